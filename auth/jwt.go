@@ -9,6 +9,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/clock"
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/database"
 	"goyave.dev/goyave/v3/lang"
@@ -27,6 +28,9 @@ func init() {
 	registerKeyConfigEntry("auth.jwt.rsa.password")
 	registerKeyConfigEntry("auth.jwt.ecdsa.public")
 	registerKeyConfigEntry("auth.jwt.ecdsa.private")
+
+	// Validate "exp" and "nbf" claims using the framework's clock.
+	jwt.TimeFunc = clock.Now
 }
 
 func registerKeyConfigEntry(name string) {
@@ -70,7 +74,7 @@ func GenerateToken(username interface{}) (string, error) {
 // `nbf` and `exp` can be overridden if they are set in the `claims` parameter.
 func GenerateTokenWithClaims(claims jwt.MapClaims, signingMethod jwt.SigningMethod) (string, error) {
 	expiry := time.Duration(config.GetInt("auth.jwt.expiry")) * time.Second
-	now := time.Now()
+	now := clock.Now()
 	customClaims := jwt.MapClaims{
 		"nbf": now.Unix(),             // Not Before
 		"exp": now.Add(expiry).Unix(), // Expiry
@@ -0,0 +1,86 @@
+package goyave
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+var (
+	httpClient   *http.Client
+	httpClientMu sync.Mutex
+)
+
+// HTTPClient returns the shared HTTP client your application should use
+// for outbound requests, such as calls to third-party APIs, instead of
+// "http.DefaultClient".
+//
+// The client is created on first use from the configuration:
+// the "server.httpClientTimeout" entry defines the timeout in seconds
+// (0 means no timeout) and the optional "server.httpClientProxy" entry
+// defines the URL of the proxy to use. Otherwise, the proxy is taken
+// from the environment ("HTTP_PROXY", "HTTPS_PROXY" and "NO_PROXY").
+//
+// Don't keep a reference to the returned client: call this function every
+// time you need it, so the client can be replaced with "SetHTTPClient()"
+// or "SetHTTPTransport()".
+func HTTPClient() *http.Client {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+	if httpClient == nil {
+		httpClient = newHTTPClient()
+	}
+	return httpClient
+}
+
+// SetHTTPClient replace the client returned by "HTTPClient()", for
+// customization (retries, custom transport, etc) or tests.
+// Pass nil to create a new client from the configuration on next use.
+func SetHTTPClient(client *http.Client) {
+	httpClientMu.Lock()
+	httpClient = client
+	httpClientMu.Unlock()
+}
+
+// SetHTTPTransport replace the transport of the client returned by "HTTPClient()",
+// keeping its other settings such as the timeout. This can be used to add
+// retries or instrumentation to all outbound requests. Pass nil to restore
+// the transport built from the configuration.
+func SetHTTPTransport(transport http.RoundTripper) {
+	setHTTPTransport(transport)
+}
+
+// setHTTPTransport replace the transport of the shared client and returns
+// the previous client, which is nil if it wasn't created yet.
+func setHTTPTransport(transport http.RoundTripper) *http.Client {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+	previous := httpClient
+	client := newHTTPClient()
+	if previous != nil {
+		c := *previous
+		client = &c
+	}
+	if transport == nil {
+		transport = newHTTPClient().Transport
+	}
+	client.Transport = transport
+	httpClient = client
+	return previous
+}
+
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Has("server.httpClientProxy") {
+		// The URL is validated when loading the config
+		proxy, _ := url.Parse(config.GetString("server.httpClientProxy"))
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return &http.Client{
+		Timeout:   time.Duration(config.GetInt("server.httpClientTimeout")) * time.Second,
+		Transport: transport,
+	}
+}
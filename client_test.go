@@ -0,0 +1,124 @@
+package goyave
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+type HTTPClientTestSuite struct {
+	TestSuite
+}
+
+func (suite *HTTPClientTestSuite) SetupTest() {
+	SetHTTPClient(nil)
+}
+
+func (suite *HTTPClientTestSuite) TestHTTPClient() {
+	client := HTTPClient()
+	suite.NotNil(client)
+	suite.Same(client, HTTPClient())
+	suite.Equal(30*time.Second, client.Timeout)
+	suite.NotSame(http.DefaultTransport, client.Transport)
+}
+
+func (suite *HTTPClientTestSuite) TestHTTPClientProxy() {
+	config.Set("server.httpClientProxy", "http://proxy.example.org:3128")
+	defer config.Set("server.httpClientProxy", nil)
+
+	req := httptest.NewRequest("GET", "https://api.example.org", nil)
+	proxy, err := HTTPClient().Transport.(*http.Transport).Proxy(req)
+	suite.Nil(err)
+	if suite.NotNil(proxy) {
+		suite.Equal("http://proxy.example.org:3128", proxy.String())
+	}
+}
+
+func (suite *HTTPClientTestSuite) TestHTTPClientTimeout() {
+	config.Set("server.httpClientTimeout", 1)
+	defer config.Set("server.httpClientTimeout", 30)
+	suite.Equal(time.Second, HTTPClient().Timeout)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := HTTPClient().Get(server.URL)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	suite.NotNil(err)
+	if e, ok := err.(net.Error); suite.True(ok) {
+		suite.True(e.Timeout())
+	}
+	suite.Less(int64(time.Since(start)), int64(2*time.Second))
+}
+
+func (suite *HTTPClientTestSuite) TestSetHTTPClient() {
+	client := &http.Client{Timeout: time.Minute}
+	SetHTTPClient(client)
+	suite.Same(client, HTTPClient())
+
+	SetHTTPClient(nil)
+	suite.NotSame(client, HTTPClient())
+	suite.Equal(30*time.Second, HTTPClient().Timeout)
+}
+
+func (suite *HTTPClientTestSuite) TestSetHTTPTransport() {
+	stub := &HTTPStub{handlers: map[string]http.HandlerFunc{}}
+	SetHTTPTransport(stub) // Client not created yet
+	suite.Same(stub, HTTPClient().Transport)
+	suite.Equal(30*time.Second, HTTPClient().Timeout)
+
+	client := &http.Client{Timeout: time.Minute}
+	SetHTTPClient(client)
+	SetHTTPTransport(stub)
+	suite.Same(stub, HTTPClient().Transport)
+	suite.Equal(time.Minute, HTTPClient().Timeout)
+	suite.Nil(client.Transport) // The original client is not modified
+
+	SetHTTPTransport(nil)
+	suite.IsType(&http.Transport{}, HTTPClient().Transport)
+	suite.Equal(time.Minute, HTTPClient().Timeout)
+}
+
+func (suite *HTTPClientTestSuite) TestStubConcurrency() {
+	// Stubbing and restoring must not deadlock when used concurrently
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			suite.StubHTTPClient()
+			SetHTTPTransport(nil)
+			suite.RestoreHTTPClient()
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		suite.RestoreHTTPClient()
+		HTTPClient()
+		suite.StubHTTPClient()
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		suite.Fail("Deadlock")
+	}
+	suite.RestoreHTTPClient()
+}
+
+func (suite *HTTPClientTestSuite) TearDownTest() {
+	SetHTTPClient(nil)
+}
+
+func TestHTTPClientTestSuite(t *testing.T) {
+	RunTest(t, new(HTTPClientTestSuite))
+}
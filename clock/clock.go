@@ -0,0 +1,76 @@
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time and timers. Time-dependent features of
+// the framework (rate limiting, sessions, JWT expiry, scheduled tasks,
+// signed URLs) use the global clock instead of calling the "time" package
+// directly, so they can be tested deterministically using a "Fake" clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the
+	// current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real the default clock, using the system time.
+type Real struct{}
+
+// Now returns "time.Now()".
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After returns "time.After(d)".
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// holder wraps the global clock so it always has the same concrete
+// type when stored in the atomic value.
+type holder struct {
+	clock Clock
+}
+
+var current atomic.Value
+
+func init() {
+	Reset()
+}
+
+// Set replace the global clock. Use this in tests to install a "Fake" clock,
+// and don't forget to call "Reset()" after the test.
+func Set(clock Clock) {
+	current.Store(holder{clock})
+}
+
+// Reset restore the default "Real" global clock.
+func Reset() {
+	Set(Real{})
+}
+
+// Get returns the global clock.
+func Get() Clock {
+	return current.Load().(holder).clock
+}
+
+// Now returns the current time according to the global clock.
+func Now() time.Time {
+	return Get().Now()
+}
+
+// After waits for the duration to elapse according to the global
+// clock and then sends the current time on the returned channel.
+func After(d time.Duration) <-chan time.Time {
+	return Get().After(d)
+}
+
+// Since returns the time elapsed since t according to the global clock.
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
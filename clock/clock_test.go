@@ -0,0 +1,96 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealClock(t *testing.T) {
+	assert.Equal(t, Real{}, Get())
+	before := time.Now()
+	now := Now()
+	assert.False(t, now.Before(before))
+	assert.True(t, Since(before) >= 0)
+
+	select {
+	case <-After(time.Millisecond):
+	case <-time.After(time.Second):
+		assert.Fail(t, "Timer didn't fire")
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	defer Reset()
+	start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	Set(fake)
+	assert.Same(t, fake, Get())
+	assert.Equal(t, start, Now())
+
+	fake.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), Now())
+	assert.Equal(t, time.Hour, Since(start))
+
+	Reset()
+	assert.Equal(t, Real{}, Get())
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	immediate := fake.After(0)
+	select {
+	case v := <-immediate:
+		assert.Equal(t, start, v)
+	default:
+		assert.Fail(t, "Timer with zero duration didn't fire immediately")
+	}
+
+	short := fake.After(time.Second)
+	long := fake.After(time.Minute)
+	fake.BlockUntil(2)
+
+	fake.Advance(500 * time.Millisecond)
+	assert.Len(t, short, 0)
+	assert.Len(t, long, 0)
+
+	fake.Advance(500 * time.Millisecond)
+	select {
+	case v := <-short:
+		assert.Equal(t, start.Add(time.Second), v)
+	default:
+		assert.Fail(t, "Timer didn't fire")
+	}
+	assert.Len(t, long, 0)
+	assert.Len(t, fake.timers, 1)
+
+	fake.Advance(time.Hour)
+	select {
+	case v := <-long:
+		assert.Equal(t, start.Add(time.Second+time.Hour), v)
+	default:
+		assert.Fail(t, "Timer didn't fire")
+	}
+	assert.Empty(t, fake.timers)
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	fake := NewFake(time.Now())
+	done := make(chan struct{})
+	go func() {
+		fake.BlockUntil(1)
+		close(done)
+	}()
+
+	c := fake.After(time.Second)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "BlockUntil didn't return")
+	}
+	fake.Advance(time.Second)
+	assert.Len(t, c, 1)
+}
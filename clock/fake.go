@@ -0,0 +1,81 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake a clock which only moves forward when told to, for tests.
+// Timers created with "After" fire when the clock is advanced past
+// their deadline.
+type Fake struct {
+	now    time.Time
+	timers []*fakeTimer
+	mu     sync.Mutex
+	cond   *sync.Cond
+}
+
+type fakeTimer struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewFake create a new fake clock set at the given time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the current time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel receiving the time once the clock is advanced
+// by at least the given duration. If the duration is negative or zero,
+// the channel receives the current time immediately.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+	f.timers = append(f.timers, &fakeTimer{deadline: f.now.Add(d), c: c})
+	f.cond.Broadcast()
+	return c
+}
+
+// Advance move the clock forward by the given duration
+// and fire the timers that reached their deadline.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	timers := f.timers[:0]
+	for _, t := range f.timers {
+		if t.deadline.After(f.now) {
+			timers = append(timers, t)
+			continue
+		}
+		t.c <- f.now
+	}
+	for i := len(timers); i < len(f.timers); i++ {
+		f.timers[i] = nil
+	}
+	f.timers = timers
+}
+
+// BlockUntil blocks until at least n timers are waiting on the clock.
+// Use it to make sure a goroutine is waiting before advancing the clock.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
@@ -27,27 +31,54 @@ type Entry struct {
 
 type readFunc func(string) (object, error)
 
+// baseConfigFile the name of the config file used when no environment
+// is set, and used as a base for environment-specific config files.
+const baseConfigFile = "config.json"
+
 var config object
 
+// ErrNotLoaded the value used to panic when the config is accessed
+// or modified before being loaded.
+var ErrNotLoaded = errors.New("Config is not loaded, call config.Load() first")
+
 var configDefaults object = object{
 	"app": object{
 		"name":            &Entry{"goyave", []interface{}{}, reflect.String, false},
 		"environment":     &Entry{"localhost", []interface{}{}, reflect.String, false},
 		"debug":           &Entry{true, []interface{}{}, reflect.Bool, false},
 		"defaultLanguage": &Entry{"en-US", []interface{}{}, reflect.String, false},
+		"key":             &Entry{nil, []interface{}{}, reflect.String, false},
 	},
 	"server": object{
-		"host":          &Entry{"127.0.0.1", []interface{}{}, reflect.String, false},
-		"domain":        &Entry{"", []interface{}{}, reflect.String, false},
-		"protocol":      &Entry{"http", []interface{}{"http", "https"}, reflect.String, false},
-		"port":          &Entry{8080, []interface{}{}, reflect.Int, false},
-		"httpsPort":     &Entry{8081, []interface{}{}, reflect.Int, false},
-		"timeout":       &Entry{10, []interface{}{}, reflect.Int, false},
-		"maxUploadSize": &Entry{10.0, []interface{}{}, reflect.Float64, false},
-		"maintenance":   &Entry{false, []interface{}{}, reflect.Bool, false},
+		"host":                   &Entry{"127.0.0.1", []interface{}{}, reflect.String, false},
+		"domain":                 &Entry{"", []interface{}{}, reflect.String, false},
+		"protocol":               &Entry{"http", []interface{}{"http", "https"}, reflect.String, false},
+		"port":                   &Entry{8080, []interface{}{}, reflect.Int, false},
+		"httpsPort":              &Entry{8081, []interface{}{}, reflect.Int, false},
+		"timeout":                &Entry{10, []interface{}{}, reflect.Int, false},
+		"readTimeout":            &Entry{nil, []interface{}{}, reflect.Int, false},
+		"readHeaderTimeout":      &Entry{nil, []interface{}{}, reflect.Int, false},
+		"stuckConnectionTimeout": &Entry{0, []interface{}{}, reflect.Int, false},
+		"maxUploadSize":          &Entry{10.0, []interface{}{}, reflect.Float64, false},
+		"maxResponseBufferSize":  &Entry{1.0, []interface{}{}, reflect.Float64, false},
+		"cleanupUploadedFiles":   &Entry{false, []interface{}{}, reflect.Bool, false},
+		"uploadTempDir":          &Entry{nil, []interface{}{}, reflect.String, false},
+		"maintenance":            &Entry{false, []interface{}{}, reflect.Bool, false},
+		"validationErrorStatus":  &Entry{422, []interface{}{400, 422}, reflect.Int, false},
+		"jsonUseNumber":          &Entry{false, []interface{}{}, reflect.Bool, false},
+		"maxJSONDepth":           &Entry{64, []interface{}{}, reflect.Int, false},
+		"httpClientTimeout":      &Entry{30, []interface{}{}, reflect.Int, false},
+		"httpClientProxy":        &Entry{nil, []interface{}{}, reflect.String, false},
+		"trustedProxies":         &Entry{[]string{}, []interface{}{}, reflect.String, true},
+		"basePath":               &Entry{"", []interface{}{}, reflect.String, false},
+		"httpsRedirect":          &Entry{true, []interface{}{}, reflect.Bool, false},
+		"httpsRedirectTarget":    &Entry{nil, []interface{}{}, reflect.String, false},
 		"tls": object{
-			"cert": &Entry{nil, []interface{}{}, reflect.String, false},
-			"key":  &Entry{nil, []interface{}{}, reflect.String, false},
+			"cert":         &Entry{nil, []interface{}{}, reflect.String, false},
+			"key":          &Entry{nil, []interface{}{}, reflect.String, false},
+			"certificates": &Entry{nil, []interface{}{}, reflect.Interface, true},
+			"clientAuth":   &Entry{"none", []interface{}{"none", "request", "require", "verifyIfGiven", "requireAndVerify"}, reflect.String, false},
+			"clientCA":     &Entry{nil, []interface{}{}, reflect.String, false},
 		},
 	},
 	"database": object{
@@ -112,11 +143,24 @@ func Register(key string, entry Entry) {
 // - "production": "config.production.json"
 // - "test": "config.test.json"
 // - By default: "config.json"
+//
+// When an environment-specific file is picked and a "config.json" file
+// exists too, the latter is used as a base: the environment-specific file
+// is deeply merged on top of it. This way, environment-specific files only
+// need to contain the entries that differ from the base config.
+// An error is returned if the environment-specific file doesn't exist.
+//
+// If a file system has been set with "SetFS", the files are read from it
+// instead of the working directory.
+//
+// If the config is invalid, the returned error is a "*config.Error"
+// listing all the problems found.
 func Load() error {
-	return LoadFrom(getConfigFilePath())
+	return load(readLayeredConfigFiles, getConfigFilePath())
 }
 
 // LoadFrom loads a config file from the given path.
+// If a file system has been set with "SetFS", the file is read from it.
 func LoadFrom(path string) error {
 	return load(readConfigFile, path)
 }
@@ -131,7 +175,7 @@ func LoadFrom(path string) error {
 //
 //  func main() {
 //  	if err := config.LoadJSON(cfg); err != nil {
-//  		goyave.ErrLogger.Println(err)
+//  		goyave.GetLogger().Errorf("%v", err)
 //  		os.Exit(goyave.ExitInvalidConfig)
 //  	}
 //
@@ -146,6 +190,7 @@ func LoadJSON(cfg string) error {
 func load(readFunc readFunc, source string) error {
 	mutex.Lock()
 	defer mutex.Unlock()
+	invalidateSnapshot()
 	config = make(object, len(configDefaults))
 	loadDefaults(configDefaults, config)
 
@@ -160,14 +205,56 @@ func load(readFunc readFunc, source string) error {
 		return err
 	}
 
-	if err := config.validate(""); err != nil {
+	if err := validateConfig(config); err != nil {
 		config = nil
-		return fmt.Errorf("Invalid config:%s", err.Error())
+		return err
 	}
 
 	return nil
 }
 
+// Condition function telling if a required entry must be set, depending on
+// the value of other entries. These values can be read using the given
+// lookup function, which returns false if the requested entry is not set.
+type Condition func(lookup func(key string) (interface{}, bool)) bool
+
+var requiredEntries = map[string]Condition{
+	"app.name": nil,
+}
+
+// Require marks the entry identified by the given key as required: loading the
+// config fails if this entry is not set (missing or "null") and the given condition
+// returns true. If the condition is nil, the entry is always required.
+//
+// Conditions are useful for entries that are only needed with a specific setup:
+//  config.Require("database.host", func(lookup func(string) (interface{}, bool)) bool {
+//  	connection, _ := lookup("database.connection")
+//  	return connection != "none"
+//  })
+func Require(key string, condition Condition) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	requiredEntries[key] = condition
+}
+
+func validateConfig(conf object) error {
+	problems := conf.validateEntries("", []*Problem{})
+	lookupFunc := func(key string) (interface{}, bool) {
+		return lookup(conf, key)
+	}
+	for key, condition := range requiredEntries {
+		if _, ok := lookup(conf, key); !ok && (condition == nil || condition(lookupFunc)) {
+			problems = append(problems, &Problem{Key: key, Err: fmt.Errorf("%q is required", key)})
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	err := &Error{Problems: problems}
+	err.sort()
+	return err
+}
+
 // IsLoaded returns true if the config have been loaded.
 func IsLoaded() bool {
 	mutex.RLock()
@@ -179,6 +266,7 @@ func IsLoaded() bool {
 // DANGEROUS, should only be used for testing.
 func Clear() {
 	mutex.Lock()
+	invalidateSnapshot()
 	config = nil
 	mutex.Unlock()
 }
@@ -196,9 +284,14 @@ func get(key string) (interface{}, bool) {
 	mutex.RLock()
 	defer mutex.RUnlock()
 	if config == nil {
-		panic("Config is not loaded")
+		panic(ErrNotLoaded)
 	}
-	currentCategory := config
+	return lookup(config, key)
+}
+
+// lookup the value of the entry identified by the given key in the given
+// category. This function doesn't lock the config.
+func lookup(currentCategory object, key string) (interface{}, bool) {
 	b := 0
 	e := strings.Index(key, ".")
 	if e == -1 {
@@ -332,8 +425,9 @@ func Set(key string, value interface{}) {
 	mutex.Lock()
 	defer mutex.Unlock()
 	if config == nil {
-		panic("Config is not loaded")
+		panic(ErrNotLoaded)
 	}
+	invalidateSnapshot()
 	category, entryKey, exists := walk(config, key)
 	if exists {
 		entry := category[entryKey].(*Entry)
@@ -466,7 +560,7 @@ func override(src object, dst object) error {
 				dst[k] = make(object, len(obj))
 			} else if _, ok := dstObj.(object); !ok {
 				// Conflict: destination is not a category
-				return fmt.Errorf("Invalid config:\n\t- Cannot override entry %q with a category", k)
+				return newError(k, fmt.Errorf("Cannot override entry %q with a category", k))
 			}
 			if err := override(obj, dst[k].(object)); err != nil {
 				return err
@@ -475,7 +569,7 @@ func override(src object, dst object) error {
 			e, ok := entry.(*Entry)
 			if !ok {
 				// Conflict: override category with an entry
-				return fmt.Errorf("Invalid config:\n\t- Cannot override category %q with an entry", k)
+				return newError(k, fmt.Errorf("Cannot override category %q with an entry", k))
 			}
 			e.Value = v
 		} else {
@@ -501,7 +595,7 @@ func makeEntryFromValue(value interface{}) *Entry {
 
 func readConfigFile(file string) (object, error) {
 	conf := make(object, len(configDefaults))
-	configFile, err := os.Open(file)
+	configFile, err := openFile(file)
 
 	if err == nil {
 		defer configFile.Close()
@@ -511,6 +605,56 @@ func readConfigFile(file string) (object, error) {
 	return conf, err
 }
 
+// readLayeredConfigFiles reads the given config file and merges it on
+// top of the base config file ("config.json"), if it exists.
+// An error wrapping "os.ErrNotExist" is returned if the environment-specific
+// file doesn't exist, so a misspelled "GOYAVE_ENV" is not silently ignored.
+func readLayeredConfigFiles(file string) (object, error) {
+	if file == baseConfigFile {
+		return readConfigFile(file)
+	}
+
+	exists, err := fileExists(file)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("Config file %q for environment %q (GOYAVE_ENV) not found: %w", file, os.Getenv("GOYAVE_ENV"), os.ErrNotExist)
+	}
+
+	exists, err = fileExists(baseConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return readConfigFile(file)
+	}
+
+	base, err := readConfigFile(baseConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := readConfigFile(file)
+	if err != nil {
+		return nil, err
+	}
+	mergeRaw(base, conf)
+	return base, nil
+}
+
+// mergeRaw deeply merges the "src" raw config into "dst".
+func mergeRaw(dst map[string]interface{}, src map[string]interface{}) {
+	for k, v := range src {
+		srcObj, srcIsObj := v.(map[string]interface{})
+		dstObj, dstIsObj := dst[k].(map[string]interface{})
+		if srcIsObj && dstIsObj {
+			mergeRaw(dstObj, srcObj)
+		} else {
+			dst[k] = v
+		}
+	}
+}
+
 func readString(str string) (object, error) {
 	conf := make(object, len(configDefaults))
 	if err := json.NewDecoder(strings.NewReader(str)).Decode(&conf); err != nil {
@@ -522,14 +666,23 @@ func readString(str string) (object, error) {
 func getConfigFilePath() string {
 	env := strings.ToLower(os.Getenv("GOYAVE_ENV"))
 	if env == "local" || env == "localhost" || env == "" {
-		return "config.json"
+		return baseConfigFile
 	}
 	return "config." + env + ".json"
 }
 
+// validate all the entries in this category and its sub-categories.
+// Returns a "*Error" containing all the problems found, or nil.
 func (o object) validate(key string) error {
-	message := ""
-	valid := true
+	err := &Error{Problems: o.validateEntries(key, []*Problem{})}
+	if len(err.Problems) == 0 {
+		return nil
+	}
+	err.sort()
+	return err
+}
+
+func (o object) validateEntries(key string, problems []*Problem) []*Problem {
 	for k, entry := range o {
 		var subKey string
 		if key == "" {
@@ -538,18 +691,81 @@ func (o object) validate(key string) error {
 			subKey = key + "." + k
 		}
 		if category, ok := entry.(object); ok {
-			if err := category.validate(subKey); err != nil {
-				message += err.Error()
-				valid = false
-			}
+			problems = category.validateEntries(subKey, problems)
 		} else if err := entry.(*Entry).validate(subKey); err != nil {
-			message += "\n\t- " + err.Error()
-			valid = false
+			problems = append(problems, &Problem{Key: subKey, Err: err})
 		}
 	}
+	return problems
+}
 
-	if !valid {
-		return fmt.Errorf(message)
+func newError(key string, err error) *Error {
+	return &Error{Problems: []*Problem{{Key: key, Err: err}}}
+}
+
+// entryValidators additional validation functions for entries
+// requiring more than a type check.
+var entryValidators = map[string]func(interface{}) error{
+	"app.key":                 validateAppKey,
+	"server.httpClientProxy":  validateProxyURL,
+	"server.trustedProxies":   validateCIDRs,
+	"server.basePath":         validateBasePath,
+	"server.tls.certificates": validateTLSCertificates,
+}
+
+func validateAppKey(value interface{}) error {
+	key, err := base64.StdEncoding.DecodeString(value.(string))
+	if err != nil || len(key) != 32 {
+		return fmt.Errorf("must be a base64-encoded 32 bytes key")
+	}
+	return nil
+}
+
+func validateProxyURL(value interface{}) error {
+	u, err := url.Parse(value.(string))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("must be an absolute URL")
+	}
+	return nil
+}
+
+func validateCIDRs(value interface{}) error {
+	for _, cidr := range value.([]string) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil && net.ParseIP(cidr) == nil {
+			return fmt.Errorf("must only contain IP addresses or CIDR notations (%q is invalid)", cidr)
+		}
+	}
+	return nil
+}
+
+func validateBasePath(value interface{}) error {
+	path := value.(string)
+	if path != "" && (!strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/")) {
+		return fmt.Errorf("must start with a slash and must not end with a slash")
+	}
+	return nil
+}
+
+func validateTLSCertificates(value interface{}) error {
+	list, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("must be a list of objects")
+	}
+	for _, v := range list {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("must be a list of objects")
+		}
+		for _, field := range []string{"cert", "key"} {
+			if s, ok := entry[field].(string); !ok || s == "" {
+				return fmt.Errorf("elements must have a %q string", field)
+			}
+		}
+		if host, ok := entry["host"]; ok {
+			if _, ok := host.(string); !ok {
+				return fmt.Errorf("elements \"host\" must be a string")
+			}
+		}
 	}
 	return nil
 }
@@ -563,6 +779,10 @@ func (e *Entry) validate(key string) error {
 		return err
 	}
 
+	if e.IsSlice {
+		e.tryInterfaceSliceConversion()
+	}
+
 	t := reflect.TypeOf(e.Value)
 	kind := t.Kind()
 	if e.IsSlice && kind == reflect.Slice {
@@ -579,7 +799,12 @@ func (e *Entry) validate(key string) error {
 
 			return fmt.Errorf(message, key, e.Type)
 		}
-		return nil
+	}
+
+	if validator, ok := entryValidators[key]; ok {
+		if err := validator(e.Value); err != nil {
+			return fmt.Errorf("%q %s", key, err.Error())
+		}
 	}
 
 	if len(e.AuthorizedValues) > 0 {
@@ -629,6 +854,44 @@ func (e *Entry) tryIntConversion(kind reflect.Kind) bool {
 	return false
 }
 
+// tryInterfaceSliceConversion converts slices decoded from JSON ("[]interface{}")
+// to a slice of the expected type if all their elements have this type.
+// Numbers are converted to int if the entry expects integers.
+func (e *Entry) tryInterfaceSliceConversion() {
+	list, ok := e.Value.([]interface{})
+	if !ok {
+		return
+	}
+	var elemType reflect.Type
+	switch e.Type {
+	case reflect.String:
+		elemType = reflect.TypeOf("")
+	case reflect.Bool:
+		elemType = reflect.TypeOf(false)
+	case reflect.Int:
+		elemType = reflect.TypeOf(0)
+	case reflect.Float64:
+		elemType = reflect.TypeOf(0.0)
+	default:
+		return
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(list))
+	for _, v := range list {
+		if f, ok := v.(float64); ok && e.Type == reflect.Int {
+			intVal, ok := e.convertInt(f)
+			if !ok {
+				return
+			}
+			v = intVal
+		}
+		if reflect.TypeOf(v) != elemType {
+			return
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(v))
+	}
+	e.Value = slice.Interface()
+}
+
 func (e *Entry) convertInt(value float64) (int, bool) {
 	intVal := int(value)
 	if value == float64(intVal) {
@@ -652,6 +915,10 @@ func (e *Entry) convertIntSlice() bool {
 }
 
 func (e *Entry) tryEnvVarConversion(key string) error {
+	if e.IsSlice {
+		return e.tryEnvVarSliceConversion(key)
+	}
+
 	str, ok := e.Value.(string)
 	if ok {
 		val, err := e.convertEnvVar(str, key)
@@ -664,6 +931,31 @@ func (e *Entry) tryEnvVarConversion(key string) error {
 	return nil
 }
 
+// tryEnvVarSliceConversion replaces the environment variable placeholders
+// found in the elements of a slice entry read from JSON. The value is left
+// untouched if one of the elements cannot be converted.
+func (e *Entry) tryEnvVarSliceConversion(key string) error {
+	list, ok := e.Value.([]interface{})
+	if !ok {
+		return nil
+	}
+	converted := make([]interface{}, 0, len(list))
+	for _, v := range list {
+		if str, ok := v.(string); ok {
+			val, err := e.convertEnvVar(str, key)
+			if err != nil {
+				return err
+			}
+			if val != nil {
+				v = val
+			}
+		}
+		converted = append(converted, v)
+	}
+	e.Value = converted
+	return nil
+}
+
 func (e *Entry) convertEnvVar(str, key string) (interface{}, error) {
 	if strings.HasPrefix(str, "${") && strings.HasSuffix(str, "}") {
 		varName := str[2 : len(str)-1]
@@ -47,3 +47,26 @@ func BenchmarkSetInt(b *testing.B) {
 		Set("server.port", 8080)
 	}
 }
+
+func BenchmarkGetStringContention(b *testing.B) {
+	setupConfigBench(b)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			GetString("app.name")
+			GetString("server.host")
+			GetInt("server.port")
+		}
+	})
+}
+
+func BenchmarkSnapshotGetStringContention(b *testing.B) {
+	setupConfigBench(b)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			snapshot := Snapshot()
+			snapshot.GetString("app.name")
+			snapshot.GetString("server.host")
+			snapshot.GetInt("server.port")
+		}
+	})
+}
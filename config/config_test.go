@@ -1,15 +1,20 @@
 package config
 
 import (
+	"embed"
+	"errors"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/suite"
-	"goyave.dev/goyave/v3/helper/filesystem"
 )
 
+//go:embed config.test.json
+var embeddedConfig embed.FS
+
 type ConfigTestSuite struct {
 	suite.Suite
 	previousEnv string
@@ -50,7 +55,7 @@ func (suite *ConfigTestSuite) TestReadConfigFile() {
 	if err != nil {
 		panic(err)
 	}
-	defer filesystem.Delete("test-forbidden.json")
+	defer os.Remove("test-forbidden.json")
 	obj, err = readConfigFile("test-forbidden.json")
 
 	suite.NotNil(err)
@@ -226,6 +231,43 @@ func (suite *ConfigTestSuite) TestOverride() {
 	suite.Equal([]interface{}{}, entry.AuthorizedValues)
 }
 
+func (suite *ConfigTestSuite) TestLoadNestedDefaults() {
+	configDefaults["connections"] = object{
+		"default": object{
+			"dbHost": &Entry{"127.0.0.1", []interface{}{}, reflect.String, false},
+			"dbPort": &Entry{3306, []interface{}{}, reflect.Int, false},
+			"options": object{
+				"timeout": &Entry{10, []interface{}{}, reflect.Int, false},
+			},
+		},
+		"replica": object{
+			"dbHost": &Entry{"127.0.0.2", []interface{}{}, reflect.String, false},
+		},
+	}
+	defer delete(configDefaults, "connections")
+	defer Clear()
+
+	err := LoadJSON(`{
+		"connections": {
+			"default": {
+				"dbHost": "db.example.org",
+				"options": {
+					"ssl": true
+				}
+			}
+		}
+	}`)
+	suite.Nil(err)
+	suite.Equal("db.example.org", GetString("connections.default.dbHost"))
+	suite.Equal(3306, GetInt("connections.default.dbPort"))
+	suite.Equal(10, GetInt("connections.default.options.timeout"))
+	suite.True(GetBool("connections.default.options.ssl"))
+	suite.Equal("127.0.0.2", GetString("connections.replica.dbHost"))
+
+	// Defaults are not modified by the override
+	suite.Equal("127.0.0.1", configDefaults["connections"].(object)["default"].(object)["dbHost"].(*Entry).Value)
+}
+
 func (suite *ConfigTestSuite) TestOverrideConflict() {
 	// conflict override entry with category (depth == 0)
 	src := object{
@@ -319,7 +361,7 @@ func (suite *ConfigTestSuite) TestLoad() {
 	if err != nil {
 		panic(err)
 	}
-	defer filesystem.Delete("config.forbidden.json")
+	defer os.Remove("config.forbidden.json")
 	if e := os.Setenv("GOYAVE_ENV", "forbidden"); e != nil {
 		panic(e)
 	}
@@ -359,6 +401,95 @@ func (suite *ConfigTestSuite) TestLoad() {
 	suite.False(IsLoaded())
 }
 
+func (suite *ConfigTestSuite) TestLoadLayered() {
+	base := `{
+		"app": {
+			"name": "layered",
+			"environment": "base"
+		},
+		"server": {
+			"port": 1234,
+			"tls": {
+				"cert": "base.crt",
+				"key": "base.key"
+			}
+		},
+		"custom": "base value"
+	}`
+	if err := ioutil.WriteFile("config.json", []byte(base), 0644); err != nil {
+		panic(err)
+	}
+	defer os.Remove("config.json")
+	overlay := `{
+		"app": {
+			"environment": "layered"
+		},
+		"server": {
+			"tls": {
+				"key": "layered.key"
+			}
+		}
+	}`
+	if err := ioutil.WriteFile("config.layered.json", []byte(overlay), 0644); err != nil {
+		panic(err)
+	}
+	defer os.Remove("config.layered.json")
+	os.Setenv("GOYAVE_ENV", "layered")
+	defer os.Setenv("GOYAVE_ENV", "test")
+
+	Clear()
+	suite.Nil(Load())
+	suite.Equal("layered", GetString("app.name"))
+	suite.Equal("layered", GetString("app.environment"))
+	suite.Equal(1234, GetInt("server.port"))
+	suite.Equal("base.crt", GetString("server.tls.cert"))
+	suite.Equal("layered.key", GetString("server.tls.key"))
+	suite.Equal("base value", GetString("custom"))
+
+	// Without environment, only the base file is loaded
+	os.Setenv("GOYAVE_ENV", "")
+	Clear()
+	suite.Nil(Load())
+	suite.Equal("base", GetString("app.environment"))
+	suite.Equal("base.key", GetString("server.tls.key"))
+
+	os.Setenv("GOYAVE_ENV", "local")
+	Clear()
+	suite.Nil(Load())
+	suite.Equal("base", GetString("app.environment"))
+
+	// The environment file is still required
+	os.Setenv("GOYAVE_ENV", "missing")
+	Clear()
+	err := Load()
+	if suite.NotNil(err) {
+		suite.Equal("Config file \"config.missing.json\" for environment \"missing\" (GOYAVE_ENV) not found: file does not exist", err.Error())
+		suite.True(errors.Is(err, os.ErrNotExist))
+	}
+	suite.False(IsLoaded())
+}
+
+func (suite *ConfigTestSuite) TestLoadMissingEnvFile() {
+	os.Setenv("GOYAVE_ENV", "prod")
+	defer os.Setenv("GOYAVE_ENV", "test")
+
+	// No base config file
+	Clear()
+	err := Load()
+	if suite.NotNil(err) {
+		suite.Equal("Config file \"config.prod.json\" for environment \"prod\" (GOYAVE_ENV) not found: file does not exist", err.Error())
+		suite.True(errors.Is(err, os.ErrNotExist))
+	}
+	suite.Nil(config)
+	suite.False(IsLoaded())
+
+	// Valid environment
+	os.Setenv("GOYAVE_ENV", "test")
+	Clear()
+	suite.Nil(Load())
+	suite.True(IsLoaded())
+}
+
 func (suite *ConfigTestSuite) TestLoadFrom() {
 	Clear()
 	err := LoadFrom("../resources/custom_config.json")
@@ -787,12 +918,24 @@ func (suite *ConfigTestSuite) TestValidateEntryWithConversion() {
 	category := object{"number": e}
 	err := category.validate("")
 	suite.NotNil(err)
-	suite.Equal("\n\t- \"number\" type must be int", err.Error())
+	suite.Equal("Invalid config:\n\t- \"number\" type must be int", err.Error())
 
 	e.Value = float64(2)
 	err = category.validate("")
 	suite.Nil(err)
 	suite.Equal(2, category["number"].(*Entry).Value)
+
+	// Authorized values are checked after conversion
+	e = &Entry{float64(404), []interface{}{400, 422}, reflect.Int, false}
+	err = e.validate("number")
+	suite.NotNil(err)
+	if err != nil {
+		suite.Equal("\"number\" must have one of the following values: [400 422]", err.Error())
+	}
+
+	e.Value = float64(400)
+	suite.Nil(e.validate("number"))
+	suite.Equal(400, e.Value)
 }
 
 func (suite *ConfigTestSuite) TestValidateEntry() {
@@ -1032,6 +1175,49 @@ func (suite *ConfigTestSuite) TestTryEnvVarConversion() {
 	suite.Equal("${}", entry.Value)
 }
 
+func (suite *ConfigTestSuite) TestTryEnvVarSliceConversion() {
+	os.Setenv("TEST_VAR", "8080")
+	defer os.Unsetenv("TEST_VAR")
+	os.Setenv("TEST_VAR_BOOL", "true")
+	defer os.Unsetenv("TEST_VAR_BOOL")
+
+	entry := &Entry{[]interface{}{"${TEST_VAR}", 2.0}, []interface{}{}, reflect.Int, true}
+	suite.Nil(entry.validate("entry"))
+	suite.Equal([]int{8080, 2}, entry.Value)
+
+	entry = &Entry{[]interface{}{"${TEST_VAR}", 2.5}, []interface{}{}, reflect.Float64, true}
+	suite.Nil(entry.validate("entry"))
+	suite.Equal([]float64{8080, 2.5}, entry.Value)
+
+	entry = &Entry{[]interface{}{"${TEST_VAR_BOOL}", false}, []interface{}{}, reflect.Bool, true}
+	suite.Nil(entry.validate("entry"))
+	suite.Equal([]bool{true, false}, entry.Value)
+
+	entry = &Entry{[]interface{}{"${TEST_VAR}", "value"}, []interface{}{}, reflect.String, true}
+	suite.Nil(entry.validate("entry"))
+	suite.Equal([]string{"8080", "value"}, entry.Value)
+
+	// Malformed value
+	entry = &Entry{[]interface{}{1.0, "${TEST_VAR_BOOL}"}, []interface{}{}, reflect.Int, true}
+	err := entry.validate("entry")
+	if suite.NotNil(err) {
+		suite.Equal("\"entry\" could not be converted to int from environment variable \"TEST_VAR_BOOL\" of value \"true\"", err.Error())
+	}
+	suite.Equal([]interface{}{1.0, "${TEST_VAR_BOOL}"}, entry.Value)
+
+	// Unset variable
+	entry = &Entry{[]interface{}{"${TEST_UNSET_VAR}"}, []interface{}{}, reflect.String, true}
+	err = entry.validate("entry")
+	if suite.NotNil(err) {
+		suite.Equal("\"entry\": \"TEST_UNSET_VAR\" environment variable is not set", err.Error())
+	}
+
+	// Typed slices are not affected
+	entry = &Entry{[]string{"${TEST_VAR}"}, []interface{}{}, reflect.String, true}
+	suite.Nil(entry.validate("entry"))
+	suite.Equal([]string{"${TEST_VAR}"}, entry.Value)
+}
+
 func (suite *ConfigTestSuite) TestSlice() {
 	entry := Entry{[]string{"val1", "val2"}, []interface{}{}, reflect.String, false}
 	suite.NotNil(entry.validate("slice"))
@@ -1072,6 +1258,31 @@ func (suite *ConfigTestSuite) TestSliceIntConversion() {
 	suite.Equal([]float64{1, 2.5}, entry.Value)
 }
 
+func (suite *ConfigTestSuite) TestSliceFromJSON() {
+	entry := Entry{[]interface{}{"val1", "val2"}, []interface{}{}, reflect.String, true}
+	suite.Nil(entry.validate("slice"))
+	suite.Equal([]string{"val1", "val2"}, entry.Value)
+
+	entry = Entry{[]interface{}{1.0, 2.0}, []interface{}{}, reflect.Int, true}
+	suite.Nil(entry.validate("slice"))
+	suite.Equal([]int{1, 2}, entry.Value)
+
+	entry = Entry{[]interface{}{true}, []interface{}{}, reflect.Bool, true}
+	suite.Nil(entry.validate("slice"))
+	suite.Equal([]bool{true}, entry.Value)
+
+	entry = Entry{[]interface{}{}, []interface{}{}, reflect.Float64, true}
+	suite.Nil(entry.validate("slice"))
+	suite.Equal([]float64{}, entry.Value)
+
+	entry = Entry{[]interface{}{"val1", 2.0}, []interface{}{}, reflect.String, true}
+	suite.NotNil(entry.validate("slice"))
+	suite.Equal([]interface{}{"val1", 2.0}, entry.Value)
+
+	entry = Entry{[]interface{}{1.0, 2.5}, []interface{}{}, reflect.Int, true}
+	suite.NotNil(entry.validate("slice"))
+}
+
 func (suite *ConfigTestSuite) TestMakeEntryFromValue() {
 	entry := makeEntryFromValue(1)
 	suite.Equal(1, entry.Value)
@@ -1086,6 +1297,35 @@ func (suite *ConfigTestSuite) TestMakeEntryFromValue() {
 	suite.Equal([]interface{}{}, entry.AuthorizedValues)
 }
 
+func (suite *ConfigTestSuite) TestLoadFromFS() {
+	defer SetFS(nil)
+	SetFS(embeddedConfig)
+
+	suite.Nil(Load())
+	suite.Equal("root level content", Get("rootLevel"))
+	suite.Equal("test", GetString("app.environment"))
+
+	suite.Nil(LoadFrom("config.test.json"))
+	suite.Equal("root level content", Get("rootLevel"))
+
+	suite.NotNil(LoadFrom("config.test_invalid.json")) // Not embedded
+
+	// Layered files
+	SetFS(fstest.MapFS{
+		"config.json":      {Data: []byte(`{"app": {"name": "base", "environment": "localhost"}}`)},
+		"config.test.json": {Data: []byte(`{"app": {"environment": "test"}}`)},
+	})
+	suite.Nil(Load())
+	suite.Equal("base", GetString("app.name"))
+	suite.Equal("test", GetString("app.environment"))
+
+	// Disk
+	SetFS(nil)
+	suite.Nil(Load())
+	suite.Equal("goyave", GetString("app.name"))
+	suite.Equal("test", GetString("app.environment"))
+}
+
 func (suite *ConfigTestSuite) TestLoadJSON() {
 	json := `
 	{
@@ -1117,6 +1357,198 @@ func (suite *ConfigTestSuite) TestLoadJSON() {
 	suite.Contains(err.Error(), "EOF")
 }
 
+func (suite *ConfigTestSuite) TestLoadError() {
+	err := LoadJSON(`{
+		"app": {
+			"environment": true,
+			"debug": "yes"
+		},
+		"server": {
+			"protocol": "ftp",
+			"port": 8080
+		}
+	}`)
+	suite.False(IsLoaded())
+	suite.NotNil(err)
+	configErr, ok := err.(*Error)
+	if suite.True(ok) {
+		suite.Equal([]string{"app.debug", "app.environment", "server.protocol"}, configErr.Keys())
+		suite.Equal("\"app.debug\" type must be bool", configErr.Problems[0].Error())
+		suite.Equal("\"app.environment\" type must be string", configErr.Problems[1].Error())
+		suite.Equal("\"server.protocol\" must have one of the following values: [http https]", configErr.Problems[2].Error())
+		suite.Equal("Invalid config:"+
+			"\n\t- \"app.debug\" type must be bool"+
+			"\n\t- \"app.environment\" type must be string"+
+			"\n\t- \"server.protocol\" must have one of the following values: [http https]", err.Error())
+	}
+
+	err = LoadJSON(`{"app": "not a category"}`)
+	configErr, ok = err.(*Error)
+	if suite.True(ok) {
+		suite.Equal([]string{"app"}, configErr.Keys())
+		suite.Equal("Invalid config:\n\t- Cannot override category \"app\" with an entry", err.Error())
+	}
+}
+
+func (suite *ConfigTestSuite) TestRequire() {
+	defer delete(requiredEntries, "custom.host")
+	Require("custom.host", func(lookup func(string) (interface{}, bool)) bool {
+		connection, _ := lookup("custom.connection")
+		return connection != "none"
+	})
+
+	err := LoadJSON(`{"app": {"name": null}, "custom": {"connection": "remote"}}`)
+	suite.False(IsLoaded())
+	if suite.NotNil(err) {
+		suite.Equal([]string{"app.name", "custom.host"}, err.(*Error).Keys())
+		suite.Equal("Invalid config:\n\t- \"app.name\" is required\n\t- \"custom.host\" is required", err.Error())
+	}
+
+	suite.Nil(LoadJSON(`{"custom": {"connection": "remote", "host": "example.org"}}`))
+	suite.Nil(LoadJSON(`{"custom": {"connection": "none"}}`))
+
+	Require("custom.host", nil)
+	err = LoadJSON(`{"custom": {"connection": "none"}}`)
+	if suite.NotNil(err) {
+		suite.Equal([]string{"custom.host"}, err.(*Error).Keys())
+	}
+}
+
+func (suite *ConfigTestSuite) TestNotLoaded() {
+	Clear()
+	suite.PanicsWithValue(ErrNotLoaded, func() { Set("app.name", "test") })
+	suite.PanicsWithValue(ErrNotLoaded, func() { Get("app.name") })
+	suite.PanicsWithValue(ErrNotLoaded, func() { GetString("app.name") })
+	suite.PanicsWithValue(ErrNotLoaded, func() { Has("app.name") })
+	suite.PanicsWithValue(ErrNotLoaded, func() { Snapshot() })
+	suite.Equal("Config is not loaded, call config.Load() first", ErrNotLoaded.Error())
+}
+
+func (suite *ConfigTestSuite) TestSnapshot() {
+	snapshot := Snapshot()
+	suite.Equal(GetString("app.name"), snapshot.GetString("app.name"))
+	suite.Equal(GetBool("app.debug"), snapshot.GetBool("app.debug"))
+	suite.Equal(GetInt("server.port"), snapshot.GetInt("server.port"))
+	suite.Equal(GetFloat("server.maxUploadSize"), snapshot.GetFloat("server.maxUploadSize"))
+	suite.Equal(Get("rootLevel"), snapshot.Get("rootLevel"))
+	suite.True(snapshot.Has("app.name"))
+	suite.False(snapshot.Has("app"))
+	suite.False(snapshot.Has("server.tls.cert")) // Unset
+	suite.False(snapshot.Has("notakey"))
+	suite.Panics(func() { snapshot.Get("notakey") })
+	suite.Panics(func() { snapshot.GetInt("app.name") })
+	suite.Panics(func() { snapshot.GetString("server.port") })
+	suite.Panics(func() { snapshot.GetBool("app.name") })
+	suite.Panics(func() { snapshot.GetFloat("app.name") })
+	suite.Panics(func() { snapshot.GetStringSlice("app.name") })
+	suite.Panics(func() { snapshot.GetBoolSlice("app.name") })
+	suite.Panics(func() { snapshot.GetIntSlice("app.name") })
+	suite.Panics(func() { snapshot.GetFloatSlice("app.name") })
+
+	// The snapshot is shared until the config changes
+	suite.Equal(snapshot, Snapshot())
+
+	Set("app.name", "changed")
+	Set("slice", []string{"a", "b"})
+	suite.Equal("goyave", snapshot.GetString("app.name"))
+	suite.False(snapshot.Has("slice"))
+
+	newSnapshot := Snapshot()
+	suite.Equal("changed", newSnapshot.GetString("app.name"))
+	suite.Equal([]string{"a", "b"}, newSnapshot.GetStringSlice("slice"))
+	GetStringSlice("slice")[0] = "modified"
+	suite.Equal([]string{"a", "b"}, newSnapshot.GetStringSlice("slice"))
+
+	Set("bools", []bool{true})
+	Set("ints", []int{1})
+	Set("floats", []float64{1.5})
+	newSnapshot = Snapshot()
+	suite.Equal([]bool{true}, newSnapshot.GetBoolSlice("bools"))
+	suite.Equal([]int{1}, newSnapshot.GetIntSlice("ints"))
+	suite.Equal([]float64{1.5}, newSnapshot.GetFloatSlice("floats"))
+
+	// Reload
+	suite.Nil(Load())
+	suite.Equal("goyave", Snapshot().GetString("app.name"))
+	suite.Equal("changed", newSnapshot.GetString("app.name"))
+
+	Clear()
+	suite.Panics(func() { Snapshot() })
+}
+
+func (suite *ConfigTestSuite) TestValidateAppKey() {
+	json := `
+	{
+		"app": {
+			"key": "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM="
+		}
+	}`
+	suite.Nil(LoadJSON(json))
+	suite.Equal("WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=", Get("app.key"))
+	Clear()
+
+	json = `
+	{
+		"app": {
+			"key": "not base64"
+		}
+	}`
+	err := LoadJSON(json)
+	suite.NotNil(err)
+	suite.Contains(err.Error(), "\"app.key\" must be a base64-encoded 32 bytes key")
+
+	json = `
+	{
+		"app": {
+			"key": "c2hvcnQga2V5"
+		}
+	}`
+	err = LoadJSON(json)
+	suite.NotNil(err)
+	suite.Contains(err.Error(), "\"app.key\" must be a base64-encoded 32 bytes key")
+
+	suite.Nil(LoadJSON(`{}`))
+	suite.Panics(func() {
+		Set("app.key", "c2hvcnQga2V5")
+	})
+	suite.False(Has("app.key"))
+}
+
+func (suite *ConfigTestSuite) TestValidateTrustedProxies() {
+	Clear()
+	suite.Nil(LoadJSON(`{"server": {"trustedProxies": ["10.0.0.0/8", "192.0.2.1", "::1"]}}`))
+	suite.Equal([]string{"10.0.0.0/8", "192.0.2.1", "::1"}, GetStringSlice("server.trustedProxies"))
+	Clear()
+
+	err := LoadJSON(`{"server": {"trustedProxies": ["10.0.0.0/8", "proxy"]}}`)
+	suite.NotNil(err)
+	suite.Contains(err.Error(), "\"server.trustedProxies\" must only contain IP addresses or CIDR notations (\"proxy\" is invalid)")
+
+	suite.Nil(LoadJSON(`{}`))
+	suite.Equal([]string{}, GetStringSlice("server.trustedProxies"))
+	suite.Panics(func() {
+		Set("server.trustedProxies", []string{"10.0.0.0/33"})
+	})
+}
+
+func (suite *ConfigTestSuite) TestValidateHTTPClientProxy() {
+	Clear()
+	suite.Nil(LoadJSON(`{"server": {"httpClientProxy": "http://proxy.example.org:3128"}}`))
+	suite.Equal("http://proxy.example.org:3128", Get("server.httpClientProxy"))
+	suite.Equal(30, GetInt("server.httpClientTimeout"))
+	Clear()
+
+	err := LoadJSON(`{"server": {"httpClientProxy": "proxy.example.org"}}`)
+	suite.NotNil(err)
+	suite.Contains(err.Error(), "\"server.httpClientProxy\" must be an absolute URL")
+
+	suite.Nil(LoadJSON(`{}`))
+	suite.Panics(func() {
+		Set("server.httpClientProxy", "://invalid")
+	})
+	suite.False(Has("server.httpClientProxy"))
+}
+
 func (suite *ConfigTestSuite) TearDownAllSuite() {
 	config = map[string]interface{}{}
 	os.Setenv("GOYAVE_ENV", suite.previousEnv)
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Error is returned when the configuration cannot be loaded because it is
+// invalid. It contains all the problems found, so they can be inspected
+// programmatically. Problems are sorted by key.
+type Error struct {
+	Problems []*Problem
+}
+
+// Problem is an issue related to a single config entry or category.
+type Problem struct {
+	Key string
+	Err error
+}
+
+func (p *Problem) Error() string {
+	return p.Err.Error()
+}
+
+func (p *Problem) Unwrap() error {
+	return p.Err
+}
+
+func (e *Error) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Invalid config:")
+	for _, p := range e.Problems {
+		builder.WriteString("\n\t- ")
+		builder.WriteString(p.Error())
+	}
+	return builder.String()
+}
+
+// Keys returns the keys of the config entries and categories
+// that have a problem.
+func (e *Error) Keys() []string {
+	keys := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		keys = append(keys, p.Key)
+	}
+	return keys
+}
+
+func (e *Error) sort() {
+	sort.SliceStable(e.Problems, func(i, j int) bool {
+		return e.Problems[i].Key < e.Problems[j].Key
+	})
+}
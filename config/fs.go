@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// fileSystem the file system config files are read from.
+// If nil, they are read from the disk.
+var fileSystem fs.FS
+
+// SetFS sets the file system config files are read from by "Load" and "LoadFrom".
+// This can be used to embed the config files in the binary. Paths are then relative
+// to the root of the given file system and use forward slashes. Passing nil
+// restores reading from the disk.
+//
+//  //go:embed config.json config.production.json
+//  var configFS embed.FS
+//
+//  func main() {
+//  	config.SetFS(configFS)
+//  	if err := config.Load(); err != nil {
+//  		// ...
+//  	}
+//  }
+func SetFS(fsys fs.FS) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	fileSystem = fsys
+}
+
+func openFile(file string) (io.ReadCloser, error) {
+	if fileSystem == nil {
+		return os.Open(file)
+	}
+	return fileSystem.Open(file)
+}
+
+// fileExists returns true if the given file exists, false if it doesn't,
+// or an error if its existence cannot be checked.
+func fileExists(file string) (bool, error) {
+	var err error
+	if fileSystem == nil {
+		_, err = os.Stat(file)
+	} else {
+		_, err = fs.Stat(fileSystem, file)
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Config is an immutable copy of the configuration, taken at a given time.
+// Reading a snapshot doesn't require any lock, making it more efficient than
+// the package-level getters in hot paths, such as request handlers reading
+// several config entries for every request.
+//
+// Unset entries are not present in the snapshot.
+type Config struct {
+	values map[string]interface{}
+}
+
+// snapshot the current snapshot, or nil if it needs to be rebuilt.
+var snapshot atomic.Value
+
+// Snapshot returns an immutable copy of the current configuration.
+// The snapshot is shared and only rebuilt after the config has been changed
+// (using "Load" or "Set" for example), so calling this function is cheap.
+// Snapshots taken before a change are not affected by it.
+//
+// Panics if the config is not loaded.
+func Snapshot() Config {
+	if s, _ := snapshot.Load().(*Config); s != nil {
+		return *s
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if config == nil {
+		panic(ErrNotLoaded)
+	}
+	s := &Config{values: make(map[string]interface{}, 64)}
+	flattenConfig(config, "", s.values)
+	snapshot.Store(s)
+	return *s
+}
+
+// invalidateSnapshot must be called every time the config is modified,
+// while holding the lock.
+func invalidateSnapshot() {
+	snapshot.Store((*Config)(nil))
+}
+
+func flattenConfig(category object, prefix string, dst map[string]interface{}) {
+	for k, v := range category {
+		key := prefix + k
+		if sub, ok := v.(object); ok {
+			flattenConfig(sub, key+".", dst)
+			continue
+		}
+		value := v.(*Entry).Value
+		if value == nil {
+			continue
+		}
+		if t := reflect.TypeOf(value); t.Kind() == reflect.Slice {
+			list := reflect.ValueOf(value)
+			cpy := reflect.MakeSlice(t, list.Len(), list.Len())
+			reflect.Copy(cpy, list)
+			value = cpy.Interface()
+		}
+		dst[key] = value
+	}
+}
+
+// Get a config entry. Panics if the entry doesn't exist.
+func (c Config) Get(key string) interface{} {
+	if val, ok := c.values[key]; ok {
+		return val
+	}
+	panic(fmt.Sprintf("Config entry \"%s\" doesn't exist", key))
+}
+
+// Has check if a config entry exists.
+func (c Config) Has(key string) bool {
+	_, ok := c.values[key]
+	return ok
+}
+
+// GetString a config entry as string.
+// Panics if entry is not a string or if it doesn't exist.
+func (c Config) GetString(key string) string {
+	str, ok := c.Get(key).(string)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not a string", key))
+	}
+	return str
+}
+
+// GetBool a config entry as bool.
+// Panics if entry is not a bool or if it doesn't exist.
+func (c Config) GetBool(key string) bool {
+	val, ok := c.Get(key).(bool)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not a bool", key))
+	}
+	return val
+}
+
+// GetInt a config entry as int.
+// Panics if entry is not an int or if it doesn't exist.
+func (c Config) GetInt(key string) int {
+	val, ok := c.Get(key).(int)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not an int", key))
+	}
+	return val
+}
+
+// GetFloat a config entry as float64.
+// Panics if entry is not a float64 or if it doesn't exist.
+func (c Config) GetFloat(key string) float64 {
+	val, ok := c.Get(key).(float64)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not a float64", key))
+	}
+	return val
+}
+
+// GetStringSlice a config entry as []string.
+// Panics if entry is not a string slice or if it doesn't exist.
+// The returned slice must not be modified.
+func (c Config) GetStringSlice(key string) []string {
+	val, ok := c.Get(key).([]string)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not a string slice", key))
+	}
+	return val
+}
+
+// GetBoolSlice a config entry as []bool.
+// Panics if entry is not a bool slice or if it doesn't exist.
+// The returned slice must not be modified.
+func (c Config) GetBoolSlice(key string) []bool {
+	val, ok := c.Get(key).([]bool)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not a bool slice", key))
+	}
+	return val
+}
+
+// GetIntSlice a config entry as []int.
+// Panics if entry is not an int slice or if it doesn't exist.
+// The returned slice must not be modified.
+func (c Config) GetIntSlice(key string) []int {
+	val, ok := c.Get(key).([]int)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not an int slice", key))
+	}
+	return val
+}
+
+// GetFloatSlice a config entry as []float64.
+// Panics if entry is not a float slice or if it doesn't exist.
+// The returned slice must not be modified.
+func (c Config) GetFloatSlice(key string) []float64 {
+	val, ok := c.Get(key).([]float64)
+	if !ok {
+		panic(fmt.Sprintf("Config entry \"%s\" is not a float64 slice", key))
+	}
+	return val
+}
@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"context"
+	"sync"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/clock"
+)
+
+// Task a periodic task registered with "Schedule".
+type Task struct {
+	expr    *Expression
+	task    func(context.Context)
+	overlap bool
+	mu      sync.Mutex
+}
+
+// Schedule a task to run periodically, following the given standard
+// cron expression (see "Parse" for the supported syntax). The time zone
+// is the local time zone of the server.
+//
+// The task runner is a goroutine managed by the server (see "goyave.Go"):
+// it is started once the server is ready and stopped on shutdown.
+// The context given to the task is cancelled when the server stops, and
+// shutdown waits for the running tasks to return.
+//
+// By default, if a run of the task is not finished when the next one
+// should start, the latter is skipped. Use "AllowOverlap()" to change
+// this behavior.
+//
+//  cron.Schedule("0 3 * * *", func(ctx context.Context) {
+//      // Nightly cleanup
+//  })
+//
+// Panics if the expression is invalid.
+func Schedule(spec string, task func(context.Context)) *Task {
+	expr, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	t := &Task{
+		expr: expr,
+		task: task,
+	}
+	goyave.Go(t.run)
+	return t
+}
+
+// AllowOverlap allow a new run of the task to start even if
+// the previous one is not finished yet.
+func (t *Task) AllowOverlap() *Task {
+	t.mu.Lock()
+	t.overlap = true
+	t.mu.Unlock()
+	return t
+}
+
+func (t *Task) allowsOverlap() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.overlap
+}
+
+func (t *Task) run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+	running := make(chan struct{}, 1)
+
+	for {
+		now := clock.Now()
+		next := t.expr.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-clock.After(next.Sub(now)):
+		}
+
+		overlap := t.allowsOverlap()
+		if !overlap {
+			select {
+			case running <- struct{}{}:
+			default:
+				continue // Previous run not finished, skip
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if !overlap {
+					<-running
+				}
+				wg.Done()
+			}()
+			t.task(ctx)
+		}()
+	}
+}
@@ -0,0 +1,132 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/clock"
+)
+
+type CronTestSuite struct {
+	goyave.TestSuite
+	clock *clock.Fake
+}
+
+func (suite *CronTestSuite) SetupTest() {
+	suite.clock = suite.UseFakeClock(time.Date(2021, time.January, 1, 10, 30, 0, 0, time.UTC))
+}
+
+func (suite *CronTestSuite) TearDownTest() {
+	suite.RestoreClock()
+}
+
+func (suite *CronTestSuite) TestSchedule() {
+	var count int32
+	var lastCtx context.Context
+	ran := make(chan struct{}, 10)
+	Schedule("*/15 * * * *", func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+		lastCtx = ctx
+		ran <- struct{}{}
+	})
+
+	suite.RunServer(func(r *goyave.Router) {}, func() {
+		for i := 0; i < 3; i++ {
+			suite.clock.BlockUntil(1)
+			suite.clock.Advance(15 * time.Minute)
+			select {
+			case <-ran:
+			case <-time.After(time.Second):
+				suite.Fail("Task didn't run")
+				return
+			}
+		}
+		suite.Equal(time.Date(2021, time.January, 1, 11, 15, 0, 0, time.UTC), suite.clock.Now())
+		suite.clock.BlockUntil(1)
+	})
+
+	suite.Equal(int32(3), atomic.LoadInt32(&count))
+	suite.NotNil(lastCtx.Err())
+
+	// The runner is stopped: advancing the clock doesn't run the task anymore.
+	suite.clock.Advance(15 * time.Minute)
+	select {
+	case <-ran:
+		suite.Fail("Task ran after shutdown")
+	case <-time.After(20 * time.Millisecond):
+	}
+	suite.Equal(int32(3), atomic.LoadInt32(&count))
+}
+
+func (suite *CronTestSuite) TestScheduleNoOverlap() {
+	started := make(chan struct{}, 10)
+	finished := false
+	Schedule("* * * * *", func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		finished = true
+	})
+
+	suite.RunServer(func(r *goyave.Router) {}, func() {
+		suite.clock.BlockUntil(1)
+		suite.clock.Advance(time.Minute)
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			suite.Fail("Task didn't run")
+			return
+		}
+
+		// The first run is not finished, the next ones are skipped.
+		suite.clock.BlockUntil(1)
+		suite.clock.Advance(time.Minute)
+		suite.clock.BlockUntil(1)
+		suite.clock.Advance(time.Minute)
+		suite.clock.BlockUntil(1)
+		select {
+		case <-started:
+			suite.Fail("Runs overlapped")
+		case <-time.After(20 * time.Millisecond):
+		}
+	})
+
+	suite.True(finished) // Shutdown waits for the running task
+}
+
+func (suite *CronTestSuite) TestScheduleAllowOverlap() {
+	started := make(chan struct{}, 10)
+	var finished int32
+	Schedule("* * * * *", func(ctx context.Context) {
+		started <- struct{}{}
+		<-ctx.Done()
+		atomic.AddInt32(&finished, 1)
+	}).AllowOverlap()
+
+	suite.RunServer(func(r *goyave.Router) {}, func() {
+		for i := 0; i < 2; i++ {
+			suite.clock.BlockUntil(1)
+			suite.clock.Advance(time.Minute)
+			select {
+			case <-started:
+			case <-time.After(time.Second):
+				suite.Fail("Task didn't run")
+				return
+			}
+		}
+	})
+
+	suite.Equal(int32(2), atomic.LoadInt32(&finished))
+}
+
+func (suite *CronTestSuite) TestScheduleInvalid() {
+	suite.Panics(func() {
+		Schedule("* * *", func(ctx context.Context) {})
+	})
+}
+
+func TestCronTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(CronTestSuite))
+}
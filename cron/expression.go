@@ -0,0 +1,215 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expression a parsed cron expression.
+type Expression struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// True if the field starts with a wildcard ("*"). Used to match days
+	// the same way as standard cron: if both day of month and day of week
+	// are restricted, a day matches if it matches either field.
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+}
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	macros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// maxSearch the maximum time span in which "Next" looks for a matching time.
+// Expressions which can never match, such as "0 0 30 2 *", stop there.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Parse a standard cron expression made of five fields:
+//
+//  ┌───────────── minute (0 - 59)
+//  │ ┌───────────── hour (0 - 23)
+//  │ │ ┌───────────── day of month (1 - 31)
+//  │ │ │ ┌───────────── month (1 - 12 or JAN-DEC)
+//  │ │ │ │ ┌───────────── day of week (0 - 7 or SUN-SAT, 0 and 7 are Sunday)
+//  │ │ │ │ │
+//  * * * * *
+//
+// Each field supports wildcards ("*"), lists ("1,15"), ranges ("1-5")
+// and steps ("*/15", "0-30/10"). The "@yearly", "@annually", "@monthly",
+// "@weekly", "@daily", "@midnight" and "@hourly" macros are supported too.
+func Parse(spec string) (*Expression, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Invalid cron expression %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	expr := &Expression{
+		dayOfMonthStar: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	if expr.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("Invalid cron expression %q: %s", spec, err)
+	}
+	if expr.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("Invalid cron expression %q: %s", spec, err)
+	}
+	if expr.dayOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("Invalid cron expression %q: %s", spec, err)
+	}
+	if expr.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("Invalid cron expression %q: %s", spec, err)
+	}
+	if expr.dayOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("Invalid cron expression %q: %s", spec, err)
+	}
+	if expr.dayOfWeek&(1<<7) != 0 {
+		// 7 is an alias for Sunday
+		expr.dayOfWeek |= 1
+	}
+	return expr, nil
+}
+
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		b, err := f.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func (f field) parsePart(part string) (uint64, error) {
+	rangePart := part
+	step := 1
+	if i := strings.IndexByte(part, '/'); i != -1 {
+		s, err := strconv.Atoi(part[i+1:])
+		if err != nil || s <= 0 {
+			return 0, fmt.Errorf("invalid step in %s field: %q", f.name, part)
+		}
+		rangePart, step = part[:i], s
+	}
+
+	start, end := f.min, f.max
+	if f.name == dayOfWeekField.name {
+		end = 6 // Don't include the Sunday alias in wildcards
+	}
+	if rangePart != "*" {
+		var err error
+		bounds := strings.SplitN(rangePart, "-", 2)
+		if start, err = f.parseValue(bounds[0]); err != nil {
+			return 0, err
+		}
+		if len(bounds) == 2 {
+			if end, err = f.parseValue(bounds[1]); err != nil {
+				return 0, err
+			}
+			if end < start {
+				return 0, fmt.Errorf("invalid range in %s field: %q", f.name, part)
+			}
+		} else if step != 1 {
+			end = f.max // "5/10" means "5-max/10"
+		} else {
+			end = start
+		}
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+func (f field) parseValue(value string) (int, error) {
+	if v, ok := f.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value in %s field: %q", f.name, value)
+	}
+	return v, nil
+}
+
+// Next returns the first time matching the expression strictly after
+// the given time. Seconds are ignored, the returned time is always
+// at the start of a minute, in the location of the given time.
+//
+// Returns the zero time if the expression doesn't match any time
+// in the next five years.
+func (e *Expression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(maxSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if e.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !e.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if e.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if e.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (e *Expression) matchDay(t time.Time) bool {
+	dom := e.dayOfMonth&(1<<uint(t.Day())) != 0
+	dow := e.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if e.dayOfMonthStar || e.dayOfWeekStar {
+		return dom && dow
+	}
+	return dom || dow
+}
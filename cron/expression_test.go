@@ -0,0 +1,92 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	expr, err := Parse("*/15 0-6/2 1,15 jan-mar MON-FRI")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1|1<<15|1<<30|1<<45), expr.minute)
+	assert.Equal(t, uint64(1|1<<2|1<<4|1<<6), expr.hour)
+	assert.Equal(t, uint64(1<<1|1<<15), expr.dayOfMonth)
+	assert.Equal(t, uint64(1<<1|1<<2|1<<3), expr.month)
+	assert.Equal(t, uint64(1<<1|1<<2|1<<3|1<<4|1<<5), expr.dayOfWeek)
+	assert.False(t, expr.dayOfMonthStar)
+	assert.False(t, expr.dayOfWeekStar)
+
+	expr, err = Parse("5/20 * * * 7")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1<<5|1<<25|1<<45), expr.minute)
+	assert.Equal(t, uint64(1|1<<7), expr.dayOfWeek)
+	assert.True(t, expr.dayOfMonthStar)
+
+	expr, err = Parse("@daily")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), expr.minute)
+	assert.Equal(t, uint64(1), expr.hour)
+	assert.Equal(t, uint64(0x7f), expr.dayOfWeek)
+
+	invalid := map[string]string{
+		"* * * *":       "Invalid cron expression \"* * * *\": expected 5 fields, got 4",
+		"60 * * * *":    "Invalid cron expression \"60 * * * *\": invalid value in minute field: \"60\"",
+		"* 24 * * *":    "Invalid cron expression \"* 24 * * *\": invalid value in hour field: \"24\"",
+		"* * 0 * *":     "Invalid cron expression \"* * 0 * *\": invalid value in day of month field: \"0\"",
+		"* * * foo *":   "Invalid cron expression \"* * * foo *\": invalid value in month field: \"foo\"",
+		"* * * * 8":     "Invalid cron expression \"* * * * 8\": invalid value in day of week field: \"8\"",
+		"*/0 * * * *":   "Invalid cron expression \"*/0 * * * *\": invalid step in minute field: \"*/0\"",
+		"30-10 * * * *": "Invalid cron expression \"30-10 * * * *\": invalid range in minute field: \"30-10\"",
+		"1,,2 * * * *":  "Invalid cron expression \"1,,2 * * * *\": invalid value in minute field: \"\"",
+	}
+	for spec, message := range invalid {
+		expr, err := Parse(spec)
+		assert.Nil(t, expr, spec)
+		if assert.NotNil(t, err, spec) {
+			assert.Equal(t, message, err.Error())
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+	from := time.Date(2021, time.January, 1, 10, 30, 45, 0, time.UTC) // Friday
+
+	cases := []struct {
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"* * * * *", from, date(2021, time.January, 1, 10, 31)},
+		{"*/15 * * * *", from, date(2021, time.January, 1, 10, 45)},
+		{"0 * * * *", from, date(2021, time.January, 1, 11, 0)},
+		{"30 10 * * *", from, date(2021, time.January, 2, 10, 30)},
+		{"0 3 * * *", from, date(2021, time.January, 2, 3, 0)},
+		{"0 0 1 * *", from, date(2021, time.February, 1, 0, 0)},
+		{"0 0 * * mon", from, date(2021, time.January, 4, 0, 0)},
+		{"0 0 * * 0", from, date(2021, time.January, 3, 0, 0)},
+		{"0 0 * * 7", from, date(2021, time.January, 3, 0, 0)},
+		{"0 0 15 * mon", from, date(2021, time.January, 4, 0, 0)}, // Either day of month or day of week
+		{"0 0 */10 * *", from, date(2021, time.January, 11, 0, 0)},
+		{"0 0 31 * *", date(2021, time.January, 31, 0, 0), date(2021, time.March, 31, 0, 0)},
+		{"0 0 29 2 *", from, date(2024, time.February, 29, 0, 0)},
+		{"@yearly", from, date(2022, time.January, 1, 0, 0)},
+		{"59 23 31 12 *", date(2021, time.December, 31, 23, 59), date(2022, time.December, 31, 23, 59)},
+	}
+
+	for _, c := range cases {
+		expr, err := Parse(c.spec)
+		if !assert.Nil(t, err, c.spec) {
+			continue
+		}
+		assert.Equal(t, c.expected, expr.Next(c.from), c.spec)
+	}
+
+	expr, err := Parse("0 0 30 2 *")
+	assert.Nil(t, err)
+	assert.True(t, expr.Next(from).IsZero())
+}
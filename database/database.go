@@ -30,6 +30,7 @@ type dialect struct {
 
 var (
 	dbConnection *gorm.DB
+	override     *gorm.DB
 	mu           sync.Mutex
 	models       []interface{}
 	initializers []Initializer
@@ -45,6 +46,27 @@ var (
 	}
 )
 
+func init() {
+	for placeholder, key := range optionPlaceholders {
+		config.Require(key, requiredByDialect(placeholder))
+	}
+	config.Require("database.port", requiredByDialect("{port}"))
+}
+
+// requiredByDialect returns a config condition making an entry required
+// if the selected database connection's DSN template uses the given placeholder.
+func requiredByDialect(placeholder string) config.Condition {
+	return func(lookup func(string) (interface{}, bool)) bool {
+		connection, _ := lookup("database.connection")
+		driver, _ := connection.(string)
+		if driver == "none" {
+			return false
+		}
+		dialect, ok := dialects[driver]
+		return ok && strings.Contains(dialect.template, placeholder)
+	}
+}
+
 // GetConnection returns the global database connection pool.
 // Creates a new connection pool if no connection is available.
 //
@@ -53,6 +75,9 @@ var (
 func GetConnection() *gorm.DB {
 	mu.Lock()
 	defer mu.Unlock()
+	if override != nil {
+		return override
+	}
 	if dbConnection == nil {
 		dbConnection = newConnection()
 	}
@@ -64,16 +89,35 @@ func Conn() *gorm.DB {
 	return GetConnection()
 }
 
+// SetConnection replace the connection returned by "GetConnection()" with
+// the given one, for example a transaction. Pass nil to restore
+// the global connection pool.
+//
+// This is mainly used by the test suite to run each test inside a
+// transaction. While a connection is set, "Close()" has no effect, as the
+// given connection may still be using the global connection pool.
+func SetConnection(db *gorm.DB) {
+	mu.Lock()
+	override = db
+	mu.Unlock()
+	clearSoftDeleteCache()
+}
+
 // Close the database connections if they exist.
+// Has no effect while a connection set with "SetConnection()" is in use.
 func Close() error {
 	var err error = nil
 	mu.Lock()
 	defer mu.Unlock()
+	if override != nil {
+		return nil
+	}
 	if dbConnection != nil {
 		db, _ := dbConnection.DB()
 		err = db.Close()
 		dbConnection = nil
 	}
+	clearSoftDeleteCache()
 
 	return err
 }
@@ -122,6 +166,7 @@ func Migrate() {
 			panic(err)
 		}
 	}
+	clearSoftDeleteCache()
 }
 
 // RegisterDialect registers a connection string template for the given dialect.
@@ -196,9 +241,13 @@ func newConnection() *gorm.DB {
 func (d dialect) buildDSN() string {
 	connStr := d.template
 	for k, v := range optionPlaceholders {
-		connStr = strings.Replace(connStr, k, config.GetString(v), 1)
+		if strings.Contains(connStr, k) {
+			connStr = strings.Replace(connStr, k, config.GetString(v), 1)
+		}
+	}
+	if strings.Contains(connStr, "{port}") {
+		connStr = strings.Replace(connStr, "{port}", strconv.Itoa(config.GetInt("database.port")), 1)
 	}
-	connStr = strings.Replace(connStr, "{port}", strconv.Itoa(config.GetInt("database.port")), 1)
 
 	return connStr
 }
@@ -53,6 +53,21 @@ func (suite *DatabaseTestSuite) TestGetConnection() {
 	Close()
 }
 
+func (suite *DatabaseTestSuite) TestSetConnection() {
+	db := GetConnection()
+	tx := db.Begin()
+	SetConnection(tx)
+	suite.Same(tx, GetConnection())
+	suite.Nil(Close()) // No effect while a connection is set
+	suite.Same(db, dbConnection)
+
+	suite.Nil(tx.Rollback().Error)
+	SetConnection(nil)
+	suite.Same(db, GetConnection())
+	suite.Nil(Close())
+	suite.Nil(dbConnection)
+}
+
 func (suite *DatabaseTestSuite) TestLogLevel() {
 	db := GetConnection()
 	suite.Equal(logger.Default.LogMode(logger.Silent), db.Logger)
@@ -178,6 +193,32 @@ func (suite *DatabaseTestSuite) TearDownAllSuite() {
 	os.Setenv("GOYAVE_ENV", suite.previousEnv)
 }
 
+func (suite *DatabaseTestSuite) TestRequiredConfig() {
+	defer func() {
+		if err := config.Load(); err != nil {
+			suite.FailNow(err.Error())
+		}
+	}()
+
+	err := config.LoadJSON(`{"database": {"connection": "mysql", "host": null, "name": null}}`)
+	if suite.NotNil(err) {
+		suite.Equal([]string{"database.host", "database.name"}, err.(*config.Error).Keys())
+		suite.Equal("Invalid config:\n\t- \"database.host\" is required\n\t- \"database.name\" is required", err.Error())
+	}
+
+	suite.Nil(config.LoadJSON(`{"database": {"connection": "none", "host": null, "name": null}}`))
+
+	RegisterDialect("nohost", "file:{name}", nil)
+	defer delete(dialects, "nohost")
+	suite.Nil(config.LoadJSON(`{"database": {"connection": "nohost", "host": null, "port": null}}`))
+	suite.Equal("file:goyave", dialects["nohost"].buildDSN())
+
+	err = config.LoadJSON(`{"database": {"connection": "nohost", "name": null}}`)
+	if suite.NotNil(err) {
+		suite.Equal([]string{"database.name"}, err.(*config.Error).Keys())
+	}
+}
+
 func TestDatabaseTestSuite(t *testing.T) {
 	// Ensure this test is running with a working database service running
 	// in the background. Running "run_test.sh" runs a mariadb container.
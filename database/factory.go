@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/imdario/mergo"
@@ -15,6 +16,43 @@ type Factory struct {
 	override  interface{}
 }
 
+var factories = map[reflect.Type]Generator{}
+
+// RegisterFactory registers the generator used for the given model, so
+// factories for this model can be obtained with "GetFactory()".
+// The model can be a struct or a pointer to a struct.
+//  func init() {
+//      database.RegisterFactory(&User{}, UserGenerator)
+//  }
+func RegisterFactory(model interface{}, generator Generator) {
+	factories[modelType(model)] = generator
+}
+
+// GetFactory create a new Factory using the generator registered for
+// the given model with "RegisterFactory()".
+// Panics if no generator is registered for this model.
+func GetFactory(model interface{}) *Factory {
+	t := modelType(model)
+	generator, ok := factories[t]
+	if !ok {
+		panic(fmt.Errorf("No factory registered for model %q", t.String()))
+	}
+	return NewFactory(generator)
+}
+
+// ClearFactories unregister all factories.
+func ClearFactories() {
+	factories = map[reflect.Type]Generator{}
+}
+
+func modelType(model interface{}) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
 // NewFactory create a new Factory.
 // The given generator function will be used to generate records.
 func NewFactory(generator Generator) *Factory {
@@ -106,6 +106,24 @@ func (suite *FactoryTestSuite) TestSave() {
 	})
 }
 
+func (suite *FactoryTestSuite) TestRegisterFactory() {
+	defer ClearFactories()
+	RegisterFactory(&User{}, userGenerator)
+
+	factory := GetFactory(&User{})
+	suite.NotNil(factory)
+	records := factory.Generate(1).([]*User)
+	suite.Equal("John Doe", records[0].Name)
+
+	suite.NotNil(GetFactory(User{}))
+	suite.PanicsWithError("No factory registered for model \"database.NoTable\"", func() {
+		GetFactory(&NoTable{})
+	})
+
+	ClearFactories()
+	suite.Empty(factories)
+}
+
 func (suite *FactoryTestSuite) TearDownAllSuite() {
 	os.Setenv("GOYAVE_ENV", suite.previousEnv)
 }
@@ -0,0 +1,39 @@
+package database
+
+import "fmt"
+
+// Seeder a function populating the database, usually using factories.
+type Seeder func()
+
+var seeders = map[string]Seeder{}
+
+// RegisterSeeder registers a seeder under the given name, so it can be
+// executed with "Seed()". When writing a seeder, you should always
+// register it in the init() function.
+//  func init() {
+//      database.RegisterSeeder("users", func() {
+//          database.NewFactory(UserGenerator).Save(10)
+//      })
+//  }
+//
+// Registering a seeder with an existing name replaces the previous one.
+func RegisterSeeder(name string, seeder Seeder) {
+	seeders[name] = seeder
+}
+
+// ClearSeeders unregister all seeders.
+func ClearSeeders() {
+	seeders = map[string]Seeder{}
+}
+
+// Seed executes the seeders registered with the given names, in order.
+// Panics if a seeder doesn't exist.
+func Seed(names ...string) {
+	for _, name := range names {
+		seeder, ok := seeders[name]
+		if !ok {
+			panic(fmt.Errorf("Seeder %q doesn't exist", name))
+		}
+		seeder()
+	}
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeed(t *testing.T) {
+	defer ClearSeeders()
+	executed := []string{}
+	RegisterSeeder("users", func() {
+		executed = append(executed, "users")
+	})
+	RegisterSeeder("articles", func() {
+		executed = append(executed, "articles")
+	})
+
+	Seed("articles", "users")
+	assert.Equal(t, []string{"articles", "users"}, executed)
+
+	RegisterSeeder("users", func() {
+		executed = append(executed, "users override")
+	})
+	Seed("users")
+	assert.Equal(t, []string{"articles", "users", "users override"}, executed)
+
+	assert.PanicsWithError(t, "Seeder \"unknown\" doesn't exist", func() {
+		Seed("unknown")
+	})
+
+	ClearSeeders()
+	assert.Empty(t, seeders)
+}
@@ -1,6 +1,11 @@
 package database
 
 import (
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v3/helper"
 	"goyave.dev/goyave/v3/validation"
 )
 
@@ -11,17 +16,152 @@ func init() {
 		Function:           validateUnique,
 		RequiredParameters: 1,
 	})
+	validation.AddRule("exists", &validation.RuleDefinition{
+		Function:           validateExists,
+		RequiredParameters: 1,
+	})
 }
 
+// softDeleteColumn the column used by GORM for soft deletes.
+// Soft-deleted records are ignored by the database rules.
+const softDeleteColumn = "deleted_at"
+
+// softDeleteTables caches whether the tables used by the database rules have
+// a soft delete column, so the schema is not queried on every validation.
+// The cache is cleared when the connection is closed or replaced, and after
+// migrations.
+var softDeleteTables sync.Map
+
+func clearSoftDeleteCache() {
+	softDeleteTables.Range(func(key, value interface{}) bool {
+		softDeleteTables.Delete(key)
+		return true
+	})
+}
+
+// validateUnique checks no record has the field's value in the given table.
+//  unique:table[,column[,except[,idColumn]]]
+// The column defaults to the name of the field. If "except" is given, the
+// record having this ID is ignored, which is useful when updating a record.
+// The ID column defaults to "id".
 func validateUnique(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
+	if value == nil {
+		return true
+	}
 	column := field
 	if len(parameters) >= 2 {
 		column = parameters[1]
 	}
 
+	query := newRuleQuery(parameters[0], column, value)
+	if len(parameters) >= 3 {
+		idColumn := "id"
+		if len(parameters) >= 4 {
+			idColumn = parameters[3]
+		}
+		query = query.Where(quote(query, idColumn)+" <> ?", parameters[2])
+	}
+
 	count := int64(0)
-	if err := Conn().Table(parameters[0]).Where(column+"= ?", value).Count(&count).Error; err != nil {
+	if err := query.Count(&count).Error; err != nil {
 		panic(err)
 	}
 	return count == 0
 }
+
+// validateExists checks a record has the field's value in the given table.
+//  exists:table[,column]
+// The column defaults to the name of the field. If the value is an array,
+// all its values must exist.
+func validateExists(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
+	if value == nil {
+		return false
+	}
+	column := field
+	if len(parameters) >= 2 {
+		column = parameters[1]
+	}
+
+	expected := int64(1)
+	if values, ok := uniqueValues(value); ok {
+		if len(values) == 0 {
+			return true
+		}
+		value = values
+		expected = int64(len(values))
+	}
+
+	query := newRuleQuery(parameters[0], column, value)
+	count := int64(0)
+	row := query.Select("COUNT(DISTINCT " + quote(query, column) + ")").Row()
+	if err := row.Scan(&count); err != nil {
+		panic(err)
+	}
+	return count >= expected
+}
+
+func newRuleQuery(table, column string, value interface{}) *gorm.DB {
+	db := Conn()
+	query := db.Table(table)
+	if reflect.ValueOf(value).Kind() == reflect.Slice {
+		query = query.Where(quote(db, column)+" IN ?", value)
+	} else {
+		query = query.Where(quote(db, column)+" = ?", value)
+	}
+	if hasSoftDeleteColumn(db, table) {
+		query = query.Where(quote(db, softDeleteColumn) + " IS NULL")
+	}
+	return query
+}
+
+// hasSoftDeleteColumn checks if the given table has the soft delete column.
+// The result is cached.
+func hasSoftDeleteColumn(db *gorm.DB, table string) bool {
+	if has, ok := softDeleteTables.Load(table); ok {
+		return has.(bool)
+	}
+	has := hasColumn(db, table, softDeleteColumn)
+	softDeleteTables.Store(table, has)
+	return has
+}
+
+// hasColumn checks if the given table has the given column.
+// The schema is not needed, so this works with tables without model.
+func hasColumn(db *gorm.DB, table, column string) bool {
+	rows, err := db.Table(table).Where("1 = 0").Rows()
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		panic(err)
+	}
+	return helper.ContainsStr(columns, column)
+}
+
+func uniqueValues(value interface{}) ([]interface{}, bool) {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice {
+		return nil, false
+	}
+	values := make([]interface{}, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		v := list.Index(i).Interface()
+		duplicate := false
+		for _, existing := range values {
+			if reflect.DeepEqual(existing, v) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			values = append(values, v)
+		}
+	}
+	return values, true
+}
+
+func quote(db *gorm.DB, column string) string {
+	return db.Statement.Quote(column)
+}
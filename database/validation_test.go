@@ -6,7 +6,11 @@ import (
 
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 	"goyave.dev/goyave/v3/config"
+	"goyave.dev/goyave/v3/lang"
+	"goyave.dev/goyave/v3/validation"
 )
 
 type ValidationTestSuite struct {
@@ -51,6 +55,149 @@ func (suite *ValidationTestSuite) TestValidateUnique() {
 	})
 }
 
+type SoftDeletedUser struct {
+	DeletedAt gorm.DeletedAt
+	Email     string `gorm:"type:varchar(100)"`
+	ID        uint   `gorm:"primaryKey"`
+}
+
+type SQLiteValidationTestSuite struct {
+	suite.Suite
+	previousEnv string
+}
+
+func (suite *SQLiteValidationTestSuite) SetupSuite() {
+	if _, ok := dialects["sqlite3"]; !ok {
+		RegisterDialect("sqlite3", "file:{name}?{options}", sqlite.Open)
+	}
+	suite.previousEnv = os.Getenv("GOYAVE_ENV")
+	os.Setenv("GOYAVE_ENV", "test")
+	if err := config.Load(); err != nil {
+		suite.FailNow(err.Error())
+	}
+	config.Set("database.connection", "sqlite3")
+	config.Set("database.name", "validation_test")
+	config.Set("database.options", "mode=memory&cache=shared")
+	lang.LoadDefault()
+
+	db := Conn()
+	if err := db.AutoMigrate(&User{}, &SoftDeletedUser{}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	db.Create([]*User{
+		{ID: 1, Name: "Hugh", Email: "hugh@example.org"},
+		{ID: 2, Name: "Jane", Email: "jane@example.org"},
+	})
+	db.Create(&SoftDeletedUser{ID: 1, Email: "hugh@example.org"})
+	db.Create(&SoftDeletedUser{ID: 2, Email: "deleted@example.org"})
+	db.Delete(&SoftDeletedUser{ID: 2})
+}
+
+func (suite *SQLiteValidationTestSuite) TestValidateUnique() {
+	form := map[string]interface{}{}
+	suite.False(validateUnique("email", "hugh@example.org", []string{"users"}, form))
+	suite.False(validateUnique("mail", "hugh@example.org", []string{"users", "email"}, form))
+	suite.True(validateUnique("email", "john@example.org", []string{"users"}, form))
+	suite.True(validateUnique("email", "hugh@example.org", []string{"users", "name"}, form))
+	suite.True(validateUnique("email", nil, []string{"users"}, form))
+
+	// Except
+	suite.True(validateUnique("email", "hugh@example.org", []string{"users", "email", "1"}, form))
+	suite.False(validateUnique("email", "hugh@example.org", []string{"users", "email", "2"}, form))
+	suite.True(validateUnique("email", "hugh@example.org", []string{"users", "email", "Hugh", "name"}, form))
+
+	// Arrays
+	suite.False(validateUnique("email", []string{"john@example.org", "jane@example.org"}, []string{"users"}, form))
+	suite.True(validateUnique("email", []string{"john@example.org", "doe@example.org"}, []string{"users"}, form))
+
+	// Soft deletes
+	suite.False(validateUnique("email", "hugh@example.org", []string{"soft_deleted_users"}, form))
+	suite.True(validateUnique("email", "deleted@example.org", []string{"soft_deleted_users"}, form))
+
+	suite.Panics(func() {
+		validateUnique("email", "hugh@example.org", []string{"not_a_table"}, form)
+	})
+}
+
+func (suite *SQLiteValidationTestSuite) TestValidateExists() {
+	form := map[string]interface{}{}
+	suite.True(validateExists("id", 1, []string{"users"}, form))
+	suite.True(validateExists("user_id", 2.0, []string{"users", "id"}, form))
+	suite.False(validateExists("id", 3, []string{"users"}, form))
+	suite.True(validateExists("email", "hugh@example.org", []string{"users"}, form))
+	suite.False(validateExists("email", "john@example.org", []string{"users"}, form))
+	suite.False(validateExists("id", nil, []string{"users"}, form))
+
+	// Arrays
+	suite.True(validateExists("id", []float64{1, 2}, []string{"users"}, form))
+	suite.True(validateExists("id", []float64{1, 1}, []string{"users"}, form))
+	suite.False(validateExists("id", []float64{1, 3}, []string{"users"}, form))
+	suite.True(validateExists("id", []float64{}, []string{"users"}, form))
+
+	// Soft deletes
+	suite.True(validateExists("id", 1, []string{"soft_deleted_users"}, form))
+	suite.False(validateExists("id", 2, []string{"soft_deleted_users"}, form))
+
+	suite.Panics(func() {
+		validateExists("id", 1, []string{"not_a_table"}, form)
+	})
+}
+
+func (suite *SQLiteValidationTestSuite) TestSoftDeleteCache() {
+	clearSoftDeleteCache()
+	form := map[string]interface{}{}
+	suite.True(validateUnique("email", "deleted@example.org", []string{"soft_deleted_users"}, form))
+	suite.True(validateExists("id", 1, []string{"users"}, form))
+
+	has, ok := softDeleteTables.Load("soft_deleted_users")
+	suite.True(ok)
+	suite.Equal(true, has)
+	has, ok = softDeleteTables.Load("users")
+	suite.True(ok)
+	suite.Equal(false, has)
+
+	// The cached value is used
+	softDeleteTables.Store("users", true)
+	suite.Panics(func() {
+		validateExists("id", 1, []string{"users"}, form) // No "deleted_at" column
+	})
+
+	clearSoftDeleteCache()
+	_, ok = softDeleteTables.Load("users")
+	suite.False(ok)
+	suite.True(validateExists("id", 1, []string{"users"}, form))
+
+	// Missing tables are not cached
+	suite.Panics(func() {
+		validateExists("id", 1, []string{"not_a_table"}, form)
+	})
+	_, ok = softDeleteTables.Load("not_a_table")
+	suite.False(ok)
+}
+
+func (suite *SQLiteValidationTestSuite) TestMessages() {
+	rules := validation.RuleSet{
+		"email":   {"required", "string", "unique:users"},
+		"user_id": {"required", "numeric", "exists:users,id"},
+	}
+	data := map[string]interface{}{"email": "hugh@example.org", "user_id": 3}
+	errors := validation.Validate(data, rules, true, "en-US")
+	suite.Equal([]string{"The email address has already been taken."}, errors["email"])
+	suite.Equal([]string{"The selected user_id doesn't exist."}, errors["user_id"])
+
+	data = map[string]interface{}{"email": "john@example.org", "user_id": 1}
+	suite.Empty(validation.Validate(data, rules, true, "en-US"))
+}
+
+func (suite *SQLiteValidationTestSuite) TearDownSuite() {
+	Close()
+	os.Setenv("GOYAVE_ENV", suite.previousEnv)
+}
+
+func TestSQLiteValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLiteValidationTestSuite))
+}
+
 func (suite *ValidationTestSuite) TearDownAllSuite() {
 	os.Setenv("GOYAVE_ENV", suite.previousEnv)
 }
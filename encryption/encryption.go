@@ -0,0 +1,107 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+// KeySize the size in bytes of the application key.
+const KeySize = 32
+
+var (
+	// ErrNoKey returned when the "app.key" config entry is not set.
+	ErrNoKey = errors.New("\"app.key\" config entry is not set")
+
+	// ErrInvalidCiphertext returned by "Decrypt()" if the given ciphertext
+	// is malformed or has been tampered with.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// GenerateKey generates a new random application key, suitable for
+// the "app.key" config entry. The returned key is base64-encoded.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Key returns the decoded application key, defined by the "app.key" config entry.
+// Returns "ErrNoKey" if the config entry is not set.
+func Key() ([]byte, error) {
+	if !config.Has("app.key") {
+		return nil, ErrNoKey
+	}
+	return decodeKey(config.GetString("app.key"))
+}
+
+func decodeKey(key string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("application key must be base64-encoded: %w", err)
+	}
+	if len(decoded) != KeySize {
+		return nil, fmt.Errorf("application key must be %d bytes long, got %d", KeySize, len(decoded))
+	}
+	return decoded, nil
+}
+
+// Encrypt the given plaintext using AES-GCM and the application key.
+// The returned ciphertext is URL-safe base64-encoded and contains the
+// random nonce used for encryption.
+func Encrypt(plaintext []byte) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt a ciphertext generated by "Encrypt()".
+// Returns "ErrInvalidCiphertext" if the ciphertext is malformed, has been
+// tampered with or has been encrypted with another key.
+func Decrypt(ciphertext string) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce := data[:gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := Key()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
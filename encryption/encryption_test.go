@@ -0,0 +1,123 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"goyave.dev/goyave/v3/config"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EncryptionTestSuite) SetupTest() {
+	if err := config.LoadFrom("../config.test.json"); err != nil {
+		suite.FailNow(err.Error())
+	}
+	config.Set("app.key", "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=")
+}
+
+func (suite *EncryptionTestSuite) TearDownTest() {
+	config.Clear()
+}
+
+func (suite *EncryptionTestSuite) TestGenerateKey() {
+	key, err := GenerateKey()
+	suite.Nil(err)
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	suite.Nil(err)
+	suite.Len(decoded, KeySize)
+
+	other, err := GenerateKey()
+	suite.Nil(err)
+	suite.NotEqual(key, other)
+
+	suite.NotPanics(func() {
+		config.Set("app.key", key)
+	})
+}
+
+func (suite *EncryptionTestSuite) TestKey() {
+	key, err := Key()
+	suite.Nil(err)
+	suite.Len(key, KeySize)
+
+	config.Set("app.key", nil)
+	key, err = Key()
+	suite.Equal(ErrNoKey, err)
+	suite.Nil(key)
+}
+
+func (suite *EncryptionTestSuite) TestEncryptDecrypt() {
+	plaintext := []byte("hello world")
+	ciphertext, err := Encrypt(plaintext)
+	suite.Nil(err)
+	suite.NotContains(ciphertext, "hello world")
+
+	other, err := Encrypt(plaintext)
+	suite.Nil(err)
+	suite.NotEqual(ciphertext, other) // Random nonce
+
+	decrypted, err := Decrypt(ciphertext)
+	suite.Nil(err)
+	suite.Equal(plaintext, decrypted)
+
+	empty, err := Encrypt([]byte{})
+	suite.Nil(err)
+	decrypted, err = Decrypt(empty)
+	suite.Nil(err)
+	suite.Empty(decrypted)
+}
+
+func (suite *EncryptionTestSuite) TestDecryptTampered() {
+	ciphertext, err := Encrypt([]byte("hello world"))
+	suite.Nil(err)
+
+	data, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	suite.Nil(err)
+	data[len(data)-1] ^= 1
+	plaintext, err := Decrypt(base64.RawURLEncoding.EncodeToString(data))
+	suite.Equal(ErrInvalidCiphertext, err)
+	suite.Nil(plaintext)
+
+	plaintext, err = Decrypt("not base64!")
+	suite.Equal(ErrInvalidCiphertext, err)
+	suite.Nil(plaintext)
+
+	plaintext, err = Decrypt("c2hvcnQ")
+	suite.Equal(ErrInvalidCiphertext, err)
+	suite.Nil(plaintext)
+
+	// Other key
+	config.Set("app.key", "9LddQLwUGC6OYXum0AqhVvMlyauNQxW7JYqV5cUW2wI=")
+	plaintext, err = Decrypt(ciphertext)
+	suite.Equal(ErrInvalidCiphertext, err)
+	suite.Nil(plaintext)
+
+	// No key
+	config.Set("app.key", nil)
+	_, err = Encrypt([]byte("hello world"))
+	suite.Equal(ErrNoKey, err)
+	_, err = Decrypt(ciphertext)
+	suite.Equal(ErrNoKey, err)
+}
+
+func (suite *EncryptionTestSuite) TestSignVerify() {
+	signature, err := Sign([]byte("message"))
+	suite.Nil(err)
+	suite.Nil(Verify([]byte("message"), signature))
+	suite.Equal(ErrInvalidSignature, Verify([]byte("tampered"), signature))
+	suite.Equal(ErrInvalidSignature, Verify([]byte("message"), "not base64!"))
+	suite.Equal(ErrInvalidSignature, Verify([]byte("message"), ""))
+
+	config.Set("app.key", nil)
+	_, err = Sign([]byte("message"))
+	suite.Equal(ErrNoKey, err)
+	suite.Equal(ErrNoKey, Verify([]byte("message"), signature))
+}
+
+func TestEncryptionSuite(t *testing.T) {
+	suite.Run(t, new(EncryptionTestSuite))
+}
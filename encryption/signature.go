@@ -0,0 +1,46 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrInvalidSignature returned by "Verify()" if the signature is malformed
+// or doesn't match the message.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Sign computes the HMAC-SHA256 of the given message, using the application key.
+// The returned signature is URL-safe base64-encoded.
+func Sign(message []byte) (string, error) {
+	mac, err := computeMAC(message)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+// Verify checks the given signature, generated by "Sign()", matches the message.
+// Returns "ErrInvalidSignature" if the signature is malformed or doesn't match.
+func Verify(message []byte, signature string) error {
+	expected, err := computeMAC(message)
+	if err != nil {
+		return err
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func computeMAC(message []byte) ([]byte, error) {
+	key, err := Key()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
@@ -2,42 +2,57 @@ package goyave
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/database"
+	"goyave.dev/goyave/v3/encryption"
 	"goyave.dev/goyave/v3/lang"
 )
 
 var (
 	server             *http.Server
 	redirectServer     *http.Server
+	serverAddr         net.Addr
+	redirectServerAddr net.Addr
+	httpServesApp      bool
 	router             *Router
 	maintenanceHandler http.Handler
 	sigChannel         chan os.Signal
+	shutdownSignals    []os.Signal   = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
 	tlsStopChannel     chan struct{} = make(chan struct{}, 1)
 	stopChannel        chan struct{} = make(chan struct{}, 1)
 	hookChannel        chan struct{} = make(chan struct{}, 1)
 
 	// Critical config entries (cached for better performance)
 	protocol        string
-	maxPayloadSize  int64
 	defaultLanguage string
 
-	startupHooks       []func()
-	shutdownHooks      []func()
-	ready              bool = false
-	maintenanceEnabled bool = false
-	mutex                   = &sync.RWMutex{}
-	once               sync.Once
+	startupHooks        []func()
+	shutdownHooks       []func()
+	connStateHooks      []func(net.Conn, http.ConnState)
+	serverConfigurators []func(*http.Server)
+	ready               bool = false
+	stopping            bool = false
+	appKeyRequired      bool = false
+	maintenanceEnabled  bool = false
+	mutex                    = &sync.RWMutex{}
+	once                sync.Once
+
+	// addrMutex guards "serverAddr" and "redirectServerAddr". It is separate from
+	// "mutex" so "BaseURL()" can be used while registering routes or in hooks.
+	addrMutex = &sync.RWMutex{}
 
 	// Logger the logger for default output
 	// Writes to stdout by default.
@@ -86,6 +101,17 @@ func IsReady() bool {
 	return ready
 }
 
+// RequireAppKey makes "Start()" fail with "ExitInvalidConfig" if the application
+// key ("app.key" config entry) is not set or is invalid. Call it before "Start()"
+// if your application relies on features using the key, such as signed URLs,
+// signed or encrypted cookies, or the "encryption" package, so a missing key
+// is reported at startup instead of when the first request needs it.
+func RequireAppKey() {
+	mutex.Lock()
+	appKeyRequired = true
+	mutex.Unlock()
+}
+
 // RegisterStartupHook to execute some code once the server is ready and running.
 func RegisterStartupHook(hook func()) {
 	mutex.Lock()
@@ -115,30 +141,157 @@ func ClearShutdownHooks() {
 	mutex.Unlock()
 }
 
+// RegisterConnStateHook to observe the lifecycle of the connections
+// to the server. The hook is called every time a client connection changes
+// state. See "http.Server.ConnState" for more details.
+//
+// Hooks must be registered before the server starts.
+func RegisterConnStateHook(hook func(net.Conn, http.ConnState)) {
+	mutex.Lock()
+	connStateHooks = append(connStateHooks, hook)
+	mutex.Unlock()
+}
+
+// ClearConnStateHooks removes all connection state hooks.
+func ClearConnStateHooks() {
+	mutex.Lock()
+	connStateHooks = []func(net.Conn, http.ConnState){}
+	mutex.Unlock()
+}
+
+// ConfigureServer registers a function giving full control over the
+// underlying "*http.Server" for advanced configuration ("BaseContext",
+// "TLSNextProto", "ErrorLog", etc). The function is called once the server
+// is built from the configuration and before it starts listening.
+//
+// If the protocol is "https", the function is also called for the HTTP server
+// redirecting to HTTPS. Use the "Addr" field to tell the two servers apart.
+// Replacing the "ConnState" field disables the connection state hooks
+// and the "server.stuckConnectionTimeout" config entry.
+//
+// Configurators must be registered before the server starts.
+func ConfigureServer(configurator func(*http.Server)) {
+	mutex.Lock()
+	serverConfigurators = append(serverConfigurators, configurator)
+	mutex.Unlock()
+}
+
+// ClearServerConfigurators removes all functions registered with "ConfigureServer()".
+func ClearServerConfigurators() {
+	mutex.Lock()
+	serverConfigurators = []func(*http.Server){}
+	mutex.Unlock()
+}
+
+func configureServer(s *http.Server) {
+	for _, configurator := range serverConfigurators {
+		configurator(s)
+	}
+}
+
+// LogConnState is a connection state hook writing the state changes
+// of the client connections to the framework logger (see "SetLogger").
+//
+//	goyave.RegisterConnStateHook(goyave.LogConnState)
+func LogConnState(conn net.Conn, state http.ConnState) {
+	GetLogger().Infof("Connection %s: %s", conn.RemoteAddr(), state)
+}
+
+// connWatcher closes the connections staying too long in the
+// "new" or "active" state and calls the connection state hooks.
+type connWatcher struct {
+	timers  map[net.Conn]*time.Timer
+	hooks   []func(net.Conn, http.ConnState)
+	timeout time.Duration
+	mu      sync.Mutex
+}
+
+func newConnWatcher(timeout time.Duration, hooks []func(net.Conn, http.ConnState)) *connWatcher {
+	return &connWatcher{
+		timers:  make(map[net.Conn]*time.Timer),
+		hooks:   hooks,
+		timeout: timeout,
+	}
+}
+
+func (w *connWatcher) connState(conn net.Conn, state http.ConnState) {
+	if w.timeout > 0 {
+		w.mu.Lock()
+		w.watch(conn, state)
+		w.mu.Unlock()
+	}
+
+	for _, hook := range w.hooks {
+		hook(conn, state)
+	}
+}
+
+// watch stops the timer of the given connection and starts a new one if
+// the connection is in the "new" or "active" state. The caller must hold "w.mu".
+func (w *connWatcher) watch(conn net.Conn, state http.ConnState) {
+	if timer, ok := w.timers[conn]; ok {
+		timer.Stop()
+		delete(w.timers, conn)
+	}
+	if state == http.StateNew || state == http.StateActive {
+		var timer *time.Timer
+		timer = time.AfterFunc(w.timeout, func() {
+			w.mu.Lock()
+			if w.timers[conn] != timer {
+				// The connection changed state while this timer was firing
+				w.mu.Unlock()
+				return
+			}
+			delete(w.timers, conn)
+			w.mu.Unlock()
+			conn.Close()
+		})
+		w.timers[conn] = timer
+	}
+}
+
+// loadLanguages loads the default language and all the language
+// directories. A missing language directory only results in a warning.
+// Returns an error if the language defined by the "app.defaultLanguage"
+// config entry is not available.
+func loadLanguages() error {
+	lang.LoadDefault()
+	if err := lang.LoadAllAvailableLanguages(); err != nil {
+		GetLogger().Warnf("%v", err)
+	}
+	if defaultLanguage := config.GetString("app.defaultLanguage"); !lang.IsAvailable(defaultLanguage) {
+		available := lang.GetAvailableLanguages()
+		sort.Strings(available)
+		return fmt.Errorf("Invalid config entry \"app.defaultLanguage\": language %q is not available (available languages: %s)", defaultLanguage, strings.Join(available, ", "))
+	}
+	return nil
+}
+
 // Start starts the web server.
 // The routeRegistrer parameter is a function aimed at registering all your routes and middleware.
-//  import (
-//      "goyave.dev/goyave/v3"
-//      "github.com/username/projectname/route"
-//  )
 //
-//  func main() {
-//      if err := goyave.Start(route.Register); err != nil {
-//          os.Exit(err.(*goyave.Error).ExitCode)
-//      }
-//  }
+//	import (
+//	    "goyave.dev/goyave/v3"
+//	    "github.com/username/projectname/route"
+//	)
+//
+//	func main() {
+//	    if err := goyave.Start(route.Register); err != nil {
+//	        os.Exit(err.(*goyave.Error).ExitCode)
+//	    }
+//	}
 //
 // Errors returned can be safely type-asserted to "*goyave.Error".
 // Panics if the server is already running.
 func Start(routeRegistrer func(*Router)) error {
 	if IsReady() {
-		ErrLogger.Panicf("Server is already running.")
+		panic("Server is already running.")
 	}
 
 	mutex.Lock()
 	if !config.IsLoaded() {
 		if err := config.Load(); err != nil {
-			ErrLogger.Println(err)
+			GetLogger().Errorf("%v", err)
 			mutex.Unlock()
 			return &Error{err, ExitInvalidConfig}
 		}
@@ -147,8 +300,20 @@ func Start(routeRegistrer func(*Router)) error {
 	// Performance improvements by loading critical config entries beforehand
 	cacheCriticalConfig()
 
-	lang.LoadDefault()
-	lang.LoadAllAvailableLanguages()
+	if err := loadLanguages(); err != nil {
+		GetLogger().Errorf("%v", err)
+		mutex.Unlock()
+		return &Error{err, ExitInvalidConfig}
+	}
+
+	if appKeyRequired {
+		if _, err := encryption.Key(); err != nil {
+			err = fmt.Errorf("The application key is required: %w", err)
+			GetLogger().Errorf("%v", err)
+			mutex.Unlock()
+			return &Error{err, ExitInvalidConfig}
+		}
+	}
 
 	if config.GetBool("database.autoMigrate") && config.GetString("database.connection") != "none" {
 		database.Migrate()
@@ -161,7 +326,6 @@ func Start(routeRegistrer func(*Router)) error {
 }
 
 func cacheCriticalConfig() {
-	maxPayloadSize = int64(config.GetFloat("server.maxUploadSize") * 1024 * 1024)
 	defaultLanguage = config.GetString("app.defaultLanguage")
 	protocol = config.GetString("server.protocol")
 }
@@ -171,6 +335,9 @@ func EnableMaintenance() {
 	mutex.Lock()
 	server.Handler = getMaintenanceHandler()
 	maintenanceEnabled = true
+	if httpServesApp {
+		redirectServer.Handler = server.Handler
+	}
 	mutex.Unlock()
 }
 
@@ -179,6 +346,9 @@ func DisableMaintenance() {
 	mutex.Lock()
 	server.Handler = router
 	maintenanceEnabled = false
+	if httpServesApp {
+		redirectServer.Handler = server.Handler
+	}
 	mutex.Unlock()
 }
 
@@ -207,10 +377,15 @@ func getMaintenanceHandler() http.Handler {
 }
 
 // Stop gracefully shuts down the server without interrupting any
-// active connections.
+// active connections. If the application is served over HTTPS, the HTTP
+// listener (redirecting to HTTPS or serving the application, depending on
+// the "server.httpsRedirect" config entry) is shut down gracefully as well.
 //
 // Make sure the program doesn't exit and waits instead for Stop to return.
 //
+// The context of the goroutines started with "Go()" is cancelled and
+// Stop waits for them to return.
+//
 // Stop does not attempt to close nor wait for hijacked
 // connections such as WebSockets. The caller of Stop should
 // separately notify such long-lived connections of shutdown and wait
@@ -228,29 +403,67 @@ func Stop() {
 	mutex.Unlock()
 }
 
+// stop gracefully shuts down the server. The caller must hold "mutex".
+// It is released while waiting for the servers and the managed goroutines, so
+// request handlers and goroutines using functions such as "IsReady()" don't
+// block the shutdown, and locked again before returning.
 func stop(ctx context.Context) error {
-	var err error
-	if server != nil {
-		err = server.Shutdown(ctx)
-		database.Close()
-		server = nil
-		router = nil
-		ready = false
-		maintenanceEnabled = false
-		if redirectServer != nil {
-			redirectServer.Shutdown(ctx)
+	if server == nil || stopping {
+		return nil
+	}
+	stopping = true
+	s, rs := server, redirectServer
+	mutex.Unlock()
+
+	// Both listeners may serve the application: shut them down
+	// before stopping the workers and closing the database.
+	wg := sync.WaitGroup{}
+	if rs != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rs.Shutdown(ctx)
 			<-tlsStopChannel
-			redirectServer = nil
-		}
+		}()
+	}
+	err := s.Shutdown(ctx)
+	wg.Wait()
+	stopWorkers(ctx)
+	database.Close()
 
-		for _, hook := range shutdownHooks {
-			hook()
-		}
-		stopChannel <- struct{}{}
+	mutex.Lock()
+	server = nil
+	router = nil
+	ready = false
+	maintenanceEnabled = false
+	addrMutex.Lock()
+	serverAddr = nil
+	if rs != nil {
+		redirectServerAddr = nil
+	}
+	addrMutex.Unlock()
+	if rs != nil {
+		redirectServer = nil
+		httpServesApp = false
 	}
+	stopping = false
+
+	for _, hook := range shutdownHooks {
+		hook()
+	}
+	stopChannel <- struct{}{}
 	return err
 }
 
+// getTimeout returns the duration in seconds defined by the given
+// config entry, or the given default value if the entry is not set.
+func getTimeout(key string, defaultValue time.Duration) time.Duration {
+	if !config.Has(key) {
+		return defaultValue
+	}
+	return time.Duration(config.GetInt(key)) * time.Second
+}
+
 func getHost(protocol string) string {
 	var port string
 	if protocol == "https" {
@@ -266,10 +479,16 @@ func getAddress(protocol string) string {
 	var port string
 	if protocol == "https" {
 		p := config.GetInt("server.httpsPort")
+		if p == 0 {
+			p = boundPort(protocol)
+		}
 		port = strconv.Itoa(p)
 		shouldShowPort = p != 443
 	} else {
 		p := config.GetInt("server.port")
+		if p == 0 {
+			p = boundPort(protocol)
+		}
 		port = strconv.Itoa(p)
 		shouldShowPort = p != 80
 	}
@@ -288,35 +507,74 @@ func getAddress(protocol string) string {
 	return protocol + "://" + host
 }
 
+// boundPort returns the port the listener serving the given protocol
+// is bound to, or 0 if there is no such listener.
+func boundPort(proto string) int {
+	addrMutex.RLock()
+	addr := serverAddr
+	if proto != protocol {
+		addr = redirectServerAddr
+	}
+	addrMutex.RUnlock()
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.Port
+	}
+	return 0
+}
+
 // BaseURL returns the base URL of your application.
+// If the port is set to 0 in the config, the port assigned
+// by the OS is used while the server is running.
 func BaseURL() string {
 	return getAddress(config.GetString("server.protocol"))
 }
 
+// Address returns the network address ("host:port") the server is listening
+// on, or an empty string if the server is not running.
+//
+// Setting the "server.port" (or "server.httpsPort") config entry to 0 lets
+// the OS pick a free port. This is useful in tests to avoid port conflicts.
+// The actual port can then only be known from this function or "BaseURL()".
+func Address() string {
+	addrMutex.RLock()
+	defer addrMutex.RUnlock()
+	if serverAddr == nil {
+		return ""
+	}
+	return serverAddr.String()
+}
+
+// startTLSRedirectServer starts the HTTP listener used alongside the HTTPS
+// server. By default, it redirects all requests to HTTPS. If the "server.httpsRedirect"
+// config entry is disabled, it serves the application like the HTTPS server instead.
 func startTLSRedirectServer() {
-	httpsAddress := getAddress("https")
 	timeout := time.Duration(config.GetInt("server.timeout")) * time.Second
 	redirectServer = &http.Server{
 		Addr:         getHost("http"),
 		WriteTimeout: timeout,
 		ReadTimeout:  timeout,
 		IdleTimeout:  timeout * 2,
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			address := httpsAddress + r.URL.Path
-			query := r.URL.Query()
-			if len(query) != 0 {
-				address += "?" + query.Encode()
-			}
-			http.Redirect(w, r, address, http.StatusPermanentRedirect)
-		}),
+		Handler:      tlsRedirectHandler(),
 	}
+	httpServesApp = !config.GetBool("server.httpsRedirect")
+	if httpServesApp {
+		redirectServer.Handler = server.Handler
+		redirectServer.ReadTimeout = server.ReadTimeout
+		redirectServer.ReadHeaderTimeout = server.ReadHeaderTimeout
+		redirectServer.ConnState = server.ConnState
+	}
+	configureServer(redirectServer)
 
 	ln, err := net.Listen("tcp", redirectServer.Addr)
 	if err != nil {
-		ErrLogger.Printf("The TLS redirect server encountered an error: %s\n", err.Error())
+		GetLogger().Errorf("The TLS redirect server encountered an error: %s", err.Error())
 		redirectServer = nil
+		httpServesApp = false
 		return
 	}
+	addrMutex.Lock()
+	redirectServerAddr = ln.Addr()
+	addrMutex.Unlock()
 
 	ok := ready
 	r := redirectServer
@@ -324,9 +582,13 @@ func startTLSRedirectServer() {
 	go func() {
 		if ok && r != nil {
 			if err := r.Serve(ln); err != nil && err != http.ErrServerClosed {
-				ErrLogger.Printf("The TLS redirect server encountered an error: %s\n", err.Error())
+				GetLogger().Errorf("The TLS redirect server encountered an error: %s", err.Error())
 				mutex.Lock()
 				redirectServer = nil
+				httpServesApp = false
+				addrMutex.Lock()
+				redirectServerAddr = nil
+				addrMutex.Unlock()
 				ln.Close()
 				mutex.Unlock()
 				return
@@ -337,27 +599,50 @@ func startTLSRedirectServer() {
 	}()
 }
 
+// tlsRedirectHandler returns a handler permanently redirecting requests
+// to HTTPS. The target is the HTTPS address of the server, or the host defined
+// by the "server.httpsRedirectTarget" config entry if set.
+func tlsRedirectHandler() http.Handler {
+	httpsAddress := getAddress("https")
+	if config.Has("server.httpsRedirectTarget") {
+		httpsAddress = "https://" + config.GetString("server.httpsRedirectTarget")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address := httpsAddress + r.URL.Path
+		query := r.URL.Query()
+		if len(query) != 0 {
+			address += "?" + query.Encode()
+		}
+		http.Redirect(w, r, address, http.StatusPermanentRedirect)
+	})
+}
+
 func startServer(router *Router) error {
 	defer func() {
 		<-stopChannel // Wait for stop() to finish before returning
 	}()
 	timeout := time.Duration(config.GetInt("server.timeout")) * time.Second
+	readTimeout := getTimeout("server.readTimeout", timeout)
+	watcher := newConnWatcher(time.Duration(config.GetInt("server.stuckConnectionTimeout"))*time.Second, connStateHooks)
 	server = &http.Server{
-		Addr:         getHost(protocol),
-		WriteTimeout: timeout,
-		ReadTimeout:  timeout,
-		IdleTimeout:  timeout * 2,
-		Handler:      router,
+		Addr:              getHost(protocol),
+		WriteTimeout:      timeout,
+		ReadTimeout:       readTimeout,
+		ReadHeaderTimeout: getTimeout("server.readHeaderTimeout", readTimeout),
+		IdleTimeout:       timeout * 2,
+		Handler:           router,
+		ConnState:         watcher.connState,
 	}
 
 	if config.GetBool("server.maintenance") {
 		server.Handler = getMaintenanceHandler()
 		maintenanceEnabled = true
 	}
+	configureServer(server)
 
 	ln, err := net.Listen("tcp", server.Addr)
 	if err != nil {
-		ErrLogger.Println(err)
+		GetLogger().Errorf("%v", err)
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		stop(ctx)
@@ -365,6 +650,9 @@ func startServer(router *Router) error {
 		return &Error{err, ExitNetworkError}
 	}
 	defer ln.Close()
+	addrMutex.Lock()
+	serverAddr = ln.Addr()
+	addrMutex.Unlock()
 
 	readyChan := make(chan struct{})
 	registerShutdownHook(readyChan, stop)
@@ -372,14 +660,21 @@ func startServer(router *Router) error {
 	close(readyChan)
 
 	ready = true
+	startWorkers()
 	if protocol == "https" {
 		startTLSRedirectServer()
 
 		s := server
+		tlsErr := configureTLS(s)
 		mutex.Unlock()
+		if tlsErr != nil {
+			GetLogger().Errorf("%v", tlsErr)
+			Stop()
+			return &Error{tlsErr, ExitHTTPError}
+		}
 		runStartupHooks()
-		if err := s.ServeTLS(ln, config.GetString("server.tls.cert"), config.GetString("server.tls.key")); err != nil && err != http.ErrServerClosed {
-			ErrLogger.Println(err)
+		if err := s.ServeTLS(ln, "", ""); err != nil && err != http.ErrServerClosed {
+			GetLogger().Errorf("%v", err)
 			Stop()
 			return &Error{err, ExitHTTPError}
 		}
@@ -389,7 +684,7 @@ func startServer(router *Router) error {
 		mutex.Unlock()
 		runStartupHooks()
 		if err := s.Serve(ln); err != nil && err != http.ErrServerClosed {
-			ErrLogger.Println(err)
+			GetLogger().Errorf("%v", err)
 			Stop()
 			return &Error{err, ExitHTTPError}
 		}
@@ -404,16 +699,44 @@ func runStartupHooks() {
 	}
 }
 
+// SetShutdownSignals replace the signals triggering the graceful shutdown
+// of the server. By default, the server is stopped when receiving "SIGINT"
+// or "SIGTERM".
+//
+// Calling this function without any signal disables the built-in signal handling.
+// This is useful when embedding the server in a process managing its own lifecycle:
+// the server then has to be stopped with "Stop()".
+//
+// The signals are registered when the server starts, so this function must
+// be called before "Start()" to take effect.
+//
+//	goyave.SetShutdownSignals(syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+func SetShutdownSignals(signals ...os.Signal) {
+	mutex.Lock()
+	shutdownSignals = signals
+	mutex.Unlock()
+}
+
 func registerShutdownHook(readyChan chan struct{}, hook func(context.Context) error) {
-	sigChannel = make(chan os.Signal, 64)
-	signal.Notify(sigChannel, syscall.SIGINT, syscall.SIGTERM)
+	if len(shutdownSignals) == 0 {
+		// Built-in signal handling disabled
+		go func() {
+			readyChan <- struct{}{}
+		}()
+		return
+	}
+	ch := make(chan os.Signal, 64)
+	sigChannel = ch
+	signal.Notify(ch, shutdownSignals...)
 
 	go func() {
 		readyChan <- struct{}{}
 		select {
 		case <-hookChannel:
+			signal.Stop(ch)
 			hookChannel <- struct{}{}
-		case <-sigChannel: // Block until SIGINT or SIGTERM received
+		case <-ch: // Block until one of the shutdown signals is received
+			signal.Stop(ch)
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
@@ -3,7 +3,9 @@ package goyave
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"net"
 	"net/http"
 	"os"
@@ -14,6 +16,7 @@ import (
 
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/helper/filesystem"
+	"goyave.dev/goyave/v3/lang"
 
 	_ "goyave.dev/goyave/v3/database/dialect/mysql"
 )
@@ -112,6 +115,77 @@ func (suite *GoyaveTestSuite) TestStartStopServer() {
 	}
 }
 
+func (suite *GoyaveTestSuite) TestCustomShutdownSignal() {
+	if runtime.GOOS == "windows" {
+		suite.T().Skip("Testing on a windows machine. Cannot test proc signals")
+	}
+	config.Clear()
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		suite.Fail("Couldn't get process PID, skipping custom signal test")
+		return
+	}
+	SetShutdownSignals(syscall.SIGHUP)
+	defer SetShutdownSignals(syscall.SIGINT, syscall.SIGTERM)
+
+	c := make(chan struct{}, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), suite.Timeout())
+	defer cancel()
+
+	RegisterStartupHook(func() {
+		time.Sleep(10 * time.Millisecond)
+		if err := proc.Signal(syscall.SIGHUP); err != nil {
+			suite.Fail(err.Error())
+		}
+	})
+	defer ClearStartupHooks()
+	go func() {
+		if err := Start(func(router *Router) {}); err != nil {
+			suite.Fail(err.Error())
+		}
+		c <- struct{}{}
+	}()
+
+	select {
+	case <-ctx.Done():
+		suite.Fail(fmt.Sprintf("Timeout (%dms) exceeded in custom shutdown signal test", suite.Timeout().Milliseconds()))
+	case <-c:
+		suite.False(IsReady())
+		suite.Nil(server)
+	}
+}
+
+func (suite *GoyaveTestSuite) TestDisabledShutdownSignals() {
+	config.Clear()
+	SetShutdownSignals()
+	defer SetShutdownSignals(syscall.SIGINT, syscall.SIGTERM)
+
+	c := make(chan struct{}, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), suite.Timeout())
+	defer cancel()
+
+	RegisterStartupHook(func() {
+		mutex.RLock()
+		suite.Nil(sigChannel)
+		mutex.RUnlock()
+		Stop()
+	})
+	defer ClearStartupHooks()
+	go func() {
+		if err := Start(func(router *Router) {}); err != nil {
+			suite.Fail(err.Error())
+		}
+		c <- struct{}{}
+	}()
+
+	select {
+	case <-ctx.Done():
+		suite.Fail(fmt.Sprintf("Timeout (%dms) exceeded in disabled shutdown signals test", suite.Timeout().Milliseconds()))
+	case <-c:
+		suite.False(IsReady())
+	}
+}
+
 func (suite *GoyaveTestSuite) TestTLSServer() {
 	suite.loadConfig()
 	protocol = "https"
@@ -173,6 +247,239 @@ func (suite *GoyaveTestSuite) TestTLSServer() {
 	protocol = "http"
 }
 
+func (suite *GoyaveTestSuite) TestTLSRedirectDisabled() {
+	suite.loadConfig()
+	protocol = "https"
+	config.Set("server.protocol", "https")
+	config.Set("server.httpsRedirect", false)
+	defer func() {
+		config.Set("server.protocol", "http")
+		config.Set("server.httpsRedirect", true)
+		protocol = "http"
+	}()
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/hello", helloHandler)
+	}, func() {
+		netClient := suite.getHTTPClient()
+		for _, url := range []string{"http://127.0.0.1:1235/hello", "https://127.0.0.1:1236/hello"} {
+			resp, err := netClient.Get(url)
+			suite.Nil(err)
+			if err == nil {
+				suite.Equal(200, resp.StatusCode)
+				suite.Equal("Hi!", string(suite.GetBody(resp)))
+				resp.Body.Close()
+			}
+		}
+
+		// Maintenance mode applies to the HTTP listener too
+		EnableMaintenance()
+		resp, err := netClient.Get("http://127.0.0.1:1235/hello")
+		suite.Nil(err)
+		if err == nil {
+			suite.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+			resp.Body.Close()
+		}
+		DisableMaintenance()
+	})
+}
+
+func (suite *GoyaveTestSuite) TestDualProtocolGracefulShutdown() {
+	suite.loadConfig()
+	protocol = "https"
+	config.Set("server.protocol", "https")
+	config.Set("server.httpsRedirect", false)
+	defer func() {
+		config.Set("server.protocol", "http")
+		config.Set("server.httpsRedirect", true)
+		protocol = "http"
+	}()
+
+	entered := make(chan struct{}, 2)
+	results := make(chan string, 2)
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/slow", func(response *Response, request *Request) {
+			entered <- struct{}{}
+			time.Sleep(100 * time.Millisecond)
+			response.String(http.StatusOK, "done")
+		})
+	}, func() {
+		netClient := suite.getHTTPClient()
+		for _, url := range []string{"http://127.0.0.1:1235/slow", "https://127.0.0.1:1236/slow"} {
+			go func(url string) {
+				resp, err := netClient.Get(url)
+				if err != nil {
+					results <- err.Error()
+					return
+				}
+				defer resp.Body.Close()
+				results <- string(suite.GetBody(resp))
+			}(url)
+		}
+		// Shut down while both requests are being handled
+		<-entered
+		<-entered
+	})
+
+	suite.Equal("done", <-results)
+	suite.Equal("done", <-results)
+}
+
+func (suite *GoyaveTestSuite) TestDualProtocolShutdownOrder() {
+	suite.loadConfig()
+	protocol = "https"
+	config.Set("server.protocol", "https")
+	config.Set("server.httpsRedirect", false)
+	config.Set("server.readTimeout", 7)
+	defer func() {
+		config.Set("server.protocol", "http")
+		config.Set("server.httpsRedirect", true)
+		config.Set("server.readTimeout", nil)
+		protocol = "http"
+	}()
+
+	workerCtx := make(chan context.Context, 1)
+	entered := make(chan struct{}, 1)
+	workerDone := make(chan bool, 1)
+	suite.RunServer(func(router *Router) {
+		Go(func(ctx context.Context) {
+			workerCtx <- ctx
+			<-ctx.Done()
+		})
+		router.Route("GET", "/slow", func(response *Response, request *Request) {
+			ctx := <-workerCtx
+			entered <- struct{}{}
+			time.Sleep(100 * time.Millisecond)
+			workerDone <- ctx.Err() != nil
+			response.String(http.StatusOK, "done")
+		})
+	}, func() {
+		mutex.RLock()
+		suite.Equal(server.ReadTimeout, redirectServer.ReadTimeout)
+		suite.Equal(7*time.Second, redirectServer.ReadTimeout)
+		suite.Equal(server.ReadHeaderTimeout, redirectServer.ReadHeaderTimeout)
+		suite.NotNil(redirectServer.ConnState)
+		mutex.RUnlock()
+
+		go func() {
+			resp, err := suite.getHTTPClient().Get("http://127.0.0.1:1235/slow")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		<-entered
+	})
+
+	// The HTTP listener is shut down before the workers are stopped
+	suite.False(<-workerDone)
+}
+
+func (suite *GoyaveTestSuite) TestTLSRedirectCustomTarget() {
+	suite.loadConfig()
+	protocol = "https"
+	config.Set("server.protocol", "https")
+	config.Set("server.httpsRedirectTarget", "example.org:8443")
+	defer func() {
+		config.Set("server.protocol", "http")
+		config.Set("server.httpsRedirectTarget", nil)
+		protocol = "http"
+	}()
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/hello", helloHandler)
+	}, func() {
+		resp, err := suite.getHTTPClient().Get("http://127.0.0.1:1235/hello?param=1")
+		suite.Nil(err)
+		if err == nil {
+			suite.Equal(http.StatusPermanentRedirect, resp.StatusCode)
+			suite.Equal("https://example.org:8443/hello?param=1", resp.Header.Get("Location"))
+			resp.Body.Close()
+		}
+	})
+}
+
+func (suite *GoyaveTestSuite) TestRandomPort() {
+	suite.loadConfig()
+	config.Set("server.port", 0)
+	defer config.Set("server.port", 1235)
+
+	suite.Empty(Address())
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/hello", helloHandler)
+	}, func() {
+		address := Address()
+		_, port, err := net.SplitHostPort(address)
+		suite.Nil(err)
+		suite.NotEqual("0", port)
+		suite.Equal("http://127.0.0.1:"+port, BaseURL())
+
+		resp, err := suite.Get("/hello", nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusOK, resp.StatusCode)
+			suite.Equal("Hi!", string(suite.GetBody(resp)))
+		}
+	})
+	suite.Empty(Address())
+	suite.Equal("http://127.0.0.1:0", BaseURL())
+}
+
+func (suite *GoyaveTestSuite) TestBaseURLConcurrentStop() {
+	suite.loadConfig()
+	config.Set("server.port", 0)
+	defer config.Set("server.port", 1235)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	suite.RunServer(func(router *Router) {}, func() {
+		go func() {
+			defer close(stopped)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					BaseURL()
+				}
+			}
+		}()
+	})
+	close(done)
+	<-stopped
+	suite.Equal("http://127.0.0.1:0", BaseURL())
+}
+
+func (suite *GoyaveTestSuite) TestRandomPortTLS() {
+	suite.loadConfig()
+	protocol = "https"
+	config.Set("server.protocol", "https")
+	config.Set("server.port", 0)
+	config.Set("server.httpsPort", 0)
+	defer func() {
+		config.Set("server.protocol", "http")
+		config.Set("server.port", 1235)
+		config.Set("server.httpsPort", 1236)
+		protocol = "http"
+	}()
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/hello", helloHandler)
+	}, func() {
+		_, port, err := net.SplitHostPort(Address())
+		suite.Nil(err)
+		suite.NotEqual("0", port)
+		suite.Equal("https://127.0.0.1:"+port, BaseURL())
+
+		httpAddress := getAddress("http")
+		suite.NotEqual("http://127.0.0.1:0", httpAddress)
+		resp, err := suite.getHTTPClient().Get(httpAddress + "/hello")
+		suite.Nil(err)
+		if err == nil {
+			resp.Body.Close()
+			suite.Equal(http.StatusPermanentRedirect, resp.StatusCode)
+			suite.Equal(BaseURL()+"/hello", resp.Header.Get("Location"))
+		}
+	})
+}
+
 func (suite *GoyaveTestSuite) TestTLSRedirectServerError() {
 	suite.loadConfig()
 	c := make(chan bool)
@@ -251,6 +558,61 @@ func (suite *GoyaveTestSuite) TestStaticServing() {
 	})
 }
 
+func (suite *GoyaveTestSuite) TestMissingLanguageDirectory() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	dir, err := ioutil.TempDir("", "goyave-lang")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	loadLanguages()
+	if err := os.Chdir(wd); err != nil {
+		panic(err)
+	}
+	defer loadLanguages()
+
+	suite.Equal([]string{"en-US"}, lang.GetAvailableLanguages())
+	suite.Equal("Malformed JSON", lang.Get("en-US", "malformed-json"))
+	suite.Equal([]logEntry{{"warn", lang.ErrNoLanguageDirectory.Error()}}, logger.entries)
+}
+
+func (suite *GoyaveTestSuite) TestStaticServingRange() {
+	suite.RunServer(func(router *Router) {
+		router.Static("/resources", "resources", false)
+	}, func() {
+		resp, err := suite.Get("/resources/test_file.txt", map[string]string{"Range": "bytes=3-7"})
+		suite.Nil(err)
+		if err != nil {
+			return
+		}
+		body := suite.GetBody(resp)
+		resp.Body.Close()
+		suite.Equal(http.StatusPartialContent, resp.StatusCode)
+		suite.Equal("bytes 3-7/25", resp.Header.Get("Content-Range"))
+		suite.Equal("5", resp.Header.Get("Content-Length"))
+		suite.Equal("utf-8", string(body))
+
+		resp, err = suite.Get("/resources/test_file.txt", map[string]string{"Range": "bytes=100-"})
+		suite.Nil(err)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+		suite.Equal(http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+		suite.Equal("bytes */25", resp.Header.Get("Content-Range"))
+	})
+}
+
 func (suite *GoyaveTestSuite) TestServerError() {
 	suite.loadConfig()
 	suite.testServerError("http")
@@ -455,6 +817,60 @@ func (suite *GoyaveTestSuite) TestConfigError() {
 	}
 }
 
+func (suite *GoyaveTestSuite) TestDefaultLanguage() {
+	suite.Nil(loadLanguages())
+
+	prev := config.GetString("app.defaultLanguage")
+	config.Set("app.defaultLanguage", "xx-XX")
+	defer config.Set("app.defaultLanguage", prev)
+
+	err := loadLanguages()
+	if suite.NotNil(err) {
+		suite.Equal("Invalid config entry \"app.defaultLanguage\": language \"xx-XX\" is not available (available languages: en-US)", err.Error())
+	}
+
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	err = Start(func(r *Router) {})
+	suite.False(IsReady())
+	if suite.NotNil(err) {
+		e := err.(*Error)
+		suite.Equal(ExitInvalidConfig, e.ExitCode)
+		suite.Equal("Invalid config entry \"app.defaultLanguage\": language \"xx-XX\" is not available (available languages: en-US)", e.Error())
+	}
+	suite.Len(logger.entries, 1)
+}
+
+func (suite *GoyaveTestSuite) TestRequireAppKey() {
+	suite.False(appKeyRequired)
+	RequireAppKey()
+	defer func() {
+		appKeyRequired = false
+	}()
+	suite.True(appKeyRequired)
+
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	err := Start(func(r *Router) {})
+	suite.False(IsReady())
+	if suite.NotNil(err) {
+		e := err.(*Error)
+		suite.Equal(ExitInvalidConfig, e.ExitCode)
+		suite.Equal("The application key is required: \"app.key\" config entry is not set", e.Error())
+	}
+	suite.Len(logger.entries, 1)
+
+	config.Set("app.key", "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=")
+	defer config.Set("app.key", nil)
+	suite.RunServer(func(r *Router) {}, func() {
+		suite.True(IsReady())
+	})
+}
+
 func (suite *GoyaveTestSuite) TestShutdownHook() {
 	executed := false
 	RegisterShutdownHook(func() {
@@ -469,6 +885,241 @@ func (suite *GoyaveTestSuite) TestShutdownHook() {
 	suite.Len(shutdownHooks, 0)
 }
 
+func (suite *GoyaveTestSuite) TestConfigureServer() {
+	errorLog := log.New(ioutil.Discard, "", 0)
+	configured := []*http.Server{}
+	ConfigureServer(func(s *http.Server) {
+		configured = append(configured, s)
+		s.ErrorLog = errorLog
+	})
+	defer ClearServerConfigurators()
+
+	suite.RunServer(func(r *Router) {}, func() {
+		suite.Len(configured, 1)
+		suite.Same(server, configured[0])
+		suite.Same(errorLog, server.ErrorLog)
+	})
+
+	configured = []*http.Server{}
+	suite.loadConfig()
+	protocol = "https"
+	config.Set("server.protocol", "https")
+	suite.RunServer(func(r *Router) {}, func() {
+		suite.Len(configured, 2)
+		suite.Contains(configured, server)
+		suite.Contains(configured, redirectServer)
+		suite.Same(errorLog, server.ErrorLog)
+		suite.Same(errorLog, redirectServer.ErrorLog)
+	})
+	config.Set("server.protocol", "http")
+	protocol = "http"
+
+	ClearServerConfigurators()
+	suite.Empty(serverConfigurators)
+}
+
+// dialSlowClient opens a connection to the server and sends the beginning
+// of a request, one byte at a time. Returns the time it took for the
+// server to close the connection.
+func (suite *GoyaveTestSuite) dialSlowClient() time.Duration {
+	conn, err := net.Dial("tcp", getHost("http"))
+	if err != nil {
+		suite.Fail(err.Error())
+		return 0
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	closed := make(chan struct{})
+	go func() {
+		conn.SetReadDeadline(time.Now().Add(4 * time.Second))
+		ioutil.ReadAll(conn)
+		close(closed)
+	}()
+
+	payload := []byte("GET / HTTP/1.1\r\nHost: 127.0.0.1\r\nX-Slow: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	for _, b := range payload {
+		select {
+		case <-closed:
+			return time.Since(start)
+		case <-time.After(100 * time.Millisecond):
+			conn.Write([]byte{b})
+		}
+	}
+	<-closed
+	return time.Since(start)
+}
+
+func (suite *GoyaveTestSuite) TestReadHeaderTimeout() {
+	config.Set("server.readHeaderTimeout", 1)
+	defer config.Set("server.readHeaderTimeout", nil)
+	suite.RunServer(func(r *Router) {}, func() {
+		suite.Equal(time.Second, server.ReadHeaderTimeout)
+		suite.Equal(10*time.Second, server.ReadTimeout)
+		elapsed := suite.dialSlowClient()
+		suite.GreaterOrEqual(int64(elapsed), int64(time.Second))
+		suite.Less(int64(elapsed), int64(2*time.Second))
+	})
+
+	config.Set("server.readTimeout", 3)
+	defer config.Set("server.readTimeout", nil)
+	config.Set("server.readHeaderTimeout", nil)
+	suite.RunServer(func(r *Router) {}, func() {
+		suite.Equal(3*time.Second, server.ReadHeaderTimeout)
+		suite.Equal(3*time.Second, server.ReadTimeout)
+	})
+}
+
+func (suite *GoyaveTestSuite) TestStuckConnectionTimeout() {
+	config.Set("server.stuckConnectionTimeout", 1)
+	defer config.Set("server.stuckConnectionTimeout", 0)
+
+	states := make(chan http.ConnState, 10)
+	RegisterConnStateHook(func(conn net.Conn, state http.ConnState) {
+		states <- state
+	})
+	defer ClearConnStateHooks()
+
+	suite.RunServer(func(r *Router) {}, func() {
+		elapsed := suite.dialSlowClient()
+		suite.GreaterOrEqual(int64(elapsed), int64(time.Second))
+		suite.Less(int64(elapsed), int64(2*time.Second))
+		suite.Equal(http.StateNew, <-states)
+		suite.Equal(http.StateActive, <-states) // First byte received
+		suite.Equal(http.StateClosed, <-states)
+	})
+}
+
+func (suite *GoyaveTestSuite) TestConnWatcher() {
+	hookStates := []http.ConnState{}
+	watcher := newConnWatcher(50*time.Millisecond, []func(net.Conn, http.ConnState){
+		func(conn net.Conn, state http.ConnState) {
+			hookStates = append(hookStates, state)
+		},
+	})
+
+	client, conn := net.Pipe()
+	defer client.Close()
+	watcher.connState(conn, http.StateNew)
+	watcher.connState(conn, http.StateActive)
+	watcher.connState(conn, http.StateIdle)
+	suite.Empty(watcher.timers)
+	suite.Equal([]http.ConnState{http.StateNew, http.StateActive, http.StateIdle}, hookStates)
+
+	// Idle connections are not closed by the watcher
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, err := client.Read(make([]byte, 1))
+	suite.True(err.(net.Error).Timeout())
+
+	watcher.connState(conn, http.StateActive)
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	start := time.Now()
+	_, err = client.Read(make([]byte, 1))
+	suite.Equal(io.EOF, err)
+	suite.Less(int64(time.Since(start)), int64(500*time.Millisecond))
+	suite.Empty(watcher.timers)
+
+	// A timer firing while the connection goes back to idle
+	// then active doesn't close the connection
+	client2, conn2 := net.Pipe()
+	defer client2.Close()
+	watcher = newConnWatcher(100*time.Millisecond, nil)
+	watcher.connState(conn2, http.StateActive)
+	watcher.mu.Lock()
+	time.Sleep(150 * time.Millisecond) // The first timer fires and waits for the lock
+	watcher.watch(conn2, http.StateIdle)
+	watcher.watch(conn2, http.StateActive)
+	watcher.mu.Unlock()
+
+	client2.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	_, err = client2.Read(make([]byte, 1))
+	netErr, ok := err.(net.Error)
+	suite.True(ok && netErr.Timeout(), err)
+	watcher.mu.Lock()
+	suite.Len(watcher.timers, 1)
+	watcher.mu.Unlock()
+
+	client2.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = client2.Read(make([]byte, 1))
+	suite.Equal(io.EOF, err)
+
+	// Disabled
+	watcher = newConnWatcher(0, nil)
+	watcher.connState(conn, http.StateNew)
+	suite.Empty(watcher.timers)
+}
+
+func (suite *GoyaveTestSuite) TestManagedGoroutine() {
+	started := make(chan struct{})
+	cancelled := false
+	finished := false
+	Go(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond) // Make sure Stop waits
+		cancelled = ctx.Err() == context.Canceled
+		finished = true
+	})
+	suite.Len(pendingWorkers, 1)
+
+	lateFinished := false
+	suite.RunServer(func(r *Router) {}, func() {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			suite.Fail("Managed goroutine not started")
+		}
+		suite.Empty(pendingWorkers)
+		Go(func(ctx context.Context) {
+			<-ctx.Done()
+			lateFinished = true
+		})
+	})
+
+	suite.True(cancelled)
+	suite.True(finished)
+	suite.True(lateFinished)
+	suite.Nil(workers)
+}
+
+func (suite *GoyaveTestSuite) TestManagedGoroutineStopNotBlocked() {
+	finished := make(chan bool, 1)
+	Go(func(ctx context.Context) {
+		<-ctx.Done()
+		// Functions using the global mutex must not block during shutdown
+		IsReady()
+		IsMaintenanceEnabled()
+		BaseURL()
+		finished <- true
+	})
+
+	start := time.Now()
+	suite.RunServer(func(r *Router) {}, func() {})
+	suite.Less(int64(time.Since(start)), int64(time.Second))
+	select {
+	case <-finished:
+	default:
+		suite.Fail("Managed goroutine didn't return before Stop")
+	}
+}
+
+func (suite *GoyaveTestSuite) TestStopWorkersTimeout() {
+	startWorkers()
+	release := make(chan struct{})
+	Go(func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	stopWorkers(ctx)
+	suite.Equal(context.DeadlineExceeded, ctx.Err())
+	suite.Nil(workers)
+	close(release)
+
+	stopWorkers(context.Background()) // No-op when not running
+}
+
 func TestGoyaveTestSuite(t *testing.T) {
 	RunTest(t, new(GoyaveTestSuite))
 }
@@ -7,6 +7,14 @@ import (
 )
 
 // File represents a file received from client.
+//
+// Files are parsed from multipart forms. Small files are kept in memory
+// while larger ones are stored in temporary files, in the directory
+// defined by the "server.uploadTempDir" config entry. If the
+// "server.cleanupUploadedFiles" config entry is enabled, the temporary
+// files are removed by the framework once the request has been handled.
+// Use "File.Save()" to persist an uploaded file, or "CleanupFiles()" to
+// release it manually.
 type File struct {
 	Data     multipart.File
 	Header   *multipart.FileHeader
@@ -21,20 +29,36 @@ type File struct {
 // Creates directories if needed.
 //
 // Returns the actual file name.
-func (file *File) Save(path string, name string) string {
+func (file *File) Save(path string, name string) (string, error) {
 	name = timestampFileName(name)
 	if err := os.MkdirAll(path, os.ModePerm); err != nil {
-		panic(err)
+		return "", err
 	}
 	writer, err := os.OpenFile(path+string(os.PathSeparator)+name, os.O_WRONLY|os.O_CREATE, 0660)
 	if err != nil {
-		panic(err)
+		return "", err
 	}
 	defer writer.Close()
-	_, errCopy := io.Copy(writer, file.Data)
-	if errCopy != nil {
-		panic(errCopy)
+	if _, err := io.Copy(writer, file.Data); err != nil {
+		return "", err
 	}
 	file.Data.Close()
-	return name
+	return name, nil
+}
+
+// CleanupFiles closes the given files and removes the temporary
+// files backing them from the disk, if any.
+// Files kept in memory are only closed.
+//
+// Errors are ignored so all files are processed.
+func CleanupFiles(files []File) {
+	for _, file := range files {
+		if file.Data == nil {
+			continue
+		}
+		file.Data.Close()
+		if f, ok := file.Data.(*os.File); ok {
+			os.Remove(f.Name())
+		}
+	}
 }
@@ -48,7 +48,15 @@ func GetMIMEType(file string) (string, int64) {
 		panic(errStat)
 	}
 
-	contentType := http.DetectContentType(buffer)
+	return DetectMIMEType(file, buffer), stat.Size()
+}
+
+// DetectMIMEType get the mime type of a file from the first 512 bytes
+// of its content. If the content is not recognized, the MIME type is
+// guessed from the extension of the given file name for the common
+// web formats (JSON, JavaScript, CSS).
+func DetectMIMEType(file string, content []byte) string {
+	contentType := http.DetectContentType(content)
 
 	if strings.HasPrefix(contentType, "application/octet-stream") || strings.HasPrefix(contentType, "text/plain") {
 		for ext, t := range contentTypeByExtension {
@@ -63,7 +71,7 @@ func GetMIMEType(file string) (string, int64) {
 		}
 	}
 
-	return contentType, stat.Size()
+	return contentType
 }
 
 // FileExists returns true if the file at the given path exists and is readable.
@@ -94,22 +102,29 @@ func Delete(path string) {
 }
 
 // ParseMultipartFiles parse a single file field in a request.
+//
+// The returned files are left open. It is the responsibility of the
+// caller to release them using "CleanupFiles()" once they are not needed anymore.
 func ParseMultipartFiles(request *http.Request, field string) []File {
 	files := []File{}
 	for _, fh := range request.MultipartForm.File[field] {
 		f, err := fh.Open()
 		if err != nil {
+			CleanupFiles(files)
 			panic(err)
 		}
-		defer f.Close()
 
 		fileHeader := make([]byte, 512)
 
 		if _, err := f.Read(fileHeader); err != nil {
+			f.Close()
+			CleanupFiles(files)
 			panic(err)
 		}
 
 		if _, err := f.Seek(0, 0); err != nil {
+			f.Close()
+			CleanupFiles(files)
 			panic(err)
 		}
 
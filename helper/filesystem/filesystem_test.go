@@ -112,7 +112,8 @@ func TestIsDirectory(t *testing.T) {
 
 func TestSaveDelete(t *testing.T) {
 	file := createTestFiles("resources/img/logo/goyave_16.png")[0]
-	actualName := file.Save(toAbsolutePath("."), "saved.png")
+	actualName, err := file.Save(toAbsolutePath("."), "saved.png")
+	assert.Nil(t, err)
 	actualPath := toAbsolutePath(actualName)
 	assert.True(t, FileExists(actualPath))
 
@@ -120,7 +121,8 @@ func TestSaveDelete(t *testing.T) {
 	assert.False(t, FileExists(actualPath))
 
 	file = createTestFiles("resources/img/logo/goyave_16.png")[0]
-	actualName = file.Save(toAbsolutePath("."), "saved")
+	actualName, err = file.Save(toAbsolutePath("."), "saved")
+	assert.Nil(t, err)
 	actualPath = toAbsolutePath(actualName)
 	assert.Equal(t, -1, strings.Index(actualName, "."))
 	assert.True(t, FileExists(actualPath))
@@ -134,7 +136,8 @@ func TestSaveDelete(t *testing.T) {
 
 	file = createTestFiles("resources/img/logo/goyave_16.png")[0]
 	path := toAbsolutePath("./subdir")
-	actualName = file.Save(path, "saved")
+	actualName, err = file.Save(path, "saved")
+	assert.Nil(t, err)
 	actualPath = toAbsolutePath("./subdir/" + actualName)
 	assert.True(t, FileExists(actualPath))
 
@@ -142,8 +145,54 @@ func TestSaveDelete(t *testing.T) {
 	assert.False(t, FileExists(actualPath))
 
 	file = createTestFiles("resources/img/logo/goyave_16.png")[0]
-	assert.Panics(t, func() {
-		file.Save(toAbsolutePath("./go.mod"), "saved")
+	actualName, err = file.Save(toAbsolutePath("./go.mod"), "saved")
+	assert.NotNil(t, err)
+	assert.Empty(t, actualName)
+}
+
+func TestSavePersistsContent(t *testing.T) {
+	file := createTestFiles("resources/img/logo/goyave_16.png")[0]
+	dir := toAbsolutePath("./subdir")
+	defer os.RemoveAll(dir)
+	actualName, err := file.Save(dir, "saved.png")
+	assert.Nil(t, err)
+
+	expected, err := ioutil.ReadFile(toAbsolutePath("resources/img/logo/goyave_16.png"))
+	if err != nil {
+		panic(err)
+	}
+	saved, err := ioutil.ReadFile(dir + "/" + actualName)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, expected, saved)
+
+	CleanupFiles([]File{file})
+}
+
+func TestCleanupFiles(t *testing.T) {
+	files := make([]File, 0, 2)
+	tmpNames := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		tmp, err := ioutil.TempFile("", "goyave-upload-")
+		if err != nil {
+			panic(err)
+		}
+		files = append(files, File{Data: tmp})
+		tmpNames = append(tmpNames, tmp.Name())
+	}
+
+	CleanupFiles(files)
+	for i, name := range tmpNames {
+		assert.False(t, FileExists(name))
+		_, err := files[i].Data.Read(make([]byte, 1))
+		assert.NotNil(t, err) // Closed
+	}
+
+	// In-memory files are only closed
+	assert.NotPanics(t, func() {
+		CleanupFiles(createTestFiles("resources/img/logo/goyave_16.png", "resources/img/logo/goyave_32.png"))
+		CleanupFiles([]File{{}})
 	})
 }
 
@@ -153,6 +202,8 @@ func TestOpenFileError(t *testing.T) {
 		os.Mkdir(dir, 0500)
 		defer os.RemoveAll(dir)
 		file := createTestFiles("resources/img/logo/goyave_16.png")[0]
-		file.Save(dir, "saved.png")
+		if _, err := file.Save(dir, "saved.png"); err != nil {
+			panic(err)
+		}
 	})
 }
@@ -0,0 +1,79 @@
+package filesystem
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v3/clock"
+	"goyave.dev/goyave/v3/encryption"
+)
+
+var (
+	// ErrInvalidSignature returned by "VerifyURL()" if the signature of the URL
+	// is missing or doesn't match its content.
+	ErrInvalidSignature = errors.New("invalid URL signature")
+
+	// ErrExpiredSignature returned by "VerifyURL()" if the signature of the URL
+	// is valid but expired.
+	ErrExpiredSignature = errors.New("expired URL signature")
+)
+
+// SignURL generates a time-limited signed URL for the given path.
+// The path can contain a query, which will be covered by the signature too.
+// The returned URL expires after the given duration.
+//
+// The signature is an HMAC-SHA256 using the application key ("app.key" config entry).
+// Use "VerifyURL()" or the "middleware.ValidateSignature" middleware to check it.
+func SignURL(path string, expiry time.Duration) (string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Del("signature")
+	query.Set("expires", strconv.FormatInt(clock.Now().Add(expiry).Unix(), 10))
+	u.RawQuery = query.Encode()
+
+	signature, err := encryption.Sign(urlMessage(u))
+	if err != nil {
+		return "", err
+	}
+	query.Set("signature", signature)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// VerifyURL checks the signature and the expiry of the given URL,
+// generated by "SignURL()".
+// Returns "ErrInvalidSignature" if the URL has been tampered with and
+// "ErrExpiredSignature" if it has expired.
+func VerifyURL(u *url.URL) error {
+	query := u.Query()
+	signature := query.Get("signature")
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if signature == "" || err != nil {
+		return ErrInvalidSignature
+	}
+
+	query.Del("signature")
+	unsigned := *u
+	unsigned.RawQuery = query.Encode()
+	if err := encryption.Verify(urlMessage(&unsigned), signature); err != nil {
+		if err == encryption.ErrInvalidSignature {
+			return ErrInvalidSignature
+		}
+		return err
+	}
+
+	if clock.Now().Unix() > expires {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+func urlMessage(u *url.URL) []byte {
+	return []byte(u.EscapedPath() + "?" + u.RawQuery)
+}
@@ -0,0 +1,76 @@
+package filesystem
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v3/config"
+)
+
+func parseTestURL(str string) *url.URL {
+	u, err := url.Parse(str)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestSignURL(t *testing.T) {
+	if err := config.LoadFrom("../../config.test.json"); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	defer config.Clear()
+	config.Set("app.key", "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=")
+
+	signed, err := SignURL("/files/private/report.pdf?download=1", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(signed, "/files/private/report.pdf?"))
+	u := parseTestURL(signed)
+	assert.Equal(t, "1", u.Query().Get("download"))
+	assert.NotEmpty(t, u.Query().Get("expires"))
+	assert.NotEmpty(t, u.Query().Get("signature"))
+	assert.Nil(t, VerifyURL(u))
+
+	// Tampered path
+	tampered := parseTestURL(signed)
+	tampered.Path = "/files/private/other.pdf"
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(tampered))
+
+	// Tampered query
+	tampered = parseTestURL(signed)
+	query := tampered.Query()
+	query.Set("download", "0")
+	tampered.RawQuery = query.Encode()
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(tampered))
+
+	// Tampered expiry
+	tampered = parseTestURL(signed)
+	query = tampered.Query()
+	query.Set("expires", "9999999999")
+	tampered.RawQuery = query.Encode()
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(tampered))
+
+	// Missing or malformed signature
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(parseTestURL("/files/private/report.pdf?expires=9999999999")))
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(parseTestURL("/files/private/report.pdf?expires=9999999999&signature=notahexstring")))
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(parseTestURL("/files/private/report.pdf?signature=abcdef")))
+
+	// Other key
+	config.Set("app.key", "9LddQLwUGC6OYXum0AqhVvMlyauNQxW7JYqV5cUW2wI=")
+	assert.Equal(t, ErrInvalidSignature, VerifyURL(u))
+	config.Set("app.key", "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=")
+
+	// Expired
+	signed, err = SignURL("/files/private/report.pdf", -time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrExpiredSignature, VerifyURL(parseTestURL(signed)))
+
+	// No key
+	config.Set("app.key", nil)
+	signed, err = SignURL("/files/private/report.pdf", time.Minute)
+	assert.NotNil(t, err)
+	assert.Empty(t, signed)
+}
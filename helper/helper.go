@@ -27,6 +27,27 @@ func Contains(slice interface{}, value interface{}) bool {
 	return IndexOf(slice, value) != -1
 }
 
+// ContainsEqual check if a slice or an array contains a value, using
+// "reflect.DeepEqual" to compare the elements. Unlike "Contains", it supports
+// non-comparable values, such as slices or maps, and returns false if the
+// given slice is not a slice nor an array.
+//
+// Values of different types are never equal: a slice of float64 doesn't
+// contain the int 2, even if it contains the float64 2.
+func ContainsEqual(slice interface{}, value interface{}) bool {
+	list := reflect.ValueOf(slice)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return false
+	}
+	length := list.Len()
+	for i := 0; i < length; i++ {
+		if reflect.DeepEqual(list.Index(i).Interface(), value) {
+			return true
+		}
+	}
+	return false
+}
+
 // IndexOfStr get the index of the given value in the given string slice,
 // or -1 if not found.
 // Prefer using this helper instead of IndexOf for better performance.
@@ -45,6 +66,72 @@ func ContainsStr(slice []string, value string) bool {
 	return IndexOfStr(slice, value) != -1
 }
 
+// ContainsInt check if an int slice contains a value.
+// Prefer using this helper instead of Contains for better performance.
+func ContainsInt(slice []int, value int) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsFloat check if a float64 slice contains a value.
+// Prefer using this helper instead of Contains for better performance.
+func ContainsFloat(slice []float64, value float64) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MapStr returns a new string slice containing the result of the
+// given function applied to each element of the given slice.
+func MapStr(slice []string, fn func(string) string) []string {
+	result := make([]string, 0, len(slice))
+	for _, v := range slice {
+		result = append(result, fn(v))
+	}
+	return result
+}
+
+// FilterStr returns a new string slice containing only the elements
+// of the given slice for which the given function returns true.
+func FilterStr(slice []string, fn func(string) bool) []string {
+	result := make([]string, 0, len(slice))
+	for _, v := range slice {
+		if fn(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// UniqueStr returns a new string slice without the duplicate elements
+// of the given slice. The order of first occurrence is preserved.
+func UniqueStr(slice []string) []string {
+	result := make([]string, 0, len(slice))
+	seen := make(map[string]struct{}, len(slice))
+	for _, v := range slice {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// RemoveEmpty returns a new string slice without the empty strings
+// of the given slice.
+func RemoveEmpty(slice []string) []string {
+	return FilterStr(slice, func(v string) bool {
+		return v != ""
+	})
+}
+
 // SliceEqual check if two generic slices are the same.
 func SliceEqual(first interface{}, second interface{}) bool {
 	l1 := reflect.ValueOf(first)
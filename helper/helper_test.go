@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,38 @@ func TestIndexOf(t *testing.T) {
 	assert.Equal(t, -1, IndexOf([]float64{1, 2, 3}, 4))
 }
 
+func TestContainsEqual(t *testing.T) {
+	cases := []struct {
+		slice    interface{}
+		value    interface{}
+		expected bool
+	}{
+		{[]string{"val1", "val2"}, "val2", true},
+		{[]string{"val1", "val2"}, "val3", false},
+		{[]int{1, 2, 3}, 2, true},
+		{[]int{1, 2, 3}, 4, false},
+		{[]float64{1, 2.5, 3}, 2.5, true},
+		{[]float64{1, 2, 3}, 2, false}, // Type mismatch
+		{[]int{1, 2, 3}, float64(2), false},
+		{[]int{1, 2, 3}, "2", false},
+		{[]bool{true}, true, true},
+		{[]bool{true}, false, false},
+		{[]interface{}{1, "a", true, 2.5}, "a", true},
+		{[]interface{}{1, "a", true, 2.5}, 2.5, true},
+		{[]interface{}{1, "a", true, 2.5}, false, false},
+		{[]interface{}{[]string{"a"}, map[string]interface{}{"k": "v"}}, map[string]interface{}{"k": "v"}, true},
+		{[]interface{}{[]string{"a"}, map[string]interface{}{"k": "v"}}, []string{"a"}, true},
+		{[]interface{}{[]string{"a"}}, []string{"b"}, false},
+		{[2]int{1, 2}, 2, true},
+		{[]int{}, 1, false},
+		{nil, 1, false},
+		{"not a slice", "n", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ContainsEqual(c.slice, c.value), c)
+	}
+}
+
 func TestContainsStr(t *testing.T) {
 	assert.True(t, ContainsStr([]string{"val1", "val2", "val3"}, "val2"))
 	assert.False(t, ContainsStr([]string{"val1", "val2", "val3"}, "val4"))
@@ -39,6 +72,109 @@ func TestIndexOfStr(t *testing.T) {
 	assert.Equal(t, -1, IndexOfStr([]string{"val1", "val2", "val3"}, "val4"))
 }
 
+func TestContainsInt(t *testing.T) {
+	cases := []struct {
+		slice    []int
+		value    int
+		expected bool
+	}{
+		{[]int{1, 2, 3}, 2, true},
+		{[]int{1, 2, 3}, 4, false},
+		{[]int{-1}, -1, true},
+		{[]int{}, 1, false},
+		{nil, 0, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ContainsInt(c.slice, c.value), c)
+	}
+}
+
+func TestContainsFloat(t *testing.T) {
+	cases := []struct {
+		slice    []float64
+		value    float64
+		expected bool
+	}{
+		{[]float64{1.5, 2, 3}, 1.5, true},
+		{[]float64{1.5, 2, 3}, 2, true},
+		{[]float64{1.5, 2, 3}, 2.5, false},
+		{[]float64{math.NaN()}, math.NaN(), false},
+		{[]float64{}, 1, false},
+		{nil, 0, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ContainsFloat(c.slice, c.value), c)
+	}
+}
+
+func TestMapStr(t *testing.T) {
+	cases := []struct {
+		slice    []string
+		expected []string
+	}{
+		{[]string{"a", "b", "c"}, []string{"A", "B", "C"}},
+		{[]string{"", "b"}, []string{"", "B"}},
+		{[]string{}, []string{}},
+		{nil, []string{}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, MapStr(c.slice, strings.ToUpper), c.slice)
+	}
+
+	slice := []string{"a"}
+	MapStr(slice, strings.ToUpper)
+	assert.Equal(t, []string{"a"}, slice) // Not modified
+}
+
+func TestFilterStr(t *testing.T) {
+	startsWithA := func(v string) bool { return strings.HasPrefix(v, "a") }
+	cases := []struct {
+		slice    []string
+		expected []string
+	}{
+		{[]string{"ab", "b", "ac"}, []string{"ab", "ac"}},
+		{[]string{"b", "c"}, []string{}},
+		{[]string{"a"}, []string{"a"}},
+		{[]string{}, []string{}},
+		{nil, []string{}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, FilterStr(c.slice, startsWithA), c.slice)
+	}
+}
+
+func TestUniqueStr(t *testing.T) {
+	cases := []struct {
+		slice    []string
+		expected []string
+	}{
+		{[]string{"b", "a", "b", "c", "a"}, []string{"b", "a", "c"}},
+		{[]string{"a", "b"}, []string{"a", "b"}},
+		{[]string{"", ""}, []string{""}},
+		{[]string{}, []string{}},
+		{nil, []string{}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, UniqueStr(c.slice), c.slice)
+	}
+}
+
+func TestRemoveEmpty(t *testing.T) {
+	cases := []struct {
+		slice    []string
+		expected []string
+	}{
+		{[]string{"a", "", "b", ""}, []string{"a", "b"}},
+		{[]string{" "}, []string{" "}},
+		{[]string{""}, []string{}},
+		{[]string{}, []string{}},
+		{nil, []string{}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, RemoveEmpty(c.slice), c.slice)
+	}
+}
+
 func TestToFloat64(t *testing.T) {
 	v, err := ToFloat64(1)
 	assert.Nil(t, err)
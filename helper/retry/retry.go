@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"goyave.dev/goyave/v3/clock"
+)
+
+// BackoffFunc returns the duration to wait before the next attempt,
+// after the given attempt failed. Attempts are numbered from 1.
+type BackoffFunc func(attempt int) time.Duration
+
+// Predicate returns true if the operation should be retried
+// after failing with the given error.
+type Predicate func(err error) bool
+
+// Constant backoff, always waiting the given duration between two attempts.
+func Constant(delay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return delay
+	}
+}
+
+// Exponential backoff, doubling the delay after each attempt, starting
+// with the given base delay. The delay never exceeds "max".
+func Exponential(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+}
+
+// Jitter randomizes the delays returned by the given backoff, so
+// clients failing at the same time don't all retry at the same time.
+// The returned delays are between half and the full original delay.
+func Jitter(backoff BackoffFunc) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+		if delay <= 1 {
+			return delay
+		}
+		half := delay / 2
+		return half + time.Duration(rand.Int63n(int64(delay-half)))
+	}
+}
+
+// Do execute "fn" until it succeeds, at most "attempts" times, waiting
+// between two attempts according to the given backoff.
+// "fn" is executed at least once, unless the context is already done
+// when calling this function: its error is then returned without
+// executing "fn".
+//
+// Returns nil as soon as "fn" succeeds, or the error returned by the last
+// attempt. If the context is done before the next attempt, its
+// error is returned instead.
+//
+//  err := retry.Do(ctx, 3, retry.Jitter(retry.Exponential(100*time.Millisecond, 2*time.Second)), func() error {
+//      resp, err := goyave.HTTPClient().Get("https://api.example.org/users")
+//      ...
+//  })
+func Do(ctx context.Context, attempts int, backoff BackoffFunc, fn func() error) error {
+	return DoIf(ctx, attempts, backoff, nil, fn)
+}
+
+// DoIf is the same as "Do" but only retries if the given predicate returns
+// true for the error returned by "fn". If the predicate is nil,
+// all errors are retried.
+//
+//  retry.DoIf(ctx, 3, retry.Constant(time.Second), func(err error) bool {
+//      return !errors.Is(err, ErrNotFound)
+//  }, fn)
+func DoIf(ctx context.Context, attempts int, backoff BackoffFunc, retryable Predicate, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt >= attempts || (retryable != nil && !retryable(err)) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(backoff(attempt)):
+		}
+	}
+}
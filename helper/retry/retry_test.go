@@ -0,0 +1,143 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v3/clock"
+)
+
+var errTest = errors.New("test error")
+
+func TestSuccessSecondAttempt(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), 3, Constant(time.Millisecond), func() error {
+		calls++
+		if calls < 2 {
+			return errTest
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestExhaustAttempts(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), 3, Constant(time.Millisecond), func() error {
+		calls++
+		return errTest
+	})
+	assert.Same(t, errTest, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Do(context.Background(), 0, Constant(time.Millisecond), func() error {
+		calls++
+		return errTest
+	})
+	assert.Same(t, errTest, err)
+	assert.Equal(t, 1, calls) // Executed at least once
+}
+
+func TestPredicate(t *testing.T) {
+	errPermanent := errors.New("permanent")
+	calls := 0
+	err := DoIf(context.Background(), 5, Constant(time.Millisecond), func(err error) bool {
+		return err != errPermanent
+	}, func() error {
+		calls++
+		if calls == 2 {
+			return errPermanent
+		}
+		return errTest
+	})
+	assert.Same(t, errPermanent, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestEarlyCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	err := Do(ctx, 5, Constant(time.Hour), func() error {
+		calls++
+		return errTest
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	// Context already done: fn is not executed
+	calls = 0
+	err = Do(ctx, 5, Constant(time.Hour), func() error {
+		calls++
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, calls)
+
+	calls = 0
+	err = DoIf(ctx, 1, Constant(time.Hour), func(error) bool { return true }, func() error {
+		calls++
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, calls)
+}
+
+func TestWaitUsesClock(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	clock.Set(fake)
+	defer clock.Reset()
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- Do(context.Background(), 3, Exponential(time.Second, time.Minute), func() error {
+			calls++
+			return errTest
+		})
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	fake.BlockUntil(1)
+	fake.Advance(2 * time.Second)
+	select {
+	case err := <-done:
+		assert.Same(t, errTest, err)
+		assert.Equal(t, 3, calls)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Retry didn't use the clock")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	constant := Constant(time.Second)
+	assert.Equal(t, time.Second, constant(1))
+	assert.Equal(t, time.Second, constant(10))
+
+	exponential := Exponential(100*time.Millisecond, time.Second)
+	assert.Equal(t, 100*time.Millisecond, exponential(1))
+	assert.Equal(t, 200*time.Millisecond, exponential(2))
+	assert.Equal(t, 400*time.Millisecond, exponential(3))
+	assert.Equal(t, 800*time.Millisecond, exponential(4))
+	assert.Equal(t, time.Second, exponential(5))
+	assert.Equal(t, time.Second, exponential(100))
+
+	jitter := Jitter(constant)
+	for i := 0; i < 100; i++ {
+		delay := jitter(1)
+		assert.GreaterOrEqual(t, int64(delay), int64(500*time.Millisecond))
+		assert.Less(t, int64(delay), int64(time.Second))
+	}
+	assert.Equal(t, time.Duration(0), Jitter(Constant(0))(1))
+}
@@ -5,6 +5,18 @@ var enUS language = language{
 		"disallow-non-validated-fields": "Non-validated fields are forbidden.",
 		"malformed-request":             "Malformed request",
 		"malformed-json":                "Malformed JSON",
+		"json-too-deep":                 "The JSON body may not be nested more than :max levels deep.",
+		"payload-too-large":             "The request body may not be larger than :max MiB.",
+		"request-timeout":               "The request took too long to be processed.",
+		"http.400":                      "Bad Request",
+		"http.401":                      "Unauthorized",
+		"http.403":                      "Forbidden",
+		"http.404":                      "Not Found",
+		"http.405":                      "Method Not Allowed",
+		"http.413":                      "Request Entity Too Large",
+		"http.429":                      "Too Many Requests",
+		"http.500":                      "Internal Server Error",
+		"http.503":                      "Service Unavailable",
 		"auth.invalid-credentials":      "These credentials don't match our records.",
 		"auth.no-credentials-provided":  "Invalid or missing authentication header.",
 		"auth.jwt-invalid":              "Your authentication token is invalid.",
@@ -121,6 +133,8 @@ var enUS language = language{
 			"uuid.array":                       "The :field values must be valid UUID:version.",
 			"bool":                             "The :field must be a boolean.",
 			"bool.array":                       "The :field values must be booleans.",
+			"boolean":                          "The :field must be a boolean.",
+			"boolean.array":                    "The :field values must be booleans.",
 			"same":                             "The :field and the :other must match.",
 			"same.array":                       "The :field values and the :other must match.",
 			"different":                        "The :field and the :other must be different.",
@@ -152,6 +166,8 @@ var enUS language = language{
 			"object.array":                     "The :field values must be objects.",
 			"unique":                           "The :field has already been taken.",
 			"unique.array":                     "At least one of the :field values has already been taken.",
+			"exists":                           "The selected :field doesn't exist.",
+			"exists.array":                     "At least one of the selected :field values doesn't exist.",
 		},
 		fields: map[string]attribute{
 			"email": {
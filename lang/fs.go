@@ -0,0 +1,94 @@
+package lang
+
+import (
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"goyave.dev/goyave/v3/helper/filesystem"
+)
+
+// fileSystem the file system language directories are read from.
+// If nil, they are read from the disk.
+var fileSystem fs.FS
+
+// SetFS sets the file system language directories are read from by "Load",
+// "LoadAllAvailableLanguages" and "Reload". This can be used to embed the
+// language files in the binary. Paths are then relative to the root of the
+// given file system and use forward slashes. Passing nil restores reading
+// from the disk.
+//
+//  //go:embed resources/lang
+//  var langFS embed.FS
+//
+//  func main() {
+//  	lang.SetFS(langFS)
+//  	// ...
+//  }
+func SetFS(fsys fs.FS) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	fileSystem = fsys
+}
+
+func isDirectory(dir string) bool {
+	if fileSystem == nil {
+		return filesystem.IsDirectory(dir)
+	}
+	stat, err := fs.Stat(fileSystem, dir)
+	return err == nil && stat.IsDir()
+}
+
+func fileExists(file string) bool {
+	if fileSystem == nil {
+		return filesystem.FileExists(file)
+	}
+	stat, err := fs.Stat(fileSystem, file)
+	return err == nil && !stat.IsDir()
+}
+
+func openFile(file string) (io.ReadCloser, error) {
+	if fileSystem == nil {
+		return os.Open(file)
+	}
+	return fileSystem.Open(file)
+}
+
+// readSubDirectories returns the names of the directories
+// contained in the given directory.
+func readSubDirectories(dir string) ([]string, error) {
+	names := []string{}
+	if fileSystem == nil {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				names = append(names, f.Name())
+			}
+		}
+		return names, nil
+	}
+
+	entries, err := fs.ReadDir(fileSystem, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func joinPath(elem ...string) string {
+	if fileSystem == nil {
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
@@ -2,16 +2,15 @@ package lang
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/helper"
-
-	"goyave.dev/goyave/v3/helper/filesystem"
 )
 
 type validationLines struct {
@@ -39,6 +38,10 @@ type language struct {
 var languages map[string]language
 var mutex = &sync.RWMutex{}
 
+// sources the directories each language has been loaded from,
+// in loading order. Used to reload languages.
+var sources = map[string][]string{}
+
 func (l *language) clone() language {
 	cpy := language{
 		lines: make(map[string]string, len(l.lines)),
@@ -70,31 +73,44 @@ func LoadDefault() {
 	defer mutex.Unlock()
 	languages = make(map[string]language, 1)
 	languages["en-US"] = enUS.clone()
+	sources = map[string][]string{}
 }
 
+// ErrNoLanguageDirectory returned by "LoadAllAvailableLanguages" if the
+// "resources/lang" directory doesn't exist. Only the default language
+// ("en-US", see "LoadDefault") is then available.
+var ErrNoLanguageDirectory = errors.New("Language directory \"resources/lang\" not found, only the default language is available")
+
 // LoadAllAvailableLanguages loads every language directory
 // in the "resources/lang" directory if it exists.
-func LoadAllAvailableLanguages() {
+// If a file system has been set with "SetFS", the "resources/lang"
+// directory is read from it instead of the working directory.
+//
+// A missing directory is not fatal: "ErrNoLanguageDirectory" is returned and
+// the languages already loaded are left untouched.
+func LoadAllAvailableLanguages() error {
 	mutex.Lock()
 	defer mutex.Unlock()
-	sep := string(os.PathSeparator)
-	workingDir, err := os.Getwd()
-	if err != nil {
-		panic(err)
+	langDirectory := joinPath("resources", "lang")
+	if fileSystem == nil {
+		workingDir, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+		langDirectory = joinPath(workingDir, langDirectory)
 	}
-	langDirectory := workingDir + sep + "resources" + sep + "lang" + sep
-	if filesystem.IsDirectory(langDirectory) {
-		files, err := ioutil.ReadDir(langDirectory)
+	if isDirectory(langDirectory) {
+		directories, err := readSubDirectories(langDirectory)
 		if err != nil {
 			panic(err)
 		}
 
-		for _, f := range files {
-			if f.IsDir() {
-				load(f.Name(), langDirectory+sep+f.Name())
-			}
+		for _, name := range directories {
+			load(name, joinPath(langDirectory, name))
 		}
+		return nil
 	}
+	return ErrNoLanguageDirectory
 }
 
 // Load a language directory.
@@ -106,10 +122,12 @@ func LoadAllAvailableLanguages() {
 //    └─ attributes.json (contains the attribute-specific validation messages)
 //
 // Each file is optional.
+//
+// If a file system has been set with "SetFS", the directory is read from it.
 func Load(language, path string) {
 	mutex.Lock()
 	defer mutex.Unlock()
-	if filesystem.IsDirectory(path) {
+	if isDirectory(path) {
 		load(language, path)
 	} else {
 		panic(fmt.Sprintf("Failed loading language \"%s\", directory \"%s\" doesn't exist", language, path))
@@ -117,11 +135,11 @@ func Load(language, path string) {
 }
 
 func load(lang string, path string) {
-	langStruct := language{}
-	sep := string(os.PathSeparator)
-	readLangFile(path+sep+"locale.json", &langStruct.lines)
-	readLangFile(path+sep+"rules.json", &langStruct.validation.rules)
-	readLangFile(path+sep+"fields.json", &langStruct.validation.fields)
+	langStruct, err := readLanguage(path)
+	if err != nil {
+		panic(err)
+	}
+	sources[lang] = append(sources[lang], path)
 
 	if existingLang, exists := languages[lang]; exists {
 		mergeLang(existingLang, langStruct)
@@ -130,16 +148,89 @@ func load(lang string, path string) {
 	}
 }
 
+// Reload reads the files of the given language again, from all the directories
+// it has been loaded from, and replaces the language lines with their new content.
+// This is useful during development to see changes to language files without
+// restarting the server. Lines added with "LoadDefault" are kept for "en-US".
+//
+// If a file cannot be read, an error is returned and the language is left untouched.
+// Language lines can safely be read concurrently.
+func Reload(lang string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return reload(lang)
+}
+
+// ReloadAll reloads every loaded language. See "Reload".
+// Stops and returns the error at the first language that cannot be reloaded.
+func ReloadAll() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for lang := range languages {
+		if err := reload(lang); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reload(lang string) error {
+	if _, exists := languages[lang]; !exists {
+		return fmt.Errorf("Cannot reload language \"%s\": language is not loaded", lang)
+	}
+	reloaded := language{
+		lines: map[string]string{},
+		validation: validationLines{
+			rules:  map[string]string{},
+			fields: map[string]attribute{},
+		},
+	}
+	if lang == "en-US" {
+		reloaded = enUS.clone()
+	}
+	for _, path := range sources[lang] {
+		langStruct, err := readLanguage(path)
+		if err != nil {
+			return err
+		}
+		mergeLang(reloaded, langStruct)
+	}
+	languages[lang] = reloaded
+	return nil
+}
+
+// readLanguage reads the language files in the given directory.
+func readLanguage(path string) (language, error) {
+	langStruct := language{}
+	if err := decodeLangFile(joinPath(path, "locale.json"), &langStruct.lines); err != nil {
+		return langStruct, err
+	}
+	if err := decodeLangFile(joinPath(path, "rules.json"), &langStruct.validation.rules); err != nil {
+		return langStruct, err
+	}
+	if err := decodeLangFile(joinPath(path, "fields.json"), &langStruct.validation.fields); err != nil {
+		return langStruct, err
+	}
+	return langStruct, nil
+}
+
 func readLangFile(path string, dest interface{}) {
-	if filesystem.FileExists(path) {
-		langFile, _ := os.Open(path)
-		defer langFile.Close()
+	if err := decodeLangFile(path, dest); err != nil {
+		panic(err)
+	}
+}
 
-		errParse := json.NewDecoder(langFile).Decode(&dest)
-		if errParse != nil {
-			panic(errParse)
+func decodeLangFile(path string, dest interface{}) error {
+	if fileExists(path) {
+		langFile, err := openFile(path)
+		if err != nil {
+			return err
 		}
+		defer langFile.Close()
+
+		return json.NewDecoder(langFile).Decode(&dest)
 	}
+	return nil
 }
 
 func mergeLang(dst language, src language) {
@@ -175,7 +266,8 @@ func mergeMap(dst map[string]string, src map[string]string) {
 // For normal lines, just use the name of the line. Note that if you have
 // a line called "validation", it won't conflict with the dot-separated paths.
 //
-// If not found, returns the exact "line" attribute.
+// If not found, returns the exact "line" attribute. If the given language
+// is not available, the default language is used.
 //
 // The placeholders parameter is a variadic associative slice of placeholders and their
 // replacement. In the following example, the placeholder ":username" will be replaced
@@ -184,7 +276,10 @@ func mergeMap(dst map[string]string, src map[string]string) {
 // 	lang.Get("en-US", "greetings", ":username", user.Name)
 func Get(lang string, line string, placeholders ...string) string {
 	if !IsAvailable(lang) {
-		return line
+		lang = defaultLanguage()
+		if !IsAvailable(lang) {
+			return line
+		}
 	}
 
 	mutex.RLock()
@@ -223,6 +318,43 @@ func Get(lang string, line string, placeholders ...string) string {
 	return convertEmptyLine(line, languages[lang].lines[line], placeholders)
 }
 
+// Getf get a language line, like "Get", replacing the placeholders
+// with the values of the given map. The keys of the map are the names of
+// the placeholders, with or without the leading colon. Placeholders that are
+// not in the map are left intact.
+//
+// 	lang.Getf("en-US", "greetings", map[string]string{"username": user.Name, "today": "Monday"})
+func Getf(lang string, line string, placeholders map[string]string) string {
+	return Get(lang, line, placeholderPairs(placeholders)...)
+}
+
+// placeholderPairs converts the given placeholder map to an associative
+// slice. Longest placeholders come first so a placeholder that is the
+// prefix of another one (":user" and ":username") doesn't replace part of it.
+func placeholderPairs(placeholders map[string]string) []string {
+	keys := make([]string, 0, len(placeholders))
+	for k := range placeholders {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := strings.TrimPrefix(keys[i], ":"), strings.TrimPrefix(keys[j], ":")
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		placeholder := k
+		if !strings.HasPrefix(placeholder, ":") {
+			placeholder = ":" + placeholder
+		}
+		pairs = append(pairs, placeholder, placeholders[k])
+	}
+	return pairs
+}
+
 func processPlaceholders(message string, values []string) string {
 	length := len(values) - 1
 	for i := 0; i < length; i += 2 {
@@ -238,6 +370,15 @@ func convertEmptyLine(entry, line string, placeholders []string) string {
 	return processPlaceholders(line, placeholders)
 }
 
+// defaultLanguage returns the language defined by the "app.defaultLanguage"
+// config entry, or "en-US" if the config is not loaded.
+func defaultLanguage() string {
+	if config.IsLoaded() {
+		return config.GetString("app.defaultLanguage")
+	}
+	return "en-US"
+}
+
 // IsAvailable returns true if the language is available.
 func IsAvailable(lang string) bool {
 	mutex.RLock()
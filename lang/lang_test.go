@@ -1,9 +1,13 @@
 package lang
 
 import (
+	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/suite"
 	"goyave.dev/goyave/v3/config"
@@ -138,6 +142,123 @@ func (suite *LangTestSuite) TestPlaceholders() {
 	suite.Equal("Greetings, Kevin, today is :today", convertEmptyLine("greetings", "Greetings, :username, today is :today", []string{":username", "Kevin", ":today"}))
 }
 
+func (suite *LangTestSuite) TestGetf() {
+	suite.Equal("The request body may not be larger than 10 MiB.", Getf("en-US", "payload-too-large", map[string]string{"max": "10"}))
+	suite.Equal("The age must be between 18 and 99.", Getf("en-US", "validation.rules.between.numeric", map[string]string{":field": "age", "min": "18", ":max": "99"}))
+	suite.Equal("The age must be between 18 and :max.", Getf("en-US", "validation.rules.between.numeric", map[string]string{"field": "age", "min": "18", "unknown": "value"}))
+	suite.Equal("The :field must be between :min and :max.", Getf("en-US", "validation.rules.between.numeric", nil))
+	suite.Equal("unknown-line", Getf("en-US", "unknown-line", map[string]string{"name": "value"}))
+
+	// Placeholders sharing a prefix
+	pairs := placeholderPairs(map[string]string{"user": "Kevin", "username": "kevin42"})
+	suite.Equal([]string{":username", "kevin42", ":user", "Kevin"}, pairs)
+	suite.Equal("Kevin (kevin42)", convertEmptyLine("line", ":user (:username)", pairs))
+}
+
+func (suite *LangTestSuite) TestReload() {
+	dir, err := ioutil.TempDir("", "goyave-lang")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	writeLocale := func(content string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, "locale.json"), []byte(content), 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	writeLocale(`{"greetings": "Hello, :username", "removed": "removed line"}`)
+	Load("reload-TEST", dir)
+	suite.Equal("Hello, Kevin", Get("reload-TEST", "greetings", ":username", "Kevin"))
+
+	writeLocale(`{"greetings": "Hi, :username"}`)
+	suite.Equal("Hello, Kevin", Get("reload-TEST", "greetings", ":username", "Kevin"))
+
+	// Concurrent reads are safe
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			Get("reload-TEST", "greetings")
+		}
+	}()
+	suite.Nil(Reload("reload-TEST"))
+	<-done
+	suite.Equal("Hi, Kevin", Get("reload-TEST", "greetings", ":username", "Kevin"))
+	suite.Equal("removed", Get("reload-TEST", "removed"))
+
+	// Invalid file: language left untouched
+	writeLocale(`{"greetings": `)
+	suite.NotNil(Reload("reload-TEST"))
+	suite.Equal("Hi, Kevin", Get("reload-TEST", "greetings", ":username", "Kevin"))
+	suite.NotNil(ReloadAll())
+
+	writeLocale(`{"greetings": "Welcome, :username"}`)
+	suite.Nil(ReloadAll())
+	suite.Equal("Welcome, Kevin", Get("reload-TEST", "greetings", ":username", "Kevin"))
+
+	// Defaults are kept for en-US
+	suite.Equal("Malformed JSON", Get("en-US", "malformed-json"))
+
+	err = Reload("notalanguage")
+	suite.Equal("Cannot reload language \"notalanguage\": language is not loaded", err.Error())
+}
+
+func (suite *LangTestSuite) TestLoadFromFS() {
+	defer SetFS(nil)
+	fsys := fstest.MapFS{
+		"resources/lang/fs-TEST/locale.json": {Data: []byte(`{"greetings": "Hello from fs"}`)},
+		"resources/lang/fs-TEST/rules.json":  {Data: []byte(`{"required": "The :field is required (fs)."}`)},
+		"other/fs-OTHER/locale.json":         {Data: []byte(`{"greetings": "Hello from other"}`)},
+	}
+	SetFS(fsys)
+
+	LoadAllAvailableLanguages()
+	suite.True(IsAvailable("fs-TEST"))
+	suite.Equal("Hello from fs", Get("fs-TEST", "greetings"))
+	suite.Equal("The :field is required (fs).", Get("fs-TEST", "validation.rules.required"))
+
+	Load("fs-OTHER", "other/fs-OTHER")
+	suite.Equal("Hello from other", Get("fs-OTHER", "greetings"))
+	suite.Panics(func() {
+		Load("fs-MISSING", "other/fs-MISSING")
+	})
+
+	fsys["other/fs-OTHER/locale.json"] = &fstest.MapFile{Data: []byte(`{"greetings": "Reloaded from other"}`)}
+	suite.Nil(Reload("fs-OTHER"))
+	suite.Equal("Reloaded from other", Get("fs-OTHER", "greetings"))
+}
+
+func (suite *LangTestSuite) TestMissingLanguageDirectory() {
+	dir, err := ioutil.TempDir("", "goyave-lang")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	defer os.Chdir(wd)
+
+	prev, prevSources := languages, sources
+	defer func() {
+		languages, sources = prev, prevSources
+	}()
+	LoadDefault()
+	suite.Equal(ErrNoLanguageDirectory, LoadAllAvailableLanguages())
+	suite.Equal([]string{"en-US"}, GetAvailableLanguages())
+	suite.Equal("Malformed JSON", Get("en-US", "malformed-json"))
+
+	// Unknown languages fall back to the default language
+	suite.Equal("Malformed JSON", Get("unknown-LANG", "malformed-json"))
+	suite.Equal("The email address must be a valid email address.", Getf("unknown-LANG", "validation.rules.email", map[string]string{"field": "email address"}))
+	suite.Equal("doesn't.exist", Get("unknown-LANG", "doesn't.exist"))
+}
+
 func (suite *LangTestSuite) TearDownAllSuite() {
 	languages = map[string]language{}
 }
@@ -0,0 +1,171 @@
+package log
+
+import (
+	"fmt"
+	stdlog "log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/clock"
+	"goyave.dev/goyave/v3/config"
+)
+
+const (
+	// AccessLogNone value of the "log.accessLog" config entry
+	// disabling access logs.
+	AccessLogNone = "none"
+
+	// AccessLogStdout value of the "log.accessLog" config entry
+	// writing access logs to "goyave.AccessLogger" (stdout by default).
+	AccessLogStdout = "stdout"
+)
+
+func init() {
+	config.Register("log.accessLog", config.Entry{
+		Value:            AccessLogNone,
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []interface{}{},
+	})
+	config.Register("log.accessLogFormat", config.Entry{
+		Value:            "common",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []interface{}{},
+	})
+}
+
+var (
+	accessLogFile  *os.File
+	accessLogger   *stdlog.Logger
+	accessLogMutex sync.Mutex
+)
+
+// AccessLogMiddleware writes one line per completed request to the
+// destination defined by the "log.accessLog" config entry:
+//  - "none" (default): access logs are disabled
+//  - "stdout": access logs are written to "goyave.AccessLogger"
+//  - any other value is the path of the file the logs are appended to.
+//    The file is created if it doesn't exist. Use "ReopenAccessLog()"
+//    after rotating it.
+//
+// The format of the lines is defined by the "log.accessLogFormat" config entry:
+// "common" (default) for the Common Log Format, "combined" for the Combined
+// Log Format, or a template (see "TemplateFormatter").
+//
+// Access logs are distinct from the application logs written
+// with "goyave.GetLogger()". Requests already logged by another
+// logging middleware are not logged twice.
+func AccessLogMiddleware() goyave.Middleware {
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			destination := config.GetString("log.accessLog")
+			if destination == AccessLogNone {
+				next(response, request)
+				return
+			}
+			logger, err := getAccessLogger(destination)
+			if err != nil {
+				goyave.GetLogger().Errorf("%v", err)
+				next(response, request)
+				return
+			}
+			logRequest(response, request, getFormatter(config.GetString("log.accessLogFormat")), logger, next)
+		}
+	}
+}
+
+// ReopenAccessLog closes the access log file and opens it again on the next
+// request. Call this after the file has been moved by a log rotation tool,
+// for example when receiving "SIGHUP".
+func ReopenAccessLog() error {
+	accessLogMutex.Lock()
+	defer accessLogMutex.Unlock()
+	return closeAccessLog()
+}
+
+func closeAccessLog() error {
+	accessLogger = nil
+	if accessLogFile == nil {
+		return nil
+	}
+	err := accessLogFile.Close()
+	accessLogFile = nil
+	return err
+}
+
+func getAccessLogger(destination string) (*stdlog.Logger, error) {
+	if destination == AccessLogStdout {
+		return goyave.AccessLogger, nil
+	}
+	accessLogMutex.Lock()
+	defer accessLogMutex.Unlock()
+	if accessLogFile != nil && accessLogFile.Name() == destination {
+		return accessLogger, nil
+	}
+	if err := closeAccessLog(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(destination, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open access log file: %w", err)
+	}
+	accessLogFile = file
+	accessLogger = stdlog.New(file, "", 0)
+	return accessLogger, nil
+}
+
+func getFormatter(format string) Formatter {
+	switch format {
+	case "common":
+		return CommonLogFormatter
+	case "combined":
+		return CombinedLogFormatter
+	}
+	return TemplateFormatter(format)
+}
+
+// TemplateFormatter returns a formatter building log entries from the given
+// template. The following placeholders are replaced:
+//  - ":remote": the remote address of the client
+//  - ":user": the user from the request URL, or "-"
+//  - ":time": the time the request started, in the Common Log Format
+//  - ":method", ":uri" and ":proto": the request line
+//  - ":status": the response status code
+//  - ":size": the length of the response body, in bytes
+//  - ":duration": the time taken to handle the request, in milliseconds
+//  - ":referrer" and ":user-agent": the corresponding request headers
+//  - ":request-id": the request ID (see "middleware.RequestID"), or "-"
+//
+//  log.TemplateFormatter(":method :uri :status :size :duration")
+func TemplateFormatter(template string) Formatter {
+	return func(now time.Time, response *goyave.Response, request *goyave.Request, length int) string {
+		req := request.Request()
+		requestID := request.ID()
+		if requestID == "" {
+			requestID = "-"
+		}
+		duration := float64(clock.Since(now)) / float64(time.Millisecond)
+		replacer := strings.NewReplacer(
+			// Placeholders sharing a prefix must be listed longest first
+			":user-agent", request.UserAgent(),
+			":user", username(request),
+			":remote", remoteHost(request),
+			":time", now.Format(TimestampFormat),
+			":method", req.Method,
+			":uri", requestURI(request),
+			":proto", req.Proto,
+			":status", strconv.Itoa(response.GetStatus()),
+			":size", strconv.Itoa(length),
+			":duration", strconv.FormatFloat(duration, 'f', 3, 64),
+			":referrer", request.Referrer(),
+			":request-id", requestID,
+		)
+		return replacer.Replace(template)
+	}
+}
@@ -0,0 +1,132 @@
+package log
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/config"
+)
+
+type AccessLogTestSuite struct {
+	goyave.TestSuite
+	buffer *bytes.Buffer
+}
+
+func (suite *AccessLogTestSuite) SetupTest() {
+	suite.buffer = &bytes.Buffer{}
+	goyave.AccessLogger.SetOutput(suite.buffer)
+}
+
+func (suite *AccessLogTestSuite) TearDownTest() {
+	goyave.AccessLogger.SetOutput(os.Stdout)
+	config.Set("log.accessLog", AccessLogNone)
+	config.Set("log.accessLogFormat", "common")
+	if err := ReopenAccessLog(); err != nil {
+		panic(err)
+	}
+}
+
+func (suite *AccessLogTestSuite) request(middleware ...goyave.Middleware) {
+	request := suite.CreateTestRequest(httptest.NewRequest("GET", "/log?page=2", nil))
+	handler := func(response *goyave.Response, request *goyave.Request) {
+		response.String(http.StatusOK, "message")
+	}
+	for i := len(middleware) - 1; i > 0; i-- {
+		handler = middleware[i](handler)
+	}
+	suite.Middleware(middleware[0], request, handler).Body.Close()
+}
+
+func (suite *AccessLogTestSuite) TestDisabled() {
+	suite.Equal(AccessLogNone, config.GetString("log.accessLog"))
+	suite.request(AccessLogMiddleware())
+	suite.Empty(suite.buffer.String())
+}
+
+func (suite *AccessLogTestSuite) TestTemplateFormat() {
+	clock := suite.UseFakeClock(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	defer suite.RestoreClock()
+
+	config.Set("log.accessLog", AccessLogStdout)
+	config.Set("log.accessLogFormat", ":remote [:time] \":method :uri :proto\" :status :size :duration :user :user-agent :request-id")
+	request := suite.CreateTestRequest(httptest.NewRequest("GET", "/log?page=2", nil))
+	request.Header().Set("User-Agent", "test-agent")
+	request.Extra[goyave.RequestIDExtraKey] = "request-id"
+	suite.Middleware(AccessLogMiddleware(), request, func(response *goyave.Response, request *goyave.Request) {
+		clock.Advance(15 * time.Millisecond)
+		response.String(http.StatusOK, "message")
+	}).Body.Close()
+
+	suite.Equal("192.0.2.1 [04/Mar/2021:05:06:07 +0000] \"GET /log?page=2 HTTP/1.1\" 200 7 15.000 - test-agent request-id\n", suite.buffer.String())
+}
+
+func (suite *AccessLogTestSuite) TestCommonFormats() {
+	config.Set("log.accessLog", AccessLogStdout)
+	suite.request(AccessLogMiddleware())
+	suite.Regexp(`^192\.0\.2\.1 - - \[.+\] "GET "/log\?page=2" HTTP/1\.1" 200 7\n$`, suite.buffer.String())
+
+	suite.buffer.Reset()
+	config.Set("log.accessLogFormat", "combined")
+	suite.request(AccessLogMiddleware())
+	suite.Regexp(`^192\.0\.2\.1 - - \[.+\] "GET "/log\?page=2" HTTP/1\.1" 200 7 "" ""\n$`, suite.buffer.String())
+}
+
+func (suite *AccessLogTestSuite) TestFile() {
+	dir, err := ioutil.TempDir("", "goyave-access-log")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "access.log")
+
+	config.Set("log.accessLog", path)
+	config.Set("log.accessLogFormat", ":method :uri :status :size")
+	suite.request(AccessLogMiddleware())
+	suite.request(AccessLogMiddleware())
+
+	content, err := ioutil.ReadFile(path)
+	suite.Nil(err)
+	suite.Equal("GET /log?page=2 200 7\nGET /log?page=2 200 7\n", string(content))
+	suite.Empty(suite.buffer.String())
+
+	// Rotation
+	rotated := filepath.Join(dir, "access.log.1")
+	suite.Nil(os.Rename(path, rotated))
+	suite.Nil(ReopenAccessLog())
+	suite.request(AccessLogMiddleware())
+
+	content, err = ioutil.ReadFile(path)
+	suite.Nil(err)
+	suite.Equal("GET /log?page=2 200 7\n", string(content))
+	content, err = ioutil.ReadFile(rotated)
+	suite.Nil(err)
+	suite.Equal("GET /log?page=2 200 7\nGET /log?page=2 200 7\n", string(content))
+}
+
+func (suite *AccessLogTestSuite) TestFileError() {
+	config.Set("log.accessLog", filepath.Join("doesn't exist", "access.log"))
+	executed := false
+	request := suite.CreateTestRequest(nil)
+	suite.Middleware(AccessLogMiddleware(), request, func(response *goyave.Response, request *goyave.Request) {
+		executed = true
+	}).Body.Close()
+	suite.True(executed)
+}
+
+func (suite *AccessLogTestSuite) TestNotDuplicated() {
+	config.Set("log.accessLog", AccessLogStdout)
+	config.Set("log.accessLogFormat", ":method :uri")
+	suite.request(AccessLogMiddleware(), CommonLogMiddleware())
+	suite.Equal("GET /log?page=2\n", suite.buffer.String())
+}
+
+func TestAccessLogTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(AccessLogTestSuite))
+}
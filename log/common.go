@@ -2,7 +2,6 @@ package log
 
 import (
 	"fmt"
-	"net"
 	"strconv"
 	"time"
 
@@ -20,21 +19,35 @@ const (
 // CommonLogFormatter build a log entry using the Common Log Format.
 func CommonLogFormatter(now time.Time, response *goyave.Response, request *goyave.Request, length int) string {
 	req := request.Request()
-	url := request.URI()
+	return fmt.Sprintf(Format,
+		remoteHost(request),
+		"-",
+		username(request),
+		now.Format(TimestampFormat),
+		req.Method,
+		strconv.QuoteToASCII(requestURI(request)),
+		req.Proto,
+		response.GetStatus(),
+		length,
+	)
+}
 
-	username := "-"
+func username(request *goyave.Request) string {
+	url := request.URI()
 	if url.User != nil {
 		if name := url.User.Username(); name != "" {
-			username = name
+			return name
 		}
 	}
+	return "-"
+}
 
-	host, _, err := net.SplitHostPort(req.RemoteAddr)
-
-	if err != nil {
-		host = req.RemoteAddr
-	}
+func remoteHost(request *goyave.Request) string {
+	return request.ClientIP()
+}
 
+func requestURI(request *goyave.Request) string {
+	req := request.Request()
 	uri := req.RequestURI
 
 	// Requests using the CONNECT method over HTTP/2.0 must use
@@ -44,20 +57,9 @@ func CommonLogFormatter(now time.Time, response *goyave.Response, request *goyav
 		uri = req.Host
 	}
 	if uri == "" {
-		uri = url.RequestURI()
+		uri = request.URI().RequestURI()
 	}
-
-	return fmt.Sprintf(Format,
-		host,
-		"-",
-		username,
-		now.Format(TimestampFormat),
-		req.Method,
-		strconv.QuoteToASCII(uri),
-		req.Proto,
-		response.GetStatus(),
-		length,
-	)
+	return uri
 }
 
 // CombinedLogFormatter build a log entry using the Combined Log Format.
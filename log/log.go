@@ -2,11 +2,17 @@ package log
 
 import (
 	"io"
+	stdlog "log"
 	"time"
 
 	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/clock"
 )
 
+// writerExtraKey the key of the log writer in "Request.Extra".
+// Used to make sure a request is only logged once.
+const writerExtraKey = "logWriter"
+
 // Formatter is a function that builds a log entry.
 // As logs are written at the end of the request's lifecycle, all the
 // data is available to formatters at the time they are called, and all
@@ -21,6 +27,7 @@ type Writer struct {
 	now       time.Time
 	request   *goyave.Request
 	response  *goyave.Response
+	logger    *stdlog.Logger
 	length    int
 }
 
@@ -32,7 +39,7 @@ var _ goyave.PreWriter = (*Writer)(nil)
 // formatter.
 func NewWriter(response *goyave.Response, request *goyave.Request, formatter Formatter) *Writer {
 	return &Writer{
-		now:       time.Now(),
+		now:       clock.Now(),
 		request:   request,
 		writer:    response.Writer(),
 		response:  response,
@@ -58,7 +65,11 @@ func (w *Writer) Write(b []byte) (int, error) {
 // Close the writer and its child ResponseWriter, flushing response
 // output to the logs.
 func (w *Writer) Close() error {
-	goyave.AccessLogger.Println(w.formatter(w.now, w.response, w.request, w.length))
+	logger := w.logger
+	if logger == nil {
+		logger = goyave.AccessLogger
+	}
+	logger.Println(w.formatter(w.now, w.response, w.request, w.length))
 
 	if wr, ok := w.writer.(io.Closer); ok {
 		return wr.Close()
@@ -68,17 +79,34 @@ func (w *Writer) Close() error {
 
 // Middleware captures response data and outputs it to the default logger
 // using the given formatter.
+//
+// Requests are only logged once: if another logging middleware
+// (including "AccessLogMiddleware") already handles the request,
+// this middleware does nothing.
 func Middleware(formatter Formatter) goyave.Middleware {
 	return func(next goyave.Handler) goyave.Handler {
 		return func(response *goyave.Response, request *goyave.Request) {
-			logWriter := NewWriter(response, request, formatter)
-			response.SetWriter(logWriter)
-
-			next(response, request)
+			logRequest(response, request, formatter, nil, next)
 		}
 	}
 }
 
+func logRequest(response *goyave.Response, request *goyave.Request, formatter Formatter, logger *stdlog.Logger, next goyave.Handler) {
+	if _, ok := request.Extra[writerExtraKey]; ok {
+		next(response, request)
+		return
+	}
+	logWriter := NewWriter(response, request, formatter)
+	logWriter.logger = logger
+	response.SetWriter(logWriter)
+	if request.Extra == nil {
+		request.Extra = make(map[string]interface{})
+	}
+	request.Extra[writerExtraKey] = logWriter
+
+	next(response, request)
+}
+
 // CommonLogMiddleware captures response data and outputs it to the default logger
 // using the common log format.
 func CommonLogMiddleware() goyave.Middleware {
@@ -0,0 +1,83 @@
+package goyave
+
+import (
+	"sync/atomic"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+// LeveledLogger logger used by the framework to write its messages,
+// such as server errors or recovered panics.
+//
+// Its methods match the ones of the most common logging libraries
+// (for example zap's "SugaredLogger" or logrus' "Logger"), so they can be
+// used directly with "SetLogger()". Access logs are not affected and
+// are still written to "AccessLogger".
+type LeveledLogger interface {
+	// Debugf logs a message only useful for debugging.
+	Debugf(format string, args ...interface{})
+
+	// Infof logs an informational message.
+	Infof(format string, args ...interface{})
+
+	// Warnf logs a message about an abnormal situation
+	// which doesn't prevent the application from working.
+	Warnf(format string, args ...interface{})
+
+	// Errorf logs an error.
+	Errorf(format string, args ...interface{})
+}
+
+// defaultLogger the logger used if none has been set with "SetLogger()".
+// Debug and info messages are written to "goyave.Logger" (stdout by default),
+// warnings and errors to "goyave.ErrLogger" (stderr by default).
+// Debug messages are discarded unless the "app.debug" config entry is enabled.
+type defaultLogger struct{}
+
+func (defaultLogger) Debugf(format string, args ...interface{}) {
+	if config.IsLoaded() && config.GetBool("app.debug") {
+		Logger.Printf(format, args...)
+	}
+}
+
+func (defaultLogger) Infof(format string, args ...interface{}) {
+	Logger.Printf(format, args...)
+}
+
+func (defaultLogger) Warnf(format string, args ...interface{}) {
+	ErrLogger.Printf(format, args...)
+}
+
+func (defaultLogger) Errorf(format string, args ...interface{}) {
+	ErrLogger.Printf(format, args...)
+}
+
+// loggerHolder wraps the logger so it always has the same concrete
+// type when stored in the atomic value.
+type loggerHolder struct {
+	logger LeveledLogger
+}
+
+var currentLogger atomic.Value
+
+func init() {
+	SetLogger(nil)
+}
+
+// SetLogger replace the logger used by the framework. Set to nil
+// to restore the default logger, writing to "goyave.Logger" and
+// "goyave.ErrLogger".
+//
+//  zapLogger, _ := zap.NewProduction()
+//  goyave.SetLogger(zapLogger.Sugar())
+func SetLogger(logger LeveledLogger) {
+	if logger == nil {
+		logger = defaultLogger{}
+	}
+	currentLogger.Store(loggerHolder{logger})
+}
+
+// GetLogger returns the logger used by the framework.
+func GetLogger() LeveledLogger {
+	return currentLogger.Load().(loggerHolder).logger
+}
@@ -0,0 +1,117 @@
+package goyave
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+type logEntry struct {
+	level   string
+	message string
+}
+
+type testLogger struct {
+	entries []logEntry
+	mu      sync.Mutex
+}
+
+func (l *testLogger) log(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level, fmt.Sprintf(format, args...)})
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) { l.log("debug", format, args...) }
+func (l *testLogger) Infof(format string, args ...interface{})  { l.log("info", format, args...) }
+func (l *testLogger) Warnf(format string, args ...interface{})  { l.log("warn", format, args...) }
+func (l *testLogger) Errorf(format string, args ...interface{}) { l.log("error", format, args...) }
+
+type LoggerTestSuite struct {
+	TestSuite
+}
+
+func (suite *LoggerTestSuite) TestSetLogger() {
+	suite.IsType(defaultLogger{}, GetLogger())
+
+	logger := &testLogger{}
+	SetLogger(logger)
+	suite.Equal(logger, GetLogger())
+
+	SetLogger(nil)
+	suite.IsType(defaultLogger{}, GetLogger())
+}
+
+func (suite *LoggerTestSuite) TestDefaultLogger() {
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	prevLogger, prevErrLogger := Logger, ErrLogger
+	Logger, ErrLogger = log.New(out, "", 0), log.New(errOut, "", 0)
+	defer func() {
+		Logger, ErrLogger = prevLogger, prevErrLogger
+	}()
+
+	prevDebug := config.GetBool("app.debug")
+	defer config.Set("app.debug", prevDebug)
+	config.Set("app.debug", false)
+
+	logger := GetLogger()
+	logger.Debugf("debug %d", 1)
+	logger.Infof("info %d", 2)
+	logger.Warnf("warn %d", 3)
+	logger.Errorf("error %d", 4)
+	suite.Equal("info 2\n", out.String())
+	suite.Equal("warn 3\nerror 4\n", errOut.String())
+
+	config.Set("app.debug", true)
+	out.Reset()
+	logger.Debugf("debug %d", 1)
+	suite.Equal("debug 1\n", out.String())
+}
+
+func (suite *LoggerTestSuite) TestFrameworkMessages() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	prevDebug := config.GetBool("app.debug")
+	defer config.Set("app.debug", prevDebug)
+	config.Set("app.debug", false)
+
+	// Recovered panic
+	request := suite.CreateTestRequest(httptest.NewRequest("GET", "/panic", nil))
+	suite.Middleware(recoveryMiddleware, request, func(response *Response, r *Request) {
+		panic("test panic")
+	}).Body.Close()
+
+	// Response.Error
+	response := suite.CreateTestResponse(httptest.NewRecorder())
+	response.Error(fmt.Errorf("test error"))
+
+	// Connection state hook
+	conn, other := net.Pipe()
+	LogConnState(conn, http.StateNew)
+	conn.Close()
+	other.Close()
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if suite.Len(logger.entries, 3) {
+		suite.Equal("error", logger.entries[0].level)
+		suite.True(strings.HasPrefix(logger.entries[0].message, "GET /panic test panic\n"))
+		suite.Equal(logEntry{"error", "test error"}, logger.entries[1])
+		suite.Equal(logEntry{"info", "Connection pipe: new"}, logger.entries[2])
+	}
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	RunTest(t, new(LoggerTestSuite))
+}
@@ -2,19 +2,32 @@ package goyave
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
 	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/helper/filesystem"
 	"goyave.dev/goyave/v3/lang"
+	"goyave.dev/goyave/v3/validation"
 )
 
+// uploadMemoryLimit the maximum total size of the uploaded files kept in memory
+// when parsing a multipart form. Files exceeding this limit are written to
+// temporary files.
+var uploadMemoryLimit int64 = 1 << 20
+
 // Middleware function generating middleware handler function.
 //
 // Request data is available to middleware, but bear in mind that
@@ -25,15 +38,44 @@ type Middleware func(Handler) Handler
 // recoveryMiddleware is a middleware that recovers from panic and sends a 500 error code.
 // If debugging is enabled in the config and the default status handler for the 500 status code
 // had not been changed, the error is also written in the response.
+//
+// The error is logged with the stack trace, the method and path of the request
+// and its ID if the "middleware.RequestID" middleware is applied. The ID is
+// also sent back in the response headers so clients can report it.
+//
+// Panics caused by a client disconnection (see "IsClientDisconnect()") are
+// only logged at debug level. "http.ErrAbortHandler" is then re-panicked so
+// the HTTP server aborts the response instead of completing it. Panics caused by
+// an exceeded request deadline ("context.DeadlineExceeded" while the request
+// context is done) are logged as warnings and result in a "504 Gateway Timeout" response.
 func recoveryMiddleware(next Handler) Handler {
 	return func(response *Response, r *Request) {
 		panicked := true
 		defer func() {
-			if err := recover(); err != nil || panicked {
-				ErrLogger.Println(err)
+			err := recover()
+			if e, ok := err.(error); ok {
+				if errors.Is(e, context.DeadlineExceeded) && r.Request().Context().Err() == context.DeadlineExceeded {
+					response.deadlineExceeded(e)
+					return
+				}
+				if response.isClientDisconnect(e) {
+					response.clientDisconnected(e)
+					if errors.Is(e, http.ErrAbortHandler) {
+						// Let the HTTP server abort the response
+						panic(http.ErrAbortHandler)
+					}
+					return
+				}
+			}
+			if err != nil || panicked {
+				stacktrace := string(debug.Stack())
+				logPanic(r, err, stacktrace)
 				response.err = err
 				if config.GetBool("app.debug") {
-					response.stacktrace = string(debug.Stack())
+					response.stacktrace = stacktrace
+				}
+				if id := r.ID(); id != "" {
+					response.Header().Set(RequestIDHeader, id)
 				}
 				response.Status(http.StatusInternalServerError)
 			}
@@ -44,10 +86,27 @@ func recoveryMiddleware(next Handler) Handler {
 	}
 }
 
+func logPanic(r *Request, err interface{}, stacktrace string) {
+	prefix := ""
+	if r.httpRequest != nil {
+		prefix = r.httpRequest.Method + " " + r.httpRequest.URL.Path + " "
+	}
+	if id := r.ID(); id != "" {
+		prefix += "[" + id + "] "
+	}
+	GetLogger().Errorf("%s%v\n%s", prefix, err, stacktrace)
+}
+
 // parseRequestMiddleware is a middleware that parses the request data.
 //
 // If the parsing fails, the request's data is set to nil. If it succeeds
 // and there is no data, the request's data is set to an empty map.
+// Filesystem errors, such as an upload temporary directory that cannot be created,
+// are not parsing failures: the error is logged, the middleware doesn't call "next()"
+// and sets the response status code to "500 Internal Server Error".
+// An absent or empty body is not considered as a parsing failure, even
+// if the "Content-Type" header is set: the request's data then only
+// contains the query parameters.
 //
 // If the "Content-Type: application/json" header is set, the middleware
 // will attempt to unmarshal the request's body.
@@ -55,27 +114,59 @@ func recoveryMiddleware(next Handler) Handler {
 // This middleware doesn't drain the request body to maximize compatibility
 // with native handlers.
 //
-// The maximum length of the data is limited by the "maxUploadSize" config entry.
+// Bodies compressed with "gzip" or "deflate" (as indicated by the "Content-Encoding" header)
+// are decompressed before being parsed. Other encodings are rejected with
+// "415 Unsupported Media Type".
+//
+// The maximum length of the data is limited by the "server.maxUploadSize" config entry,
+// read every time a request is parsed. This limit applies to the whole body, including
+// multipart forms and the files they contain. For compressed bodies, the limit applies
+// to the decompressed data.
 // If a request exceeds the maximum size, the middleware doesn't call "next()" and
 // sets the response status code to "413 Payload Too Large".
+//
+// JSON bodies nesting objects and arrays deeper than the "server.maxJSONDepth"
+// config entry are rejected before being decoded: the middleware doesn't call "next()"
+// and sets the response status code to "400 Bad Request". A value lower than or equal
+// to 0 disables this limit.
+//
+// If "server.cleanupUploadedFiles" is enabled (disabled by default), the uploaded files
+// are closed and their temporary files removed once "next()" returns. Handlers must
+// then save the files they want to keep before returning.
 func parseRequestMiddleware(next Handler) Handler {
 	return func(response *Response, request *Request) {
 
 		request.Data = nil
+		request.rawBody = nil
+		if request.httpRequest.Body == nil {
+			request.httpRequest.Body = http.NoBody
+		}
 		contentType := request.httpRequest.Header.Get("Content-Type")
-		if contentType == "" {
-			// If the Content-Type is not set, don't parse body
+		if contentType == "" || request.httpRequest.Body == http.NoBody {
+			// If the Content-Type is not set or if there is no body, don't parse body
 			request.httpRequest.Body.Close()
-			request.Data = make(map[string]interface{})
-			if err := parseQuery(request); err != nil {
-				request.Data = nil
-			}
+			parseEmptyBody(request)
 		} else {
-			maxSize := maxPayloadSize
+			var body io.Reader = request.httpRequest.Body
+			decoder, status := decodeRequestBody(request.httpRequest)
+			if status != 0 {
+				request.httpRequest.Body.Close()
+				response.Status(status)
+				return
+			}
+			if decoder != nil {
+				body = decoder
+			}
+			maxSize := getMaxPayloadSize()
 			maxValueBytes := maxSize
 			var bodyBuf bytes.Buffer
-			n, err := io.CopyN(&bodyBuf, request.httpRequest.Body, maxValueBytes+1)
+			n, err := io.CopyN(&bodyBuf, body, maxValueBytes+1)
 			request.httpRequest.Body.Close()
+			if decoder != nil {
+				// The body is not encoded anymore once it has been read.
+				request.httpRequest.Header.Del("Content-Encoding")
+				request.httpRequest.ContentLength = int64(bodyBuf.Len())
+			}
 			if err == nil || err == io.EOF {
 				maxValueBytes -= n
 				if maxValueBytes < 0 {
@@ -84,59 +175,315 @@ func parseRequestMiddleware(next Handler) Handler {
 				}
 
 				bodyBytes := bodyBuf.Bytes()
-				if strings.HasPrefix(contentType, "application/json") {
+				if len(bodyBytes) > 0 {
+					request.rawBody = bodyBytes
+				}
+				if len(bodyBytes) == 0 {
+					parseEmptyBody(request)
+					resetRequestBody(request, bodyBytes)
+				} else if strings.HasPrefix(contentType, "application/json") {
+					if maxDepth := config.GetInt("server.maxJSONDepth"); exceedsJSONDepth(bodyBytes, maxDepth) {
+						response.err = validation.Errors{"error": {lang.Get(request.Lang, "json-too-deep", ":max", strconv.Itoa(maxDepth))}}
+						response.Status(http.StatusBadRequest)
+						return
+					}
 					request.Data = make(map[string]interface{}, 10)
 					if err := parseQuery(request); err != nil {
 						request.Data = nil
 					} else {
-						if err := json.Unmarshal(bodyBytes, &request.Data); err != nil {
+						if err := decodeJSON(bodyBytes, &request.Data); err != nil {
 							request.Data = nil
 						}
 					}
 					resetRequestBody(request, bodyBytes)
 				} else {
 					resetRequestBody(request, bodyBytes)
-					request.Data = generateFlatMap(request.httpRequest, maxSize)
+					data, err := generateFlatMap(request.httpRequest)
+					if err != nil && isFilesystemError(err) {
+						response.Error(err)
+						return
+					}
+					request.Data = data
 					resetRequestBody(request, bodyBytes)
 				}
 			}
 		}
 
+		if config.GetBool("server.cleanupUploadedFiles") {
+			defer cleanupUploadedFiles(request.Data)
+		}
 		next(response, request)
 	}
 }
 
-func generateFlatMap(request *http.Request, maxSize int64) map[string]interface{} {
-	flatMap := make(map[string]interface{})
-	err := request.ParseMultipartForm(maxSize)
-
-	if err != nil {
-		if err == http.ErrNotMultipart {
-			if err := request.ParseForm(); err != nil {
-				return nil
+// exceedsJSONDepth scans the given JSON body and returns true if its objects
+// and arrays are nested deeper than the given maximum depth. The body is not
+// validated: malformed JSON is rejected later by the decoder.
+// There is no limit if the maximum depth is lower than or equal to 0.
+func exceedsJSONDepth(body []byte, maxDepth int) bool {
+	if maxDepth <= 0 {
+		return false
+	}
+	depth := 0
+	inString := false
+	escaped := false
+	for _, c := range body {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
 			}
-		} else {
-			return nil
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return false
+}
+
+// decodeJSON unmarshals the given JSON body into dst. If the
+// "server.jsonUseNumber" config entry is enabled, numbers are decoded
+// as "json.Number" instead of "float64" so large integers don't lose precision.
+func decodeJSON(body []byte, dst interface{}) error {
+	if !config.GetBool("server.jsonUseNumber") {
+		return json.Unmarshal(body, dst)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(dst); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// decodeRequestBody returns a reader decompressing the given request's body
+// according to its "Content-Encoding" header. "gzip" and "deflate" are supported.
+// The returned reader is nil if the body is not encoded.
+// If the encoding is not supported, "415 Unsupported Media Type" is returned
+// as the status. If the body is not valid for the given encoding,
+// "400 Bad Request" is returned.
+func decodeRequestBody(request *http.Request) (io.Reader, int) {
+	switch strings.ToLower(strings.TrimSpace(request.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return nil, 0
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(request.Body)
+		if err != nil {
+			return nil, http.StatusBadRequest
+		}
+		return reader, 0
+	case "deflate":
+		reader, err := zlib.NewReader(request.Body)
+		if err != nil {
+			return nil, http.StatusBadRequest
+		}
+		return reader, 0
+	}
+	return nil, http.StatusUnsupportedMediaType
+}
+
+// parseEmptyBody sets the request's data to an empty map containing
+// only the query parameters.
+func parseEmptyBody(request *Request) {
+	request.Data = make(map[string]interface{})
+	if err := parseQuery(request); err != nil {
+		request.Data = nil
+	}
+}
+
+func cleanupUploadedFiles(data map[string]interface{}) {
+	for _, v := range data {
+		if files, ok := v.([]filesystem.File); ok {
+			filesystem.CleanupFiles(files)
 		}
 	}
+}
+
+func getMaxPayloadSize() int64 {
+	return int64(config.GetFloat("server.maxUploadSize") * 1024 * 1024)
+}
+
+func getUploadTempDir() (string, error) {
+	if !config.Has("server.uploadTempDir") {
+		return os.TempDir(), nil
+	}
+	dir := config.GetString("server.uploadTempDir")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
 
-	if request.Form != nil {
-		flatten(flatMap, request.Form)
+// generateFlatMap parses the form and multipart body of the given request.
+// Returns nil and the parsing error if it fails.
+func generateFlatMap(request *http.Request) (map[string]interface{}, error) {
+	flatMap := make(map[string]interface{})
+	if err := request.ParseForm(); err != nil {
+		return nil, err
 	}
-	if request.MultipartForm != nil {
-		flatten(flatMap, request.MultipartForm.Value)
+	flatten(flatMap, request.Form)
 
-		for field := range request.MultipartForm.File {
-			flatMap[field] = filesystem.ParseMultipartFiles(request, field)
+	values, files, err := parseMultipartForm(request, uploadMemoryLimit)
+	if err != nil {
+		if err != http.ErrNotMultipart {
+			return nil, err
+		}
+	} else {
+		flatten(flatMap, values)
+		for field, f := range files {
+			flatMap[field] = f
 		}
 	}
 
 	// Source form is not needed anymore, clear it.
 	request.Form = nil
 	request.PostForm = nil
-	request.MultipartForm = nil
 
-	return flatMap
+	return flatMap, nil
+}
+
+// isFilesystemError returns true if the given parsing error is caused by
+// the server's filesystem (such as an upload directory that cannot be
+// created or a temporary file that cannot be written) rather than
+// by a malformed request body.
+func isFilesystemError(err error) bool {
+	var pathErr *os.PathError
+	return errors.As(err, &pathErr)
+}
+
+// parseMultipartForm reads the multipart body of the given request.
+// File parts are kept in memory until the given memory limit is reached.
+// The remaining ones are written to temporary files, in the directory
+// defined by the "server.uploadTempDir" config entry.
+func parseMultipartForm(request *http.Request, memoryLimit int64) (url.Values, map[string][]filesystem.File, error) {
+	reader, err := request.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := url.Values{}
+	files := map[string][]filesystem.File{}
+	cleanup := func() {
+		for _, f := range files {
+			filesystem.CleanupFiles(f)
+		}
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		var buf bytes.Buffer
+		fileName := part.FileName()
+		if fileName == "" {
+			if _, err := io.Copy(&buf, part); err != nil {
+				cleanup()
+				return nil, nil, err
+			}
+			values.Add(name, buf.String())
+			continue
+		}
+
+		file, err := readMultipartFile(part, &buf, &memoryLimit)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		files[name] = append(files[name], file)
+	}
+
+	return values, files, nil
+}
+
+func readMultipartFile(part *multipart.Part, buf *bytes.Buffer, memoryLimit *int64) (filesystem.File, error) {
+	header := &multipart.FileHeader{
+		Filename: part.FileName(),
+		Header:   part.Header,
+	}
+	n, err := io.CopyN(buf, part, *memoryLimit+1)
+	if err != nil && err != io.EOF {
+		return filesystem.File{}, err
+	}
+
+	file := filesystem.File{Header: header}
+	if n <= *memoryLimit {
+		*memoryLimit -= n
+		header.Size = n
+		file.MIMEType = http.DetectContentType(buf.Bytes())
+		file.Data = memoryFile{io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, n)}
+		return file, nil
+	}
+
+	dir, err := getUploadTempDir()
+	if err != nil {
+		return filesystem.File{}, err
+	}
+	tmp, err := ioutil.TempFile(dir, "goyave-upload-")
+	if err != nil {
+		return filesystem.File{}, err
+	}
+	size, err := io.Copy(tmp, io.MultiReader(buf, part))
+	fileHeader := make([]byte, 512)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err == nil {
+		var read int
+		read, err = io.ReadFull(tmp, fileHeader)
+		fileHeader = fileHeader[:read]
+		if err == io.ErrUnexpectedEOF {
+			err = nil
+		}
+	}
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return filesystem.File{}, err
+	}
+	header.Size = size
+	file.MIMEType = http.DetectContentType(fileHeader)
+	file.Data = tmp
+	return file, nil
+}
+
+// memoryFile is a multipart.File implementation for uploaded
+// files kept in memory.
+type memoryFile struct {
+	*io.SectionReader
+}
+
+func (memoryFile) Close() error {
+	return nil
 }
 
 func flatten(dst map[string]interface{}, values url.Values) {
@@ -195,8 +542,20 @@ func corsMiddleware(next Handler) Handler {
 // or 400 Bad Request and the response error (which can be retrieved with `GetError()`) to the
 // `validation.Errors` returned by the validator.
 // This data can then be used in a status handler.
+//
+// Malformed requests (the body couldn't be parsed) always result in 400 Bad Request.
+// The status used for validation failures is defined by the "server.validationErrorStatus"
+// config entry (422 by default). Set it to 400 to use the same status for both cases.
+//
+// Route parameters are validated first, using the rules defined with "Route.ValidateParams()".
 func validateRequestMiddleware(next Handler) Handler {
 	return func(response *Response, r *Request) {
+		if errsBag := r.validateParams(); errsBag != nil {
+			response.err = errsBag
+			response.Status(config.GetInt("server.validationErrorStatus"))
+			return
+		}
+
 		errsBag := r.validate()
 		if errsBag == nil {
 			next(response, r)
@@ -207,7 +566,7 @@ func validateRequestMiddleware(next Handler) Handler {
 		if r.Data == nil {
 			code = http.StatusBadRequest
 		} else {
-			code = http.StatusUnprocessableEntity
+			code = config.GetInt("server.validationErrorStatus")
 		}
 		response.err = errsBag
 		response.Status(code)
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"goyave.dev/goyave/v3"
+)
+
+// ETag adds an "ETag" header to the successful responses to GET and HEAD
+// requests, computed from the response body. If the "If-None-Match" header
+// of the request matches the ETag, the body is not sent and the status
+// is set to "304 Not Modified".
+//
+// The response is buffered (see "Response.Buffer()"), so this middleware
+// doesn't apply to bodies larger than the "server.maxResponseBufferSize"
+// config entry. If the handler already set the "ETag" header, its value
+// is used instead of the computed one. The ETag is weak because the
+// body may still be transformed, for example compressed by the "Gzip" middleware.
+func ETag() goyave.Middleware {
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			method := request.Method()
+			if method != http.MethodGet && method != http.MethodHead {
+				next(response, request)
+				return
+			}
+
+			response.Buffer()
+			next(response, request)
+
+			status := response.GetStatus()
+			if response.IsHeaderWritten() || status < 200 || status >= 300 {
+				return
+			}
+
+			etag := response.Header().Get("ETag")
+			if etag == "" {
+				sum := sha1.Sum(response.BufferedBody())
+				etag = "W/\"" + hex.EncodeToString(sum[:]) + "\""
+				response.Header().Set("ETag", etag)
+			}
+
+			if etagMatches(request.Header().Get("If-None-Match"), etag) {
+				response.ResetBuffer()
+				response.Header().Del("Content-Length")
+				response.WriteHeader(http.StatusNotModified)
+			}
+		}
+	}
+}
+
+// etagMatches returns true if one of the entity tags of the given
+// "If-None-Match" header matches the given ETag, using the weak comparison.
+func etagMatches(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"goyave.dev/goyave/v3"
+)
+
+type ETagMiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *ETagMiddlewareTestSuite) request(method string, headers map[string]string, handler goyave.Handler) (*http.Response, string) {
+	request := suite.CreateTestRequest(httptest.NewRequest(method, "/etag", nil))
+	for k, v := range headers {
+		request.Header().Set(k, v)
+	}
+	result := suite.Middleware(ETag(), request, handler)
+	body, err := ioutil.ReadAll(result.Body)
+	if err != nil {
+		panic(err)
+	}
+	result.Body.Close()
+	return result, string(body)
+}
+
+func (suite *ETagMiddlewareTestSuite) TestETag() {
+	handler := func(response *goyave.Response, r *goyave.Request) {
+		response.JSON(http.StatusOK, map[string]string{"hello": "world"})
+	}
+
+	result, body := suite.request(http.MethodGet, nil, handler)
+	etag := result.Header.Get("ETag")
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Equal("W/\"231f18733da35a9d6cc1e85debef593a29e41d04\"", etag)
+	suite.Equal("{\"hello\":\"world\"}\n", body)
+
+	// Revalidation
+	result, body = suite.request(http.MethodGet, map[string]string{"If-None-Match": etag}, handler)
+	suite.Equal(http.StatusNotModified, result.StatusCode)
+	suite.Equal(etag, result.Header.Get("ETag"))
+	suite.Empty(result.Header.Get("Content-Length"))
+	suite.Empty(body)
+
+	result, body = suite.request(http.MethodGet, map[string]string{"If-None-Match": "\"other\", " + etag[2:]}, handler)
+	suite.Equal(http.StatusNotModified, result.StatusCode)
+	suite.Empty(body)
+
+	result, _ = suite.request(http.MethodHead, map[string]string{"If-None-Match": "*"}, handler)
+	suite.Equal(http.StatusNotModified, result.StatusCode)
+
+	// Changed content
+	result, body = suite.request(http.MethodGet, map[string]string{"If-None-Match": "W/\"other\""}, handler)
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Equal(etag, result.Header.Get("ETag"))
+	suite.Equal("{\"hello\":\"world\"}\n", body)
+}
+
+func (suite *ETagMiddlewareTestSuite) TestETagFromHandler() {
+	result, body := suite.request(http.MethodGet, map[string]string{"If-None-Match": "\"v1\""}, func(response *goyave.Response, r *goyave.Request) {
+		response.Header().Set("ETag", "\"v1\"")
+		response.String(http.StatusOK, "content")
+	})
+	suite.Equal(http.StatusNotModified, result.StatusCode)
+	suite.Equal("\"v1\"", result.Header.Get("ETag"))
+	suite.Empty(body)
+}
+
+func (suite *ETagMiddlewareTestSuite) TestETagNotApplicable() {
+	handler := func(response *goyave.Response, r *goyave.Request) {
+		response.String(http.StatusOK, "content")
+	}
+	result, body := suite.request(http.MethodPost, map[string]string{"If-None-Match": "*"}, handler)
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Empty(result.Header.Get("ETag"))
+	suite.Equal("content", body)
+
+	result, body = suite.request(http.MethodGet, map[string]string{"If-None-Match": "*"}, func(response *goyave.Response, r *goyave.Request) {
+		response.String(http.StatusNotFound, "not found")
+	})
+	suite.Equal(http.StatusNotFound, result.StatusCode)
+	suite.Empty(result.Header.Get("ETag"))
+	suite.Equal("not found", body)
+}
+
+func TestETagMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(ETagMiddlewareTestSuite))
+}
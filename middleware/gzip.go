@@ -4,16 +4,25 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync"
 
 	"goyave.dev/goyave/v3"
 	"goyave.dev/goyave/v3/helper"
 )
 
+// gzipWriterPools pools of gzip writers, one for each compression level,
+// reused across requests to avoid allocating a new writer for every response.
+var gzipWriterPools [gzip.BestCompression - gzip.HuffmanOnly + 1]sync.Pool
+
 type gzipWriter struct {
 	*gzip.Writer
 	http.ResponseWriter
 	childWriter io.Writer
+	pool        *sync.Pool
+	closed      bool
 }
 
 func (w *gzipWriter) PreWrite(b []byte) {
@@ -32,7 +41,18 @@ func (w *gzipWriter) Write(b []byte) (int, error) {
 }
 
 func (w *gzipWriter) Close() error {
+	if w.closed {
+		// Don't close twice: the gzip writer may have been reused already.
+		return nil
+	}
+	w.closed = true
 	err := w.Writer.Close()
+	if w.pool != nil {
+		// The writer is reset when taken from the pool,
+		// but reset it now to release the child writer.
+		w.Writer.Reset(ioutil.Discard)
+		w.pool.Put(w.Writer)
+	}
 
 	if wr, ok := w.childWriter.(io.Closer); ok {
 		return wr.Close()
@@ -52,6 +72,9 @@ func Gzip() goyave.Middleware {
 //
 // The compression level should be gzip.DefaultCompression, gzip.NoCompression,
 // or any integer value between gzip.BestSpeed and gzip.BestCompression inclusive.
+//
+// Gzip writers are pooled and reused across requests. A writer is reset
+// before being used for a new response.
 func GzipLevel(level int) goyave.Middleware {
 	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
 		panic(fmt.Errorf("gzip: invalid compression level: %d", level))
@@ -66,11 +89,18 @@ func GzipLevel(level int) goyave.Middleware {
 			request.Header().Del("Accept-Encoding")
 
 			respWriter := response.Writer()
-			writer, _ := gzip.NewWriterLevel(respWriter, level)
+			pool := &gzipWriterPools[level-gzip.HuffmanOnly]
+			writer, ok := pool.Get().(*gzip.Writer)
+			if ok {
+				writer.Reset(respWriter)
+			} else {
+				writer, _ = gzip.NewWriterLevel(respWriter, level)
+			}
 			compressWriter := &gzipWriter{
 				Writer:         writer,
 				ResponseWriter: response,
 				childWriter:    respWriter,
+				pool:           pool,
 			}
 			response.SetWriter(compressWriter)
 			response.Header().Set("Content-Encoding", "gzip")
@@ -81,7 +111,27 @@ func GzipLevel(level int) goyave.Middleware {
 }
 
 func acceptsGzip(request *goyave.Request) bool {
-	encodings := helper.ParseMultiValuesHeader(request.Header().Get("Accept-Encoding"))
+	header := request.Header().Get("Accept-Encoding")
+	if !strings.Contains(header, "gzip") {
+		return false
+	}
+	if !strings.Contains(header, ";") {
+		// Fast path: no quality values, no need to fully parse the header.
+		for header != "" {
+			var value string
+			if i := strings.IndexByte(header, ','); i != -1 {
+				value, header = header[:i], header[i+1:]
+			} else {
+				value, header = header, ""
+			}
+			if strings.TrimSpace(value) == "gzip" {
+				return true
+			}
+		}
+		return false
+	}
+
+	encodings := helper.ParseMultiValuesHeader(header)
 	for _, h := range encodings {
 		if h.Value == "gzip" {
 			return true
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/config"
+)
+
+var benchmarkContent = []byte(`{"id":1,"name":"benchmark","description":"Lorem ipsum dolor sit amet, consectetur adipiscing elit."}`)
+
+func benchmarkGzip(b *testing.B, middleware goyave.Middleware) {
+	if err := config.LoadFrom("../config.test.json"); err != nil {
+		panic(err)
+	}
+	defer config.Clear()
+	suite := new(GzipMiddlewareTestSuite)
+	handler := func(response *goyave.Response, r *goyave.Request) {
+		response.Status(http.StatusOK)
+		if _, err := response.Write(benchmarkContent); err != nil {
+			panic(err)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rawRequest := httptest.NewRequest("GET", "/", nil)
+		rawRequest.Header.Set("Accept-Encoding", "deflate, gzip")
+		suite.Middleware(middleware, suite.CreateTestRequest(rawRequest), handler)
+	}
+}
+
+func BenchmarkGzipPooled(b *testing.B) {
+	benchmarkGzip(b, Gzip())
+}
+
+func BenchmarkGzipUnpooled(b *testing.B) {
+	// Reference implementation, allocating a new writer for every request.
+	middleware := func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			respWriter := response.Writer()
+			writer, _ := gzip.NewWriterLevel(respWriter, gzip.DefaultCompression)
+			response.SetWriter(&gzipWriter{
+				Writer:         writer,
+				ResponseWriter: response,
+				childWriter:    respWriter,
+			})
+			response.Header().Set("Content-Encoding", "gzip")
+			next(response, request)
+		}
+	}
+	benchmarkGzip(b, middleware)
+}
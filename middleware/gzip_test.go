@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"goyave.dev/goyave/v3"
@@ -189,6 +192,88 @@ func (suite *GzipMiddlewareTestSuite) TestWriteFile() {
 	})
 }
 
+func (suite *GzipMiddlewareTestSuite) TestWriterPool() {
+	for _, level := range []int{gzip.DefaultCompression, gzip.BestSpeed, gzip.BestCompression} {
+		middleware := GzipLevel(level)
+		for i := 0; i < 5; i++ {
+			content := fmt.Sprintf("response %d at level %d", i, level)
+			handler := func(response *goyave.Response, r *goyave.Request) {
+				response.String(http.StatusOK, content)
+			}
+			rawRequest := httptest.NewRequest("GET", "/", nil)
+			rawRequest.Header.Set("Accept-Encoding", "gzip")
+			request := suite.CreateTestRequest(rawRequest)
+			result := suite.Middleware(middleware, request, handler)
+
+			reader, err := gzip.NewReader(result.Body)
+			if err != nil {
+				panic(err)
+			}
+			body, err := ioutil.ReadAll(reader)
+			if err != nil {
+				panic(err)
+			}
+			result.Body.Close()
+			suite.Equal(content, string(body))
+		}
+	}
+}
+
+func (suite *GzipMiddlewareTestSuite) TestCloseTwice() {
+	recorder := httptest.NewRecorder()
+	gz := gzip.NewWriter(recorder)
+	writer := &gzipWriter{
+		Writer:      gz,
+		childWriter: recorder,
+		pool:        &sync.Pool{},
+	}
+	suite.False(writer.closed)
+	suite.Nil(writer.Close())
+	suite.True(writer.closed)
+
+	// Closing again must not affect the gzip writer, which may have
+	// been taken from the pool and reused by another response.
+	buf := &bytes.Buffer{}
+	gz.Reset(buf)
+	suite.Nil(writer.Close())
+	suite.True(writer.closed)
+	if _, err := gz.Write([]byte("hello world")); err != nil {
+		panic(err)
+	}
+	suite.Nil(gz.Close())
+	reader, err := gzip.NewReader(buf)
+	if err != nil {
+		panic(err)
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		panic(err)
+	}
+	suite.Equal("hello world", string(body))
+}
+
+func (suite *GzipMiddlewareTestSuite) TestAcceptsGzip() {
+	cases := map[string]bool{
+		"":                      false,
+		"gzip":                  true,
+		"deflate, gzip":         true,
+		"gzip,deflate":          true,
+		"br":                    false,
+		"x-gzipx":               false,
+		"deflate, x-gzip":       false,
+		"gzip;q=1.0":            true,
+		"deflate;q=1.0, gzip":   true,
+		"deflate, gzip;q=0.5":   true,
+		"deflate;q=0.5, br":     false,
+		"deflate;q=0.5, x-gzip": false,
+	}
+	for header, expected := range cases {
+		rawRequest := httptest.NewRequest("GET", "/", nil)
+		rawRequest.Header.Set("Accept-Encoding", header)
+		suite.Equal(expected, acceptsGzip(suite.CreateTestRequest(rawRequest)), header)
+	}
+}
+
 func TestGzipMiddlewareTestSuite(t *testing.T) {
 	goyave.RunTest(t, new(GzipMiddlewareTestSuite))
 }
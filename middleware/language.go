@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/lang"
+)
+
+// SetLanguage sets the language of the request using the given resolver,
+// for example from the profile of the authenticated user.
+//
+// The resolver is executed after the "Accept-Language" header has been
+// negotiated. If it returns an empty string or a language that is not
+// available, the negotiated language is kept. Otherwise, the returned
+// language wins.
+//
+// Validation is executed after all middleware, so validation error
+// messages use the resolved language.
+//
+//  router.Middleware(middleware.SetLanguage(func(request *goyave.Request) string {
+//      if user, ok := request.User.(*model.User); ok {
+//          return user.Language
+//      }
+//      return ""
+//  }))
+func SetLanguage(resolver func(*goyave.Request) string) goyave.Middleware {
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			if language := resolver(request); language != "" && lang.IsAvailable(language) {
+				request.Lang = language
+			}
+			next(response, request)
+		}
+	}
+}
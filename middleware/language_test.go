@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/lang"
+	"goyave.dev/goyave/v3/validation"
+)
+
+type LanguageMiddlewareTestSuite struct {
+	goyave.TestSuite
+	dir string
+}
+
+func (suite *LanguageMiddlewareTestSuite) SetupSuite() {
+	dir, err := ioutil.TempDir("", "goyave-lang")
+	if err != nil {
+		panic(err)
+	}
+	suite.dir = dir
+	if err := ioutil.WriteFile(filepath.Join(dir, "rules.json"), []byte(`{"required": "Le champ :field est requis."}`), 0644); err != nil {
+		panic(err)
+	}
+	lang.Load("fr-FR", dir)
+}
+
+func (suite *LanguageMiddlewareTestSuite) TearDownSuite() {
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *LanguageMiddlewareTestSuite) TestSetLanguage() {
+	resolved := "fr-FR"
+	middleware := SetLanguage(func(request *goyave.Request) string {
+		return resolved
+	})
+
+	request := suite.CreateTestRequest(nil)
+	request.Lang = "en-US"
+	suite.Middleware(middleware, request, func(response *goyave.Response, r *goyave.Request) {
+		suite.Equal("fr-FR", r.Lang)
+	}).Body.Close()
+
+	// Empty or unavailable: the negotiated language is kept
+	for _, resolved = range []string{"", "xx-XX"} {
+		request := suite.CreateTestRequest(nil)
+		request.Lang = "en-US"
+		suite.Middleware(middleware, request, func(response *goyave.Response, r *goyave.Request) {
+			suite.Equal("en-US", r.Lang)
+		}).Body.Close()
+	}
+}
+
+func (suite *LanguageMiddlewareTestSuite) TestSetLanguageValidation() {
+	router := goyave.NewRouter()
+	router.Middleware(SetLanguage(func(request *goyave.Request) string {
+		if request.Header().Get("X-User") == "french" {
+			return "fr-FR"
+		}
+		return ""
+	}))
+	router.Post("/validate", func(response *goyave.Response, request *goyave.Request) {
+		response.Status(http.StatusNoContent)
+	}).Validate(validation.RuleSet{
+		"name": {"required"},
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader("{}"))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", "en-US")
+	request.Header.Set("X-User", "french")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	suite.Equal("{\"validationError\":{\"name\":[\"Le champ name est requis.\"]}}\n", recorder.Body.String())
+
+	request = httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader("{}"))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", "en-US")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	suite.Equal(http.StatusUnprocessableEntity, recorder.Code)
+	suite.NotContains(recorder.Body.String(), "Le champ name est requis.")
+}
+
+func TestLanguageMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(LanguageMiddlewareTestSuite))
+}
@@ -2,7 +2,6 @@ package ratelimiter
 
 import (
 	"net/http"
-	"strings"
 	"time"
 
 	"goyave.dev/goyave/v3"
@@ -11,7 +10,7 @@ import (
 // Config for setting configuration for the limiter middleware
 type Config struct {
 	// Unique identifier for requestors. Can be userID or IP
-	// Defaults to Remote Address if it is empty
+	// Defaults to the IP of the client ("Request.ClientIP()") if it is empty
 	ClientID interface{}
 
 	// Duration or time taken until the quota expires and renews
@@ -62,14 +61,5 @@ func newWithStore(configFn ConfigFunc, lstore *limiterStore) goyave.Middleware {
 }
 
 func defaultClientID(request *goyave.Request) string {
-	remoteAddr := request.RemoteAddress()
-
-	// strip off port number
-	e := strings.Index(remoteAddr, ":")
-
-	if e == -1 {
-		return remoteAddr
-	}
-
-	return remoteAddr[0:e]
+	return request.ClientIP()
 }
@@ -153,6 +153,49 @@ func (suite *RateLimiterMiddlewareTestSuite) TestRequestQuotaResetsAfterQuotaDur
 	suite.Equal(http.StatusNoContent, result.StatusCode)
 }
 
+func (suite *RateLimiterMiddlewareTestSuite) TestQuotaWindowFakeClock() {
+	clock := suite.UseFakeClock(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+	defer suite.RestoreClock()
+
+	const quota = 2
+	ratelimiterMiddleware := New(func(request *goyave.Request) Config {
+		return Config{
+			ClientID:      "client",
+			RequestQuota:  quota,
+			QuotaDuration: time.Minute,
+		}
+	})
+
+	request := func() *http.Response {
+		result := suite.Middleware(
+			ratelimiterMiddleware,
+			suite.CreateTestRequest(nil),
+			func(response *goyave.Response, request *goyave.Request) {},
+		)
+		result.Body.Close()
+		return result
+	}
+
+	for i := 0; i < quota; i++ {
+		suite.Equal(http.StatusNoContent, request().StatusCode)
+	}
+	result := request()
+	suite.Equal(http.StatusTooManyRequests, result.StatusCode)
+	suite.Equal("60", result.Header.Get("RateLimit-Reset"))
+
+	clock.Advance(45 * time.Second)
+	result = request()
+	suite.Equal(http.StatusTooManyRequests, result.StatusCode)
+	suite.Equal("15", result.Header.Get("RateLimit-Reset"))
+
+	// The window expired, the quota is renewed.
+	clock.Advance(15 * time.Second)
+	result = request()
+	suite.Equal(http.StatusNoContent, result.StatusCode)
+	suite.Equal("1", result.Header.Get("RateLimit-Remaining"))
+	suite.Equal("60", result.Header.Get("RateLimit-Reset"))
+}
+
 func (suite *RateLimiterMiddlewareTestSuite) TestLimiterQuotaIsZero() {
 	// This middleware should be skipped if the quota or the duration is equal to zero
 	ratelimiterMiddleware := New(func(request *goyave.Request) Config {
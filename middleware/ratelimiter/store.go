@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/clock"
 )
 
 type limiter struct {
@@ -20,7 +21,7 @@ func newLimiter(config Config) *limiter {
 	return &limiter{
 		config:   config,
 		counter:  0,
-		resetsAt: time.Now().Add(config.QuotaDuration),
+		resetsAt: clock.Now().Add(config.QuotaDuration),
 	}
 }
 
@@ -65,7 +66,7 @@ func (l *limiter) getRemainingRequestQuota() int {
 }
 
 func (l *limiter) getSecondsToQuotaReset() float64 {
-	return -math.Round(time.Since(l.resetsAt).Seconds())
+	return -math.Round(clock.Since(l.resetsAt).Seconds())
 }
 
 type limiterStore struct {
@@ -88,7 +89,9 @@ func (ls *limiterStore) set(key interface{}, limiter *limiter) {
 	time.AfterFunc(limiter.config.QuotaDuration, func() {
 		ls.mx.Lock()
 		defer ls.mx.Unlock()
-		delete(ls.store, key)
+		if ls.store[key] == limiter {
+			delete(ls.store, key)
+		}
 	})
 }
 
@@ -96,7 +99,7 @@ func (ls *limiterStore) get(key interface{}, config Config) *limiter {
 	ls.mx.Lock()
 	defer ls.mx.Unlock()
 
-	if l, ok := ls.store[key]; ok {
+	if l, ok := ls.store[key]; ok && clock.Now().Before(l.resetsAt) {
 		return l
 	}
 
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"goyave.dev/goyave/v3"
+)
+
+// maxRequestIDLength the maximum length of a request ID sent by the client.
+const maxRequestIDLength = 128
+
+// RequestID assigns an identifier to each request so log entries and
+// errors can be correlated with it. The ID can be retrieved with
+// "request.ID()" and is sent back in the "X-Request-Id" response header.
+//
+// If the client (or a proxy) already sent a valid "X-Request-Id" header,
+// its value is reused. Otherwise, a new UUID is generated. Valid IDs are
+// at most 128 characters long and only contain letters, digits,
+// dashes, underscores, dots and colons.
+//
+// Apply this middleware globally to the main router so the ID is
+// assigned before the other middleware are executed.
+func RequestID(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		id := request.Header().Get(goyave.RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = uuid.New().String()
+		}
+		if request.Extra == nil {
+			request.Extra = make(map[string]interface{})
+		}
+		request.Extra[goyave.RequestIDExtraKey] = id
+		response.Header().Set(goyave.RequestIDHeader, id)
+		next(response, request)
+	}
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"goyave.dev/goyave/v3"
+)
+
+type RequestIDMiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *RequestIDMiddlewareTestSuite) TestGenerateID() {
+	request := suite.CreateTestRequest(nil)
+	id := ""
+	result := suite.Middleware(RequestID, request, func(response *goyave.Response, r *goyave.Request) {
+		id = r.ID()
+	})
+	_, err := uuid.Parse(id)
+	suite.Nil(err)
+	suite.Equal(id, result.Header.Get(goyave.RequestIDHeader))
+	result.Body.Close()
+
+	// Each request gets its own ID
+	request = suite.CreateTestRequest(nil)
+	result = suite.Middleware(RequestID, request, func(response *goyave.Response, r *goyave.Request) {
+		suite.NotEqual(id, r.ID())
+	})
+	result.Body.Close()
+}
+
+func (suite *RequestIDMiddlewareTestSuite) TestReuseClientID() {
+	rawRequest := httptest.NewRequest("GET", "/", nil)
+	rawRequest.Header.Set(goyave.RequestIDHeader, "client-id_1.2:3")
+	request := suite.CreateTestRequest(rawRequest)
+	result := suite.Middleware(RequestID, request, func(response *goyave.Response, r *goyave.Request) {
+		suite.Equal("client-id_1.2:3", r.ID())
+	})
+	suite.Equal("client-id_1.2:3", result.Header.Get(goyave.RequestIDHeader))
+	result.Body.Close()
+
+	for _, invalid := range []string{"with space", "new\nline", "é", strings.Repeat("a", 129)} {
+		rawRequest := httptest.NewRequest("GET", "/", nil)
+		rawRequest.Header.Set(goyave.RequestIDHeader, invalid)
+		request := suite.CreateTestRequest(rawRequest)
+		result := suite.Middleware(RequestID, request, func(response *goyave.Response, r *goyave.Request) {
+			_, err := uuid.Parse(r.ID())
+			suite.Nil(err)
+		})
+		result.Body.Close()
+	}
+}
+
+func (suite *RequestIDMiddlewareTestSuite) TestPanicLogCorrelation() {
+	buf := &bytes.Buffer{}
+	prevLogger := goyave.ErrLogger
+	goyave.ErrLogger = log.New(buf, "", 0)
+	defer func() {
+		goyave.ErrLogger = prevLogger
+	}()
+
+	suite.RunServer(func(router *goyave.Router) {
+		router.Middleware(RequestID)
+		router.Get("/panic", func(response *goyave.Response, request *goyave.Request) {
+			panic("test panic")
+		})
+	}, func() {
+		resp, err := suite.Get("/panic", map[string]string{goyave.RequestIDHeader: "correlation-id"})
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+			suite.Equal("correlation-id", resp.Header.Get(goyave.RequestIDHeader))
+		}
+	})
+
+	logs := buf.String()
+	suite.Contains(logs, "GET /panic [correlation-id] test panic\n")
+	suite.Contains(logs, "goroutine")
+}
+
+func TestRequestIDMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(RequestIDMiddlewareTestSuite))
+}
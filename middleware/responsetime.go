@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v3"
+)
+
+// ResponseTimeHeader the name of the header set by the "ResponseTime" middleware.
+const ResponseTimeHeader = "X-Response-Time"
+
+type responseTimeWriter struct {
+	io.Writer
+	response *goyave.Response
+	request  *goyave.Request
+}
+
+func (w *responseTimeWriter) PreWrite(b []byte) {
+	if pr, ok := w.Writer.(goyave.PreWriter); ok {
+		pr.PreWrite(b)
+	}
+	setResponseTime(w.response, w.request)
+}
+
+func (w *responseTimeWriter) Close() error {
+	if wr, ok := w.Writer.(io.Closer); ok {
+		return wr.Close()
+	}
+	return nil
+}
+
+// ResponseTime adds the "X-Response-Time" header to the responses, containing the
+// time elapsed between the start of the request and the moment the response header
+// is written, in milliseconds (for example "12.345ms").
+//
+// Apply this middleware globally to the main router so the time spent
+// in the other middleware is measured too.
+func ResponseTime() goyave.Middleware {
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			response.SetWriter(&responseTimeWriter{
+				Writer:   response.Writer(),
+				response: response,
+				request:  request,
+			})
+			next(response, request)
+			if !response.IsHeaderWritten() {
+				// Empty response, the header is written by the framework
+				// after the middleware have been executed.
+				setResponseTime(response, request)
+			}
+		}
+	}
+}
+
+func setResponseTime(response *goyave.Response, request *goyave.Request) {
+	elapsed := float64(request.Elapsed()) / float64(time.Millisecond)
+	response.Header().Set(ResponseTimeHeader, strconv.FormatFloat(elapsed, 'f', 3, 64)+"ms")
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3"
+)
+
+type ResponseTimeMiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *ResponseTimeMiddlewareTestSuite) TestResponseTime() {
+	clock := suite.UseFakeClock(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	defer suite.RestoreClock()
+
+	request := suite.CreateTestRequest(nil)
+	result := suite.Middleware(ResponseTime(), request, func(response *goyave.Response, r *goyave.Request) {
+		clock.Advance(15 * time.Millisecond)
+		response.String(http.StatusOK, "hello")
+		clock.Advance(time.Second)
+	})
+	result.Body.Close()
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Equal("15.000ms", result.Header.Get(ResponseTimeHeader))
+
+	// Empty response
+	request = suite.CreateTestRequest(nil)
+	result = suite.Middleware(ResponseTime(), request, func(response *goyave.Response, r *goyave.Request) {
+		clock.Advance(1500 * time.Microsecond)
+	})
+	result.Body.Close()
+	suite.Equal(http.StatusNoContent, result.StatusCode)
+	suite.Equal("1.500ms", result.Header.Get(ResponseTimeHeader))
+
+	// Buffered response: the header is set when the body is flushed
+	request = suite.CreateTestRequest(nil)
+	result = suite.Middleware(ResponseTime(), request, func(response *goyave.Response, r *goyave.Request) {
+		response.Buffer()
+		response.String(http.StatusOK, "hello")
+		clock.Advance(2 * time.Millisecond)
+	})
+	result.Body.Close()
+	suite.Equal("2.000ms", result.Header.Get(ResponseTimeHeader))
+}
+
+func TestResponseTimeMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(ResponseTimeMiddlewareTestSuite))
+}
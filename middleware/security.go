@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v3"
+)
+
+// SecurityHeadersOptions defines the headers written by the "SecurityHeaders"
+// middleware. Leave a field empty (or zero) to omit the corresponding header.
+type SecurityHeadersOptions struct {
+	// HSTSMaxAge the "max-age" directive of the "Strict-Transport-Security" header.
+	// This header is only sent over HTTPS (see "Request.Scheme()").
+	// Default is one year.
+	HSTSMaxAge time.Duration
+
+	// HSTSIncludeSubdomains adds the "includeSubDomains" directive to
+	// the "Strict-Transport-Security" header. Default is true.
+	HSTSIncludeSubdomains bool
+
+	// HSTSPreload adds the "preload" directive to the
+	// "Strict-Transport-Security" header. Default is false.
+	HSTSPreload bool
+
+	// ContentTypeOptions value of the "X-Content-Type-Options" header.
+	// Default is "nosniff".
+	ContentTypeOptions string
+
+	// FrameOptions value of the "X-Frame-Options" header.
+	// Default is "SAMEORIGIN".
+	FrameOptions string
+
+	// ReferrerPolicy value of the "Referrer-Policy" header.
+	// Default is "strict-origin-when-cross-origin".
+	ReferrerPolicy string
+
+	// ContentSecurityPolicy value of the "Content-Security-Policy" header.
+	// There is no default value as the policy depends on the application.
+	ContentSecurityPolicy string
+}
+
+// DefaultSecurityHeaders create new security headers options with
+// default settings. The returned value can be used as a starting point
+// for customized options.
+func DefaultSecurityHeaders() *SecurityHeadersOptions {
+	return &SecurityHeadersOptions{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentTypeOptions:    "nosniff",
+		FrameOptions:          "SAMEORIGIN",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	}
+}
+
+// SecurityHeaders writes common security headers to the response:
+// "Strict-Transport-Security" (HTTPS only), "X-Content-Type-Options",
+// "X-Frame-Options", "Referrer-Policy" and "Content-Security-Policy".
+// The headers are written before the next handler is executed, so they
+// can be overridden by handlers. If the given options are nil,
+// "DefaultSecurityHeaders()" is used.
+//
+//  options := middleware.DefaultSecurityHeaders()
+//  options.ContentSecurityPolicy = "default-src 'self'"
+//  router.Middleware(middleware.SecurityHeaders(options))
+func SecurityHeaders(options *SecurityHeadersOptions) goyave.Middleware {
+	if options == nil {
+		options = DefaultSecurityHeaders()
+	}
+	hsts := ""
+	if options.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(options.HSTSMaxAge/time.Second), 10)
+		if options.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if options.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	headers := map[string]string{
+		"X-Content-Type-Options":  options.ContentTypeOptions,
+		"X-Frame-Options":         options.FrameOptions,
+		"Referrer-Policy":         options.ReferrerPolicy,
+		"Content-Security-Policy": options.ContentSecurityPolicy,
+	}
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			header := response.Header()
+			if hsts != "" && request.Scheme() == "https" {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+			for name, value := range headers {
+				if value != "" {
+					header.Set(name, value)
+				}
+			}
+			next(response, request)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3"
+)
+
+type SecurityHeadersMiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *SecurityHeadersMiddlewareTestSuite) request(https bool) *goyave.Request {
+	rawRequest := httptest.NewRequest("GET", "/", nil)
+	if https {
+		rawRequest.TLS = &tls.ConnectionState{}
+	}
+	return suite.CreateTestRequest(rawRequest)
+}
+
+func (suite *SecurityHeadersMiddlewareTestSuite) TestDefault() {
+	result := suite.Middleware(SecurityHeaders(nil), suite.request(true), func(response *goyave.Response, r *goyave.Request) {})
+	result.Body.Close()
+	suite.Equal("max-age=31536000; includeSubDomains", result.Header.Get("Strict-Transport-Security"))
+	suite.Equal("nosniff", result.Header.Get("X-Content-Type-Options"))
+	suite.Equal("SAMEORIGIN", result.Header.Get("X-Frame-Options"))
+	suite.Equal("strict-origin-when-cross-origin", result.Header.Get("Referrer-Policy"))
+	_, ok := result.Header["Content-Security-Policy"]
+	suite.False(ok)
+
+	// No HSTS over plain HTTP
+	result = suite.Middleware(SecurityHeaders(nil), suite.request(false), func(response *goyave.Response, r *goyave.Request) {})
+	result.Body.Close()
+	_, ok = result.Header["Strict-Transport-Security"]
+	suite.False(ok)
+	suite.Equal("nosniff", result.Header.Get("X-Content-Type-Options"))
+}
+
+func (suite *SecurityHeadersMiddlewareTestSuite) TestCustom() {
+	options := DefaultSecurityHeaders()
+	options.HSTSMaxAge = time.Hour
+	options.HSTSIncludeSubdomains = false
+	options.HSTSPreload = true
+	options.FrameOptions = "DENY"
+	options.ReferrerPolicy = ""
+	options.ContentSecurityPolicy = "default-src 'self'"
+	middleware := SecurityHeaders(options)
+
+	result := suite.Middleware(middleware, suite.request(true), func(response *goyave.Response, r *goyave.Request) {})
+	result.Body.Close()
+	suite.Equal("max-age=3600; preload", result.Header.Get("Strict-Transport-Security"))
+	suite.Equal("DENY", result.Header.Get("X-Frame-Options"))
+	suite.Equal("default-src 'self'", result.Header.Get("Content-Security-Policy"))
+	_, ok := result.Header["Referrer-Policy"]
+	suite.False(ok)
+
+	// Handlers can override the headers
+	result = suite.Middleware(middleware, suite.request(true), func(response *goyave.Response, r *goyave.Request) {
+		response.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	})
+	result.Body.Close()
+	suite.Equal("SAMEORIGIN", result.Header.Get("X-Frame-Options"))
+
+	// HSTS disabled
+	options.HSTSMaxAge = 0
+	result = suite.Middleware(SecurityHeaders(options), suite.request(true), func(response *goyave.Response, r *goyave.Request) {})
+	result.Body.Close()
+	_, ok = result.Header["Strict-Transport-Security"]
+	suite.False(ok)
+}
+
+func TestSecurityHeadersMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(SecurityHeadersMiddlewareTestSuite))
+}
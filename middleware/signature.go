@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/helper/filesystem"
+)
+
+// ValidateSignature checks the signature and expiry of URLs
+// generated with "filesystem.SignURL()".
+// Returns "403 Forbidden" if the URL has been tampered with or
+// if it has expired.
+//
+// Signatures rely on the application key: call "goyave.RequireAppKey()"
+// to make the server fail at startup if it is not set.
+func ValidateSignature(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		if err := filesystem.VerifyURL(request.URI()); err != nil {
+			if err != filesystem.ErrInvalidSignature && err != filesystem.ErrExpiredSignature {
+				panic(err)
+			}
+			response.Status(http.StatusForbidden)
+			return
+		}
+		next(response, request)
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3"
+	"goyave.dev/goyave/v3/config"
+	"goyave.dev/goyave/v3/helper/filesystem"
+)
+
+type SignatureMiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *SignatureMiddlewareTestSuite) SetupTest() {
+	config.Set("app.key", "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=")
+}
+
+func (suite *SignatureMiddlewareTestSuite) TearDownTest() {
+	config.Set("app.key", nil)
+}
+
+func (suite *SignatureMiddlewareTestSuite) request(url string) *goyave.Request {
+	return suite.CreateTestRequest(httptest.NewRequest(http.MethodGet, url, nil))
+}
+
+func (suite *SignatureMiddlewareTestSuite) TestValidateSignature() {
+	signed, err := filesystem.SignURL("/files/report.pdf", time.Minute)
+	if err != nil {
+		panic(err)
+	}
+
+	executed := false
+	result := suite.Middleware(ValidateSignature, suite.request(signed), func(response *goyave.Response, r *goyave.Request) {
+		executed = true
+		response.Status(http.StatusOK)
+	})
+	result.Body.Close()
+	suite.True(executed)
+	suite.Equal(http.StatusOK, result.StatusCode)
+
+	handler := func(response *goyave.Response, r *goyave.Request) {
+		suite.Fail("ValidateSignature shouldn't pass.")
+	}
+
+	result = suite.Middleware(ValidateSignature, suite.request(signed+"&download=1"), handler)
+	result.Body.Close()
+	suite.Equal(http.StatusForbidden, result.StatusCode)
+
+	result = suite.Middleware(ValidateSignature, suite.request("/files/report.pdf"), handler)
+	result.Body.Close()
+	suite.Equal(http.StatusForbidden, result.StatusCode)
+
+	expired, err := filesystem.SignURL("/files/report.pdf", -time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	result = suite.Middleware(ValidateSignature, suite.request(expired), handler)
+	result.Body.Close()
+	suite.Equal(http.StatusForbidden, result.StatusCode)
+}
+
+func TestSignatureMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(SignatureMiddlewareTestSuite))
+}
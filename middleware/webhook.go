@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+
+	"goyave.dev/goyave/v3"
+)
+
+// SignatureScheme describes how the HMAC signature of a webhook
+// payload is computed and encoded in its header.
+type SignatureScheme struct {
+	// Hash the hash function used to compute the HMAC.
+	Hash func() hash.Hash
+
+	// Decode decodes the signature sent in the header, for
+	// example "hex.DecodeString" or "base64.StdEncoding.DecodeString".
+	Decode func(string) ([]byte, error)
+
+	// Prefix expected before the encoded signature in the header,
+	// such as "sha256=". Can be empty.
+	Prefix string
+}
+
+var (
+	// SignatureHex HMAC-SHA256 signature encoded in hexadecimal.
+	SignatureHex = SignatureScheme{Hash: sha256.New, Decode: hex.DecodeString}
+
+	// SignatureBase64 HMAC-SHA256 signature encoded in standard base64.
+	SignatureBase64 = SignatureScheme{Hash: sha256.New, Decode: base64.StdEncoding.DecodeString}
+
+	// SignaturePrefixedHex HMAC-SHA256 signature encoded in hexadecimal
+	// and prefixed with "sha256=", as sent by GitHub in the
+	// "X-Hub-Signature-256" header.
+	SignaturePrefixedHex = SignatureScheme{Hash: sha256.New, Decode: hex.DecodeString, Prefix: "sha256="}
+)
+
+// VerifySignature checks the HMAC signature of the request body, computed
+// with the given secret and sent in the given header. Use this middleware
+// for routes receiving webhooks from third-party services.
+// Returns "401 Unauthorized" if the header is missing or if the signature
+// doesn't match the raw body of the request.
+//
+// The signature is computed over the body as it was received (see "Request.RawBody()")
+// and compared in constant time.
+//
+//  router.Post("/webhook", hook.Handle).Middleware(
+//  	middleware.VerifySignature(secret, "X-Hub-Signature-256", middleware.SignaturePrefixedHex),
+//  )
+func VerifySignature(secret []byte, header string, scheme SignatureScheme) goyave.Middleware {
+	if scheme.Hash == nil || scheme.Decode == nil {
+		panic("VerifySignature: the signature scheme must define a hash function and a decoder")
+	}
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			if !checkSignature(request.Header().Get(header), request.RawBody(), secret, scheme) {
+				response.Status(http.StatusUnauthorized)
+				return
+			}
+			next(response, request)
+		}
+	}
+}
+
+func checkSignature(signature string, body []byte, secret []byte, scheme SignatureScheme) bool {
+	if signature == "" || !strings.HasPrefix(signature, scheme.Prefix) {
+		return false
+	}
+	expected, err := scheme.Decode(signature[len(scheme.Prefix):])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(scheme.Hash, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"goyave.dev/goyave/v3"
+)
+
+type WebhookMiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *WebhookMiddlewareTestSuite) sign(secret, body string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+func (suite *WebhookMiddlewareTestSuite) TestVerifySignature() {
+	secret := []byte("secret")
+	body := `{"event":"push"}`
+	signature := suite.sign("secret", body)
+
+	suite.RunServer(func(router *goyave.Router) {
+		handler := func(response *goyave.Response, request *goyave.Request) {
+			response.String(http.StatusOK, request.String("event"))
+		}
+		router.Post("/hex", handler).Middleware(VerifySignature(secret, "X-Signature", SignatureHex))
+		router.Post("/base64", handler).Middleware(VerifySignature(secret, "X-Signature", SignatureBase64))
+		router.Post("/github", handler).Middleware(VerifySignature(secret, "X-Hub-Signature-256", SignaturePrefixedHex))
+	}, func() {
+		check := func(route string, headers map[string]string, status int) {
+			headers["Content-Type"] = "application/json"
+			resp, err := suite.Post(route, headers, strings.NewReader(body))
+			suite.Nil(err)
+			if err == nil {
+				defer resp.Body.Close()
+				suite.Equal(status, resp.StatusCode, route)
+				if status == http.StatusOK {
+					suite.Equal("push", string(suite.GetBody(resp)))
+				}
+			}
+		}
+
+		// Valid signatures
+		check("/hex", map[string]string{"X-Signature": hex.EncodeToString(signature)}, http.StatusOK)
+		check("/base64", map[string]string{"X-Signature": base64.StdEncoding.EncodeToString(signature)}, http.StatusOK)
+		check("/github", map[string]string{"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(signature)}, http.StatusOK)
+
+		// Invalid signatures
+		check("/hex", map[string]string{"X-Signature": hex.EncodeToString(suite.sign("wrong secret", body))}, http.StatusUnauthorized)
+		check("/hex", map[string]string{"X-Signature": "not hex"}, http.StatusUnauthorized)
+		check("/base64", map[string]string{"X-Signature": hex.EncodeToString(signature)}, http.StatusUnauthorized)
+		check("/github", map[string]string{"X-Hub-Signature-256": hex.EncodeToString(signature)}, http.StatusUnauthorized)
+
+		// Missing header
+		check("/hex", map[string]string{}, http.StatusUnauthorized)
+	})
+}
+
+func (suite *WebhookMiddlewareTestSuite) TestInvalidScheme() {
+	suite.Panics(func() {
+		VerifySignature([]byte("secret"), "X-Signature", SignatureScheme{Hash: sha256.New})
+	})
+}
+
+func TestWebhookMiddlewareTestSuite(t *testing.T) {
+	goyave.RunTest(t, new(WebhookMiddlewareTestSuite))
+}
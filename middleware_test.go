@@ -2,9 +2,13 @@ package goyave
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -28,7 +32,6 @@ type MiddlewareTestSuite struct {
 
 func (suite *MiddlewareTestSuite) SetupSuite() {
 	lang.LoadDefault()
-	maxPayloadSize = int64(config.GetFloat("server.maxUploadSize") * 1024 * 1024)
 }
 
 func addFileToRequest(writer *multipart.Writer, path, name, fileName string) {
@@ -117,6 +120,37 @@ func (suite *MiddlewareTestSuite) TestRecoveryMiddlewarePanic() {
 	suite.Equal(500, response.status)
 }
 
+func (suite *MiddlewareTestSuite) TestRecoveryMiddlewareLog() {
+	buf := &bytes.Buffer{}
+	prevLogger := ErrLogger
+	ErrLogger = log.New(buf, "", 0)
+	defer func() {
+		ErrLogger = prevLogger
+	}()
+
+	recorder := httptest.NewRecorder()
+	response := newResponse(recorder, nil)
+	request := createTestRequest(httptest.NewRequest("POST", "/test-route?secret=1", nil))
+	request.Extra = map[string]interface{}{RequestIDExtraKey: "request-id"}
+	recoveryMiddleware(func(response *Response, r *Request) {
+		panic("error message")
+	})(response, request)
+	suite.Equal(500, response.status)
+	suite.Equal("request-id", recorder.Header().Get(RequestIDHeader))
+	suite.True(strings.HasPrefix(buf.String(), "POST /test-route [request-id] error message\ngoroutine "))
+
+	// Without request ID
+	buf.Reset()
+	recorder = httptest.NewRecorder()
+	response = newResponse(recorder, nil)
+	request = createTestRequest(httptest.NewRequest("GET", "/test-route", nil))
+	recoveryMiddleware(func(response *Response, r *Request) {
+		panic("error message")
+	})(response, request)
+	suite.Empty(recorder.Header().Get(RequestIDHeader))
+	suite.True(strings.HasPrefix(buf.String(), "GET /test-route error message\ngoroutine "))
+}
+
 func (suite *MiddlewareTestSuite) TestRecoveryMiddlewareNoPanic() {
 	response := newResponse(httptest.NewRecorder(), nil)
 	recoveryMiddleware(func(response *Response, r *Request) {
@@ -278,6 +312,319 @@ func (suite *MiddlewareTestSuite) TestParseJsonRequestMiddleware() {
 
 }
 
+func (suite *MiddlewareTestSuite) TestParseJSONUseNumber() {
+	const body = "{\"id\":9007199254740993,\"price\":1.5}"
+
+	// Default: numbers are decoded as float64, large integers lose precision
+	rawRequest := httptest.NewRequest("POST", "/test-route", strings.NewReader(body))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	executed := false
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Equal(float64(9007199254740992), r.Data["id"])
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	prev := config.Get("server.jsonUseNumber")
+	config.Set("server.jsonUseNumber", true)
+	defer config.Set("server.jsonUseNumber", prev)
+
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(body))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	request := createTestRequest(rawRequest)
+	request.Rules = validation.RuleSet{
+		"id":    {"required", "integer", "min:1"},
+		"price": {"required", "numeric"},
+	}.AsRules()
+	executed = false
+	recorder := httptest.NewRecorder()
+	response := newResponse(recorder, nil)
+	parseRequestMiddleware(func(response *Response, r *Request) {
+		suite.Equal(json.Number("9007199254740993"), r.Data["id"])
+		suite.Equal(9007199254740993, r.Integer("id"))
+		suite.Equal(1.5, r.Numeric("price"))
+		validateRequestMiddleware(func(response *Response, r *Request) {
+			suite.Equal(9007199254740993, r.Data["id"])
+			suite.Equal(1.5, r.Data["price"])
+			response.JSON(http.StatusOK, map[string]interface{}{"id": r.Integer("id")})
+			executed = true
+		})(response, r)
+	})(response, request)
+	suite.True(executed)
+	suite.Equal("{\"id\":9007199254740993}\n", recorder.Body.String())
+
+	// Trailing data is still rejected
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(body+"{}"))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	executed = false
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Nil(r.Data)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+}
+
+func (suite *MiddlewareTestSuite) TestParseJSONMaxDepth() {
+	prev := config.Get("server.maxJSONDepth")
+	config.Set("server.maxJSONDepth", 3)
+	defer config.Set("server.maxJSONDepth", prev)
+
+	// Just under the limit
+	rawRequest := httptest.NewRequest("POST", "/test-route", strings.NewReader(`{"a":[{"b":"[[{{"}]}`))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	executed := false
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.NotNil(r.Data)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	// Just over the limit
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(`{"a":[{"b":[]}]}`))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	request := createTestRequest(rawRequest)
+	request.Lang = "en-US"
+	response := newResponse(httptest.NewRecorder(), nil)
+	executed = false
+	parseRequestMiddleware(func(response *Response, r *Request) {
+		executed = true
+	})(response, request)
+	suite.False(executed)
+	suite.Equal(http.StatusBadRequest, response.GetStatus())
+	suite.Equal(validation.Errors{"error": {"The JSON body may not be nested more than 3 levels deep."}}, response.GetError())
+}
+
+func (suite *MiddlewareTestSuite) TestParseJSONNoMaxDepth() {
+	prev := config.Get("server.maxJSONDepth")
+	config.Set("server.maxJSONDepth", 0)
+	defer config.Set("server.maxJSONDepth", prev)
+
+	rawRequest := httptest.NewRequest("POST", "/test-route", strings.NewReader(`{"a":[{"b":[[{"c":1}]]}]}`))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	executed := false
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.NotNil(r.Data)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	suite.False(exceedsJSONDepth([]byte(`[[[[]]]]`), -1))
+}
+
+func (suite *MiddlewareTestSuite) TestParseRawBody() {
+	body := `{"string":"hello world","number":42}`
+	rawRequest := httptest.NewRequest("POST", "/test-route", strings.NewReader(body))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	request := createTestRequest(rawRequest)
+	response := newResponse(httptest.NewRecorder(), nil)
+	executed := false
+
+	// A middleware consumes the body
+	consumer := func(next Handler) Handler {
+		return func(response *Response, r *Request) {
+			suite.Equal(body, string(r.RawBody()))
+			read, err := ioutil.ReadAll(r.Request().Body)
+			suite.Nil(err)
+			suite.Equal(body, string(read))
+			next(response, r)
+		}
+	}
+	parseRequestMiddleware(consumer(func(response *Response, r *Request) {
+		suite.Equal(body, string(r.RawBody()))
+		suite.Equal("hello world", r.Data["string"])
+		suite.Equal(42.0, r.Data["number"])
+		executed = true
+	}))(response, request)
+	suite.True(executed)
+
+	// Form
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader("string=hello+world"))
+	rawRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	executed = false
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Equal("string=hello+world", string(r.RawBody()))
+		suite.Equal("hello world", r.Data["string"])
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	// No body
+	rawRequest = httptest.NewRequest("GET", "/test-route", nil)
+	executed = false
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Nil(r.RawBody())
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	// Too large
+	prev := config.Get("server.maxUploadSize")
+	config.Set("server.maxUploadSize", 0.00001)
+	defer config.Set("server.maxUploadSize", prev)
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(strings.Repeat("a", 100)))
+	rawRequest.Header.Set("Content-Type", "text/plain")
+	request = createTestRequest(rawRequest)
+	response = newResponse(httptest.NewRecorder(), nil)
+	executed = false
+	parseRequestMiddleware(func(response *Response, r *Request) {
+		executed = true
+	})(response, request)
+	suite.False(executed)
+	suite.Nil(request.RawBody())
+	suite.Equal(http.StatusRequestEntityTooLarge, response.GetStatus())
+}
+
+func (suite *MiddlewareTestSuite) TestExceedsJSONDepth() {
+	suite.False(exceedsJSONDepth([]byte(`{}`), 1))
+	suite.True(exceedsJSONDepth([]byte(`{"a":{}}`), 1))
+	suite.False(exceedsJSONDepth([]byte(`[1,[2],[3]]`), 2))
+	suite.True(exceedsJSONDepth([]byte(`[1,[2,[3]]]`), 2))
+	suite.False(exceedsJSONDepth([]byte(`{"a":"{[{[\"{["}`), 1))
+	suite.False(exceedsJSONDepth([]byte(`"[[[["`), 0))
+	suite.True(exceedsJSONDepth([]byte(strings.Repeat("[", 10000)), 64))
+}
+
+func (suite *MiddlewareTestSuite) TestParseEmptyBodyMiddleware() {
+	// GET without body
+	executed := false
+	rawRequest := httptest.NewRequest("GET", "/test-route?query=param", nil)
+	rawRequest.Body = nil
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Equal(map[string]interface{}{"query": "param"}, r.Data)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	// POST with empty body
+	executed = false
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(""))
+	rawRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.NotNil(r.Data)
+		suite.Empty(r.Data)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	// POST with "Content-Length: 0" and JSON content type
+	executed = false
+	rawRequest = httptest.NewRequest("POST", "/test-route?query=param", http.NoBody)
+	rawRequest.Header.Set("Content-Type", "application/json")
+	rawRequest.Header.Set("Content-Length", "0")
+	rawRequest.ContentLength = 0
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Equal(map[string]interface{}{"query": "param"}, r.Data)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	executed = false
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(""))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.NotNil(r.Data)
+		suite.Empty(r.Data)
+		body, err := ioutil.ReadAll(r.Request().Body)
+		suite.Nil(err)
+		suite.Empty(body)
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+}
+
+func (suite *MiddlewareTestSuite) TestParseCompressedRequestMiddleware() {
+	payload := "{\"string\":\"hello world\", \"number\":42}"
+	var gzipBody bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipBody)
+	if _, err := gzipWriter.Write([]byte(payload)); err != nil {
+		panic(err)
+	}
+	gzipWriter.Close()
+
+	rawRequest := httptest.NewRequest("POST", "/test-route", &gzipBody)
+	rawRequest.Header.Set("Content-Type", "application/json")
+	rawRequest.Header.Set("Content-Encoding", "gzip")
+	executed := false
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Equal("hello world", r.Data["string"])
+		suite.Equal(42.0, r.Data["number"])
+		suite.Empty(r.Header().Get("Content-Encoding"))
+		suite.Equal(int64(len(payload)), r.ContentLength())
+		body, err := ioutil.ReadAll(r.Request().Body)
+		suite.Nil(err)
+		suite.Equal(payload, string(body))
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	var deflateBody bytes.Buffer
+	deflateWriter := zlib.NewWriter(&deflateBody)
+	if _, err := deflateWriter.Write([]byte("string=hello%20world")); err != nil {
+		panic(err)
+	}
+	deflateWriter.Close()
+
+	rawRequest = httptest.NewRequest("POST", "/test-route", &deflateBody)
+	rawRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rawRequest.Header.Set("Content-Encoding", "deflate")
+	executed = false
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Equal("hello world", r.Data["string"])
+		executed = true
+	})
+	suite.True(executed)
+	res.Body.Close()
+
+	// Unsupported encoding
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(payload))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	rawRequest.Header.Set("Content-Encoding", "br")
+	response := newResponse(httptest.NewRecorder(), nil)
+	parseRequestMiddleware(nil)(response, createTestRequest(rawRequest))
+	suite.Equal(http.StatusUnsupportedMediaType, response.GetStatus())
+
+	// Invalid gzip body
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader(payload))
+	rawRequest.Header.Set("Content-Type", "application/json")
+	rawRequest.Header.Set("Content-Encoding", "gzip")
+	response = newResponse(httptest.NewRecorder(), nil)
+	parseRequestMiddleware(nil)(response, createTestRequest(rawRequest))
+	suite.Equal(http.StatusBadRequest, response.GetStatus())
+}
+
+func (suite *MiddlewareTestSuite) TestParseCompressedRequestBomb() {
+	prev := config.Get("server.maxUploadSize")
+	config.Set("server.maxUploadSize", 1.0)
+	defer config.Set("server.maxUploadSize", prev)
+
+	// 10 MiB of zeros compress to a few KiB.
+	var body bytes.Buffer
+	gzipWriter := gzip.NewWriter(&body)
+	if _, err := gzipWriter.Write(make([]byte, 10<<20)); err != nil {
+		panic(err)
+	}
+	gzipWriter.Close()
+	suite.Less(body.Len(), 1<<20)
+
+	rawRequest := httptest.NewRequest("POST", "/test-route", &body)
+	rawRequest.Header.Set("Content-Type", "application/octet-stream")
+	rawRequest.Header.Set("Content-Encoding", "gzip")
+	response := newResponse(httptest.NewRecorder(), nil)
+	parseRequestMiddleware(nil)(response, createTestRequest(rawRequest))
+	suite.Equal(http.StatusRequestEntityTooLarge, response.GetStatus())
+}
+
 func (suite *MiddlewareTestSuite) TestParseMultipartRequestMiddleware() {
 	executed := false
 	rawRequest := createTestFileRequest("/test-route?test=hello", "resources/img/logo/goyave_16.png")
@@ -296,7 +643,6 @@ func (suite *MiddlewareTestSuite) TestParseMultipartRequestMiddleware() {
 	// Test payload too large
 	prev := config.Get("server.maxUploadSize")
 	config.Set("server.maxUploadSize", -10.0)
-	maxPayloadSize = int64(config.GetFloat("server.maxUploadSize") * 1024 * 1024)
 	rawRequest = createTestFileRequest("/test-route?test=hello", "resources/img/logo/goyave_16.png")
 
 	request := createTestRequest(rawRequest)
@@ -307,7 +653,6 @@ func (suite *MiddlewareTestSuite) TestParseMultipartRequestMiddleware() {
 
 	prev = config.Get("server.maxUploadSize")
 	config.Set("server.maxUploadSize", 0.0006)
-	maxPayloadSize = int64(config.GetFloat("server.maxUploadSize") * 1024 * 1024)
 	rawRequest = createTestFileRequest("/test-route?test=hello", "resources/img/logo/goyave_16.png")
 
 	request = createTestRequest(rawRequest)
@@ -315,7 +660,182 @@ func (suite *MiddlewareTestSuite) TestParseMultipartRequestMiddleware() {
 	parseRequestMiddleware(nil)(response, request)
 	suite.Equal(http.StatusRequestEntityTooLarge, response.GetStatus())
 	config.Set("server.maxUploadSize", prev)
-	maxPayloadSize = int64(config.GetFloat("server.maxUploadSize") * 1024 * 1024)
+}
+
+func (suite *MiddlewareTestSuite) TestParseMultipartMixedFields() {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	addFileToRequest(writer, "resources/img/logo/goyave_16.png", "file", "goyave_16.png")
+	for _, f := range [][2]string{{"name", "goyave"}, {"tags", "web"}, {"tags", "framework"}, {"count", "42"}} {
+		if err := writer.WriteField(f[0], f[1]); err != nil {
+			panic(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		panic(err)
+	}
+	rawRequest, err := http.NewRequest("POST", "/test-route", body)
+	if err != nil {
+		panic(err)
+	}
+	rawRequest.Header.Set("Content-Type", writer.FormDataContentType())
+
+	rules := validation.RuleSet{
+		"file":  {"required", "file", "image"},
+		"name":  {"required", "string"},
+		"tags":  {"required", "array:string"},
+		"count": {"required", "integer", "min:10"},
+	}
+	executed := false
+	handler := func(response *Response, r *Request) {
+		suite.Len(r.Data, 4)
+		suite.Equal("goyave", r.Data["name"])
+		suite.Equal([]string{"web", "framework"}, r.Data["tags"])
+		suite.Equal(42, r.Data["count"])
+		files, ok := r.Data["file"].([]filesystem.File)
+		suite.True(ok)
+		suite.Len(files, 1)
+		executed = true
+	}
+	request := createTestRequest(rawRequest)
+	request.Rules = rules.AsRules()
+	response := newResponse(httptest.NewRecorder(), nil)
+	parseRequestMiddleware(validateRequestMiddleware(handler))(response, request)
+	suite.True(executed)
+	suite.Equal(0, response.GetStatus())
+}
+
+func (suite *MiddlewareTestSuite) TestParseMultipartCleanupFiles() {
+	createTmp := func() *os.File {
+		tmp, err := ioutil.TempFile("", "goyave-upload-")
+		if err != nil {
+			panic(err)
+		}
+		return tmp
+	}
+
+	var tmp *os.File
+	handler := func(response *Response, r *Request) {
+		tmp = createTmp()
+		files := r.Data["file"].([]filesystem.File)
+		r.Data["file"] = append(files, filesystem.File{Data: tmp})
+	}
+	// Disabled by default
+	suite.False(config.GetBool("server.cleanupUploadedFiles"))
+	rawRequest := createTestFileRequest("/test-route", "resources/img/logo/goyave_16.png")
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, handler)
+	res.Body.Close()
+	suite.True(filesystem.FileExists(tmp.Name()))
+	filesystem.CleanupFiles([]filesystem.File{{Data: tmp}})
+
+	config.Set("server.cleanupUploadedFiles", true)
+	defer config.Set("server.cleanupUploadedFiles", false)
+	rawRequest = createTestFileRequest("/test-route", "resources/img/logo/goyave_16.png")
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, handler)
+	res.Body.Close()
+	suite.False(filesystem.FileExists(tmp.Name()))
+}
+
+func (suite *MiddlewareTestSuite) TestParseMultipartUploadTempDir() {
+	prevLimit := uploadMemoryLimit
+	uploadMemoryLimit = 0
+	defer func() { uploadMemoryLimit = prevLimit }()
+
+	dir, err := ioutil.TempDir("", "goyave-test-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	uploadDir := dir + string(os.PathSeparator) + "uploads"
+
+	config.Set("server.uploadTempDir", uploadDir)
+	defer config.Set("server.uploadTempDir", nil)
+	config.Set("server.cleanupUploadedFiles", true)
+	defer config.Set("server.cleanupUploadedFiles", false)
+
+	expected, err := ioutil.ReadFile("resources/img/logo/goyave_16.png")
+	if err != nil {
+		panic(err)
+	}
+
+	tmpName := ""
+	executed := false
+	rawRequest := createTestFileRequest("/test-route", "resources/img/logo/goyave_16.png")
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		executed = true
+		suite.Equal("world", r.Data["field"])
+		files, ok := r.Data["file"].([]filesystem.File)
+		if !suite.True(ok) || !suite.Len(files, 1) {
+			return
+		}
+		tmp, ok := files[0].Data.(*os.File)
+		if !suite.True(ok) {
+			return
+		}
+		tmpName = tmp.Name()
+		suite.Equal(uploadDir, filepath.Dir(tmpName))
+		suite.Equal("image/png", files[0].MIMEType)
+		suite.Equal(int64(len(expected)), files[0].Header.Size)
+		suite.Equal("goyave_16.png", files[0].Header.Filename)
+
+		content, err := ioutil.ReadAll(tmp)
+		suite.Nil(err)
+		suite.Equal(expected, content)
+	})
+	res.Body.Close()
+	suite.True(executed)
+	suite.NotEmpty(tmpName)
+	suite.False(filesystem.FileExists(tmpName))
+
+	// Temp dir cannot be created: it would be under a regular file
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+	config.Set("server.uploadTempDir", "go.mod"+string(os.PathSeparator)+"uploads")
+	request := createTestRequest(createTestFileRequest("/test-route", "resources/img/logo/goyave_16.png"))
+	response := newResponse(httptest.NewRecorder(), nil)
+	executed = false
+	parseRequestMiddleware(func(response *Response, r *Request) {
+		executed = true
+	})(response, request)
+	suite.False(executed)
+	suite.Equal(http.StatusInternalServerError, response.GetStatus())
+	suite.IsType(&os.PathError{}, response.GetError())
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if suite.NotEmpty(logger.entries) {
+		suite.Equal("error", logger.entries[0].level)
+		suite.Contains(logger.entries[0].message, "mkdir go.mod")
+	}
+
+	// Malformed multipart bodies are not server errors
+	config.Set("server.uploadTempDir", nil)
+	rawRequest = httptest.NewRequest("POST", "/test-route", strings.NewReader("--boundary\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.txt\"\r\n\r\ntruncated"))
+	rawRequest.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	executed = false
+	res = testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		suite.Nil(r.Data)
+		executed = true
+	})
+	res.Body.Close()
+	suite.True(executed)
+}
+
+func (suite *MiddlewareTestSuite) TestParseMultipartDefaultTempDir() {
+	prevLimit := uploadMemoryLimit
+	uploadMemoryLimit = 0
+	defer func() { uploadMemoryLimit = prevLimit }()
+
+	rawRequest := createTestFileRequest("/test-route", "resources/img/logo/goyave_16.png")
+	res := testMiddleware(parseRequestMiddleware, rawRequest, nil, validation.RuleSet{}, nil, func(response *Response, r *Request) {
+		files := r.Data["file"].([]filesystem.File)
+		tmp, ok := files[0].Data.(*os.File)
+		if suite.True(ok) {
+			suite.Equal(filepath.Clean(os.TempDir()), filepath.Dir(tmp.Name()))
+		}
+	})
+	res.Body.Close()
 }
 
 func (suite *MiddlewareTestSuite) TestParseMultipartOverrideMiddleware() {
@@ -451,6 +971,77 @@ func (suite *MiddlewareTestSuite) TestValidateMiddleware() {
 	suite.Equal("{\"validationError\":{\"error\":[\"Malformed JSON\"]}}\n", string(body))
 }
 
+func (suite *MiddlewareTestSuite) TestValidateMiddlewareErrorStatus() {
+	router := NewRouter()
+	router.Post("/validate", func(response *Response, request *Request) {
+		response.Status(http.StatusNoContent)
+	}).Validate(validation.RuleSet{
+		"number": {"required", "numeric", "min:50"},
+	})
+
+	request := func(body string) int {
+		rawRequest := httptest.NewRequest("POST", "/validate", strings.NewReader(body))
+		rawRequest.Header.Set("Content-Type", "application/json")
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, rawRequest)
+		result := writer.Result()
+		result.Body.Close()
+		return result.StatusCode
+	}
+
+	suite.Equal(http.StatusNoContent, request("{\"number\":60}"))
+	suite.Equal(http.StatusUnprocessableEntity, request("{\"number\":42}"))
+	suite.Equal(http.StatusBadRequest, request("{\"number\":"))
+
+	prev := config.Get("server.validationErrorStatus")
+	config.Set("server.validationErrorStatus", http.StatusBadRequest)
+	defer config.Set("server.validationErrorStatus", prev)
+	suite.Equal(http.StatusBadRequest, request("{\"number\":42}"))
+	suite.Equal(http.StatusBadRequest, request("{\"number\":"))
+}
+
+func (suite *MiddlewareTestSuite) TestValidateParams() {
+	router := NewRouter()
+	router.Post("/products/{id}", func(response *Response, request *Request) {
+		response.String(http.StatusOK, request.Params["id"])
+	}).ValidateParams(validation.RuleSet{
+		"id": {"required", "integer", "between:1,100"},
+	}).Validate(validation.RuleSet{
+		"name": {"required"},
+	})
+
+	request := func(url, body string) (int, string) {
+		rawRequest := httptest.NewRequest("POST", url, strings.NewReader(body))
+		rawRequest.Header.Set("Content-Type", "application/json")
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, rawRequest)
+		result := writer.Result()
+		content, err := ioutil.ReadAll(result.Body)
+		if err != nil {
+			panic(err)
+		}
+		result.Body.Close()
+		return result.StatusCode, string(content)
+	}
+
+	status, body := request("/products/42", "{\"name\":\"product\"}")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("42", body)
+
+	status, body = request("/products/420", "{\"name\":\"product\"}")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+	suite.Equal("{\"validationError\":{\"id\":[\"The id must be between 1 and 100.\"]}}\n", body)
+
+	// Parameters are validated before the body
+	status, body = request("/products/abc", "{}")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+	suite.Equal("{\"validationError\":{\"id\":[\"The id must be an integer.\"]}}\n", body)
+
+	status, body = request("/products/42", "{}")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+	suite.Equal("{\"validationError\":{\"name\":[\"The name is required.\"]}}\n", body)
+}
+
 func (suite *MiddlewareTestSuite) TestCORSMiddleware() {
 	// No CORS options
 	rawRequest := httptest.NewRequest("GET", "/test-route", nil)
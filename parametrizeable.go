@@ -9,10 +9,33 @@ import (
 // parameterizable represents a route or router accepting
 // parameters in its URI.
 type parameterizable struct {
-	regex      *regexp.Regexp
-	parameters []string
+	regex       *regexp.Regexp
+	parameters  []string
+	specificity []int
+
+	// literalPrefix the beginning of the URI which doesn't contain
+	// any parameter or regex special character.
+	literalPrefix string
+
+	// literal is true if the whole URI is a literal prefix, so it
+	// can be matched without evaluating the regex.
+	literal bool
+	ends    bool
 }
 
+// regexSpecialChars the characters having a special meaning in a regex.
+// URI parts containing them cannot be matched with a simple string comparison.
+const regexSpecialChars = `\.+*?()|[]{}^$`
+
+// Specificity ranks of a URI segment. The lower the rank,
+// the more specific the segment.
+const (
+	segmentStatic = iota
+	segmentConstrained
+	segmentOpen
+	segmentWildcard
+)
+
 // compileParameters parse the route parameters and compiles their regexes if needed.
 // If "ends" is set to true, the generated regex ends with "$", thus set "ends" to true
 // if you're compiling route parameters, set to false if you're compiling router parameters.
@@ -30,10 +53,13 @@ func (p *parameterizable) compileParameters(uri string, ends bool, regexCache ma
 
 	builder.WriteString("^")
 	length := len(idxs)
+	p.specificity = make([]int, 0, strings.Count(uri, "/"))
+	segment := segmentStatic
 	if length > 0 {
 		end := 0
 		for i := 0; i < length; i += 2 {
 			raw := uri[end:idxs[i]]
+			segment = p.addSegments(raw, segment)
 			end = idxs[i+1]
 			sub := uri[idxs[i]+1 : end]
 			parts := strings.SplitN(sub, ":", 2)
@@ -41,11 +67,16 @@ func (p *parameterizable) compileParameters(uri string, ends bool, regexCache ma
 				panic(fmt.Errorf("invalid route parameter, missing name in %q", sub))
 			}
 			pattern := "[^/]+" // default pattern
+			kind := segmentOpen
 			if len(parts) == 2 {
 				pattern = parts[1]
 				if pattern == "" {
 					panic(fmt.Errorf("invalid route parameter, missing pattern in %q", sub))
 				}
+				kind = parameterKind(pattern)
+			}
+			if kind > segment {
+				segment = kind
 			}
 
 			builder.WriteString(raw)
@@ -56,9 +87,12 @@ func (p *parameterizable) compileParameters(uri string, ends bool, regexCache ma
 			p.parameters = append(p.parameters, parts[0])
 		}
 		builder.WriteString(uri[end:])
+		segment = p.addSegments(uri[end:], segment)
 	} else {
 		builder.WriteString(uri)
+		segment = p.addSegments(uri, segment)
 	}
+	p.specificity = append(p.specificity, segment)
 
 	if ends {
 		builder.WriteString("$")
@@ -78,6 +112,64 @@ func (p *parameterizable) compileParameters(uri string, ends bool, regexCache ma
 		panic(fmt.Sprintf("route %s contains capture groups in its regexp. ", uri) +
 			"Only non-capturing groups are accepted: e.g. (?:pattern) instead of (pattern)")
 	}
+
+	p.literalPrefix = uri
+	if i := strings.IndexAny(uri, regexSpecialChars); i != -1 {
+		p.literalPrefix = uri[:i]
+	}
+	p.literal = p.literalPrefix == uri
+	p.ends = ends
+}
+
+// matchPath returns the result of the regex submatch on the given path.
+// The regex is only evaluated if the path starts with the literal prefix
+// of the URI, and literal URIs are matched without regex.
+func (p *parameterizable) matchPath(path string) []string {
+	if !strings.HasPrefix(path, p.literalPrefix) {
+		return nil
+	}
+	if p.literal {
+		if p.ends && len(path) != len(p.literalPrefix) {
+			return nil
+		}
+		return []string{p.literalPrefix}
+	}
+	return p.regex.FindStringSubmatch(path)
+}
+
+// addSegments appends the rank of the current segment to the specificity
+// for each slash found in the given raw URI part. Returns the rank
+// of the segment still being read at the end of the raw part.
+func (p *parameterizable) addSegments(raw string, segment int) int {
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '/' {
+			p.specificity = append(p.specificity, segment)
+			segment = segmentStatic
+		}
+	}
+	return segment
+}
+
+// parameterKind returns the specificity rank of a route parameter
+// using the given pattern. Patterns able to match a slash, such as ".*",
+// span several segments and are considered wildcards.
+func parameterKind(pattern string) int {
+	if matched, err := regexp.MatchString("^(?:"+pattern+")$", "/"); err == nil && matched {
+		return segmentWildcard
+	}
+	return segmentConstrained
+}
+
+// isMoreSpecific returns true if this URI is strictly more specific than
+// the given one. Segments are compared from left to right: the first segment
+// having a different rank determines the result.
+func (p *parameterizable) isMoreSpecific(other *parameterizable) bool {
+	for i := 0; i < len(p.specificity) && i < len(other.specificity); i++ {
+		if p.specificity[i] != other.specificity[i] {
+			return p.specificity[i] < other.specificity[i]
+		}
+	}
+	return false
 }
 
 // braceIndices returns the first level curly brace indices from a string.
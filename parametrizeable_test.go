@@ -128,6 +128,73 @@ func (suite *ParameterizableTestSuite) TestRegexCache() {
 	suite.Same(p1.regex, p2.regex)
 }
 
+func (suite *ParameterizableTestSuite) TestMatchPath() {
+	regexCache := make(map[string]*regexp.Regexp, 5)
+	uris := []string{
+		"",
+		"/",
+		"/product",
+		"/product/",
+		"/product/{id}",
+		"/product/{id:[0-9]+}",
+		"/product/{id:[0-9]+}/{name}",
+		"/product/{id:[0-9]+}/accessories",
+		"/{category}/{id:[0-9]+}",
+		"/files/{path:.*}",
+		"/export.csv",
+		"/product/export.{format:csv|json}",
+		"/items/(?:new)",
+		"/é/{name}",
+	}
+	paths := []string{
+		"",
+		"/",
+		"/product",
+		"/product/",
+		"/products",
+		"/product/42",
+		"/product/abc",
+		"/product/42/screwdriver",
+		"/product/42/accessories",
+		"/product/42/accessories/",
+		"/tools/42",
+		"/files/",
+		"/files/a/b/c.txt",
+		"/export.csv",
+		"/exportXcsv",
+		"/export.csv/",
+		"/product/export.json",
+		"/product/exportXxml",
+		"/items/new",
+		"/é/name",
+	}
+
+	for _, ends := range []bool{true, false} {
+		for _, uri := range uris {
+			p := &parameterizable{}
+			p.compileParameters(uri, ends, regexCache)
+			for _, path := range paths {
+				suite.Equal(p.regex.FindStringSubmatch(path), p.matchPath(path), "URI %q (ends: %t), path %q", uri, ends, path)
+			}
+		}
+	}
+
+	p := &parameterizable{}
+	p.compileParameters("/product/{id:[0-9]+}", true, regexCache)
+	suite.Equal("/product/", p.literalPrefix)
+	suite.False(p.literal)
+
+	p = &parameterizable{}
+	p.compileParameters("/product/export.csv", true, regexCache)
+	suite.Equal("/product/export", p.literalPrefix)
+	suite.False(p.literal)
+
+	p = &parameterizable{}
+	p.compileParameters("/product", false, regexCache)
+	suite.Equal("/product", p.literalPrefix)
+	suite.True(p.literal)
+}
+
 func (suite *ParameterizableTestSuite) TestGetParameters() {
 	p := &parameterizable{
 		parameters: []string{"a", "b"},
@@ -1,20 +1,42 @@
 package goyave
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/imdario/mergo"
+	"goyave.dev/goyave/v3/clock"
+	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/cors"
 
 	"github.com/google/uuid"
+	"goyave.dev/goyave/v3/encryption"
+	"goyave.dev/goyave/v3/helper"
 	"goyave.dev/goyave/v3/helper/filesystem"
 	"goyave.dev/goyave/v3/validation"
 )
 
+const (
+	// RequestIDHeader the name of the header carrying the request ID.
+	// See "middleware.RequestID".
+	RequestIDHeader = "X-Request-Id"
+
+	// RequestIDExtraKey the key of the request ID in "Request.Extra".
+	RequestIDExtraKey = "requestID"
+)
+
 // Request struct represents an http request.
 // Contains the validated body in the Data attribute if the route was defined with a request generator function
 type Request struct {
@@ -28,6 +50,8 @@ type Request struct {
 	User        interface{}
 	Lang        string
 	cookies     []*http.Cookie
+	rawBody     []byte
+	startTime   time.Time
 }
 
 // Request return the raw http request.
@@ -36,6 +60,55 @@ func (r *Request) Request() *http.Request {
 	return r.httpRequest
 }
 
+// Context returns the context of the request. It is canceled when the client
+// disconnects or when the deadline set by a middleware is exceeded.
+func (r *Request) Context() context.Context {
+	return r.httpRequest.Context()
+}
+
+// SetContext replace the context of the request. This can be used by middleware
+// to set a deadline on the request:
+//
+//  ctx, cancel := context.WithTimeout(request.Context(), 5*time.Second)
+//  defer cancel()
+//  request.SetContext(ctx)
+//  next(response, request)
+//
+// If the deadline is exceeded, the framework responds with "504 Gateway Timeout".
+func (r *Request) SetContext(ctx context.Context) {
+	r.httpRequest = r.httpRequest.WithContext(ctx)
+}
+
+// ID returns the identifier of the request, assigned by the
+// "middleware.RequestID" middleware. Returns an empty string if
+// the middleware is not applied to the route.
+func (r *Request) ID() string {
+	id, _ := r.Extra[RequestIDExtraKey].(string)
+	return id
+}
+
+// RawBody returns the body of the request as it was received, before parsing.
+// The body is buffered by the framework, so it can be read by middleware (for
+// signature verification for example) and handlers, even after "Data" has been
+// populated. The body is decompressed if it was sent with a supported "Content-Encoding".
+//
+// Returns nil if the request has no body or if it exceeds the "server.maxUploadSize" limit.
+// The returned slice must not be modified.
+func (r *Request) RawBody() []byte {
+	return r.rawBody
+}
+
+// StartTime returns the time the request started to be handled, before
+// the execution of the middleware.
+func (r *Request) StartTime() time.Time {
+	return r.startTime
+}
+
+// Elapsed returns the time elapsed since the request started to be handled.
+func (r *Request) Elapsed() time.Duration {
+	return clock.Since(r.startTime)
+}
+
 // Method specifies the HTTP method (GET, POST, PUT, etc.).
 func (r *Request) Method() string {
 	return r.httpRequest.Method
@@ -53,7 +126,11 @@ func (r *Request) URI() *url.URL {
 	return r.httpRequest.URL
 }
 
-// Route returns the current route.
+// Route returns the route matched for this request.
+// Its name, methods and URI template are available through
+// GetName(), GetMethods() and GetFullURI(). This is useful in
+// middleware needing to identify the route, for metrics or
+// permission lookups for example.
 func (r *Request) Route() *Route {
 	return r.route
 }
@@ -93,6 +170,140 @@ func (r *Request) RemoteAddress() string {
 	return r.httpRequest.RemoteAddr
 }
 
+// TLS returns the state of the TLS connection the request was received on.
+// Returns nil if the request was received over plain HTTP.
+func (r *Request) TLS() *tls.ConnectionState {
+	return r.httpRequest.TLS
+}
+
+// ClientCertificates returns the certificates presented by the client,
+// leaf first, if the server requests them (see the "server.tls.clientAuth"
+// config entry). Returns nil if the request was received over plain HTTP
+// or if the client didn't present any certificate.
+func (r *Request) ClientCertificates() []*x509.Certificate {
+	if r.httpRequest.TLS == nil {
+		return nil
+	}
+	return r.httpRequest.TLS.PeerCertificates
+}
+
+// TrustedProxy returns true if the immediate peer that sent the request
+// is a trusted proxy, meaning its address is in one of the networks listed
+// in the "server.trustedProxies" config entry.
+//
+// The forwarding headers ("X-Forwarded-For", "X-Forwarded-Proto" and
+// "X-Forwarded-Host") are only taken into account by "ClientIP()", "Scheme()"
+// and "Host()" if this returns true, as they can be forged by any client.
+func (r *Request) TrustedProxy() bool {
+	return isTrustedProxy(net.ParseIP(remoteIP(r.httpRequest.RemoteAddr)))
+}
+
+// ClientIP returns the IP address of the client that sent the request,
+// without port.
+//
+// If the request was sent by a trusted proxy (see "TrustedProxy()"), the
+// "X-Forwarded-For" header is read from right to left and the first address
+// that is not a trusted proxy is returned. Otherwise, the address of the
+// immediate peer is returned.
+func (r *Request) ClientIP() string {
+	ip := remoteIP(r.httpRequest.RemoteAddr)
+	if !isTrustedProxy(net.ParseIP(ip)) {
+		return ip
+	}
+	forwarded := strings.Split(strings.Join(r.httpRequest.Header["X-Forwarded-For"], ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		parsed := net.ParseIP(addr)
+		if parsed == nil {
+			break
+		}
+		ip = addr
+		if !isTrustedProxy(parsed) {
+			break
+		}
+	}
+	return ip
+}
+
+// Scheme returns the scheme used by the client to send the request,
+// "http" or "https". If the request was sent by a trusted proxy
+// (see "TrustedProxy()"), the "X-Forwarded-Proto" header is used.
+func (r *Request) Scheme() string {
+	if proto := r.forwardedHeader("X-Forwarded-Proto"); proto != "" {
+		proto = strings.ToLower(proto)
+		if proto == "http" || proto == "https" {
+			return proto
+		}
+	}
+	if r.httpRequest.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Host returns the host requested by the client, with port if any.
+// If the request was sent by a trusted proxy (see "TrustedProxy()"),
+// the "X-Forwarded-Host" header is used.
+func (r *Request) Host() string {
+	if host := r.forwardedHeader("X-Forwarded-Host"); host != "" {
+		return host
+	}
+	return r.httpRequest.Host
+}
+
+// BaseURL returns the base URL the client used to reach the application,
+// made of the scheme and host of the request (see "Scheme()" and "Host()"),
+// followed by the "server.basePath" config entry. The base path is the path
+// under which a reverse proxy exposes the application, such as "/api".
+//
+// Unlike "goyave.BaseURL()", which is built from the server configuration,
+// this is correct behind a trusted reverse proxy, so use it to generate
+// absolute URLs sent back to the client (redirects, links in emails, etc).
+func (r *Request) BaseURL() string {
+	return r.Scheme() + "://" + r.Host() + config.GetString("server.basePath")
+}
+
+// AbsoluteURL returns an absolute URL pointing to the given path,
+// using "BaseURL()".
+//
+//  request.AbsoluteURL("/users/1") // "https://example.org/api/users/1"
+func (r *Request) AbsoluteURL(path string) string {
+	if path == "" {
+		return r.BaseURL()
+	}
+	return r.BaseURL() + "/" + strings.TrimPrefix(path, "/")
+}
+
+// RouteURL returns an absolute URL pointing to the named route,
+// using "BaseURL()" and "Route.BuildURI()".
+// Panics if the route doesn't exist or if the amount of parameters doesn't
+// match the amount of actual parameters for this route.
+//
+//  request.RouteURL("user.show", "1") // "https://example.org/api/users/1"
+func (r *Request) RouteURL(name string, parameters ...string) string {
+	var route *Route
+	if r.route != nil && r.route.parent != nil {
+		route = r.route.parent.GetRoute(name)
+	}
+	if route == nil {
+		panic(fmt.Errorf("Route %q doesn't exist", name))
+	}
+	return r.BaseURL() + route.BuildURI(parameters...)
+}
+
+// forwardedHeader returns the first value of the given forwarding header
+// if the request was sent by a trusted proxy. Returns an empty string otherwise.
+func (r *Request) forwardedHeader(name string) string {
+	value := r.httpRequest.Header.Get(name)
+	if value == "" || !r.TrustedProxy() {
+		return ""
+	}
+	if i := strings.Index(value, ","); i != -1 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
 // Cookies returns the HTTP cookies sent with the request.
 func (r *Request) Cookies(name string) []*http.Cookie {
 	if r.cookies == nil {
@@ -101,6 +312,39 @@ func (r *Request) Cookies(name string) []*http.Cookie {
 	return r.cookies
 }
 
+// SignedCookie returns the value of the cookie identified by the given name,
+// after verifying its signature. The cookie must have been set using
+// "response.SignedCookie()". If "encrypted" is true, the value is decrypted.
+//
+// Returns "http.ErrNoCookie" if the cookie is not found and
+// "encryption.ErrInvalidSignature" if its value has been tampered with.
+func (r *Request) SignedCookie(name string, encrypted bool) (string, error) {
+	cookie, err := r.httpRequest.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	i := strings.LastIndex(cookie.Value, ".")
+	if i == -1 {
+		return "", encryption.ErrInvalidSignature
+	}
+	value, signature := cookie.Value[:i], cookie.Value[i+1:]
+	if err := encryption.Verify(signedCookieMessage(name, value), signature); err != nil {
+		return "", err
+	}
+
+	var plaintext []byte
+	if encrypted {
+		plaintext, err = encryption.Decrypt(value)
+	} else {
+		plaintext, err = base64.RawURLEncoding.DecodeString(value)
+	}
+	if err != nil {
+		return "", encryption.ErrInvalidSignature
+	}
+	return string(plaintext), nil
+}
+
 // Referrer returns the referring URL, if sent in the request.
 func (r *Request) Referrer() string {
 	return r.httpRequest.Referer()
@@ -129,6 +373,72 @@ func (r *Request) BearerToken() (string, bool) {
 	return strings.TrimSpace(header[len(schema):]), true
 }
 
+// Accepts returns the content type of the given list the client prefers,
+// according to the "Accept" header and its quality values. Wildcards
+// ("text/*", "*/*") are supported. Returns the first given content type if the
+// request has no "Accept" header, or an empty string if none is acceptable.
+//
+//  switch request.Accepts("application/json", "text/html") {
+//  case "text/html":
+//      response.RenderHTML(http.StatusOK, "user.html", user)
+//  case "application/json":
+//      response.JSON(http.StatusOK, user)
+//  default:
+//      response.Status(http.StatusNotAcceptable)
+//  }
+func (r *Request) Accepts(contentTypes ...string) string {
+	header := r.Header().Get("Accept")
+	if header == "" {
+		if len(contentTypes) == 0 {
+			return ""
+		}
+		return contentTypes[0]
+	}
+
+	accepted := helper.ParseMultiValuesHeader(header)
+	best := ""
+	bestPriority := 0.0
+	for _, contentType := range contentTypes {
+		if priority := mediaTypePriority(accepted, contentType); priority > bestPriority {
+			best, bestPriority = contentType, priority
+		}
+	}
+	return best
+}
+
+// mediaTypePriority returns the quality value of the most specific
+// media range of the given list matching the given content type.
+// Parameters of the content type are ignored.
+func mediaTypePriority(accepted []helper.HeaderValue, contentType string) float64 {
+	if i := strings.IndexByte(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	mainType := contentType
+	if i := strings.IndexByte(contentType, '/'); i != -1 {
+		mainType = contentType[:i]
+	}
+
+	priority := 0.0
+	specificity := -1
+	for _, a := range accepted {
+		value := strings.ToLower(a.Value)
+		s := -1
+		switch value {
+		case contentType:
+			s = 2
+		case mainType + "/*":
+			s = 1
+		case "*/*", "*":
+			s = 0
+		}
+		if s > specificity {
+			priority, specificity = a.Priority, s
+		}
+	}
+	return priority
+}
+
 // CORSOptions returns the CORS options applied to this request, or nil.
 // The returned object is a copy of the options applied to the router.
 // Therefore, altering the returned object will not alter the router's options.
@@ -150,51 +460,138 @@ func (r *Request) Has(field string) bool {
 // String get a string field from the request data.
 // Panics if the field is not a string.
 func (r *Request) String(field string) string {
-	str, ok := r.Data[field].(string)
+	str, ok := r.StringOpt(field)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not a string", field)
+		panic(fmt.Sprintf("Field \"%s\" is not a string", field))
 	}
 	return str
 }
 
+// StringOpt get a string field from the request data.
+// The returned bool is false if the field doesn't exist or is not a string.
+func (r *Request) StringOpt(field string) (string, bool) {
+	str, ok := r.Data[field].(string)
+	return str, ok
+}
+
 // Numeric get a numeric field from the request data.
 // Panics if the field is not numeric.
 func (r *Request) Numeric(field string) float64 {
-	str, ok := r.Data[field].(float64)
+	num, ok := r.NumericOpt(field)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not numeric", field)
+		panic(fmt.Sprintf("Field \"%s\" is not numeric", field))
 	}
-	return str
+	return num
+}
+
+// NumericOpt get a numeric field from the request data.
+// Integers, floats of any size and "json.Number" are converted to float64.
+// The returned bool is false if the field doesn't exist or is not numeric.
+func (r *Request) NumericOpt(field string) (float64, bool) {
+	if number, ok := r.Data[field].(json.Number); ok {
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	value := reflect.ValueOf(r.Data[field])
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	}
+	return 0, false
 }
 
 // Integer get an integer field from the request data.
 // Panics if the field is not an integer.
 func (r *Request) Integer(field string) int {
-	str, ok := r.Data[field].(int)
+	integer, ok := r.IntegerOpt(field)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not an integer", field)
+		panic(fmt.Sprintf("Field \"%s\" is not an integer", field))
 	}
-	return str
+	return integer
+}
+
+// IntegerOpt get an integer field from the request data.
+// Floats without decimal part, such as numbers decoded from JSON
+// bodies, are accepted and converted to int. "json.Number" values
+// (see the "server.jsonUseNumber" config entry) are converted without
+// precision loss.
+// The returned bool is false if the field doesn't exist or is not an integer.
+func (r *Request) IntegerOpt(field string) (int, bool) {
+	if number, ok := r.Data[field].(json.Number); ok {
+		if i, err := number.Int64(); err == nil {
+			return int(i), true
+		}
+		f, err := number.Float64()
+		if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, false
+		}
+		return int(f), true
+	}
+	value := reflect.ValueOf(r.Data[field])
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int(f), true
+		}
+	}
+	return 0, false
 }
 
 // Bool get a bool field from the request data.
 // Panics if the field is not a bool.
 func (r *Request) Bool(field string) bool {
-	str, ok := r.Data[field].(bool)
+	b, ok := r.BoolOpt(field)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not a bool", field)
+		panic(fmt.Sprintf("Field \"%s\" is not a bool", field))
 	}
-	return str
+	return b
+}
+
+// BoolOpt get a bool field from the request data.
+// The returned bool is false if the field doesn't exist or is not a bool.
+func (r *Request) BoolOpt(field string) (bool, bool) {
+	b, ok := r.Data[field].(bool)
+	return b, ok
 }
 
 // File get a file field from the request data.
-// Panics if the field is not numeric.
+// Uploaded files are always stored as a slice, even if the client
+// sent a single file.
+// Panics if the field is not a file.
 func (r *Request) File(field string) []filesystem.File {
-	str, ok := r.Data[field].([]filesystem.File)
+	files, ok := r.Data[field].([]filesystem.File)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not a file", field)
+		panic(fmt.Sprintf("Field \"%s\" is not a file", field))
 	}
-	return str
+	return files
+}
+
+// FirstFile get the first uploaded file of a file field from the request data.
+// The returned bool is false if the field doesn't exist, is not a file
+// or doesn't contain any file.
+func (r *Request) FirstFile(field string) (filesystem.File, bool) {
+	files, ok := r.Data[field].([]filesystem.File)
+	if !ok || len(files) == 0 {
+		return filesystem.File{}, false
+	}
+	return files[0], true
+}
+
+// Files get the uploaded files of a file field from the request data.
+// Unlike "File()", this method doesn't panic: it returns nil if the field
+// doesn't exist or is not a file.
+func (r *Request) Files(field string) []filesystem.File {
+	files, _ := r.Data[field].([]filesystem.File)
+	return files
 }
 
 // Timezone get a timezone field from the request data.
@@ -202,7 +599,7 @@ func (r *Request) File(field string) []filesystem.File {
 func (r *Request) Timezone(field string) *time.Location {
 	str, ok := r.Data[field].(*time.Location)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not a timezone", field)
+		panic(fmt.Sprintf("Field \"%s\" is not a timezone", field))
 	}
 	return str
 }
@@ -212,7 +609,7 @@ func (r *Request) Timezone(field string) *time.Location {
 func (r *Request) IP(field string) net.IP {
 	str, ok := r.Data[field].(net.IP)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not an IP", field)
+		panic(fmt.Sprintf("Field \"%s\" is not an IP", field))
 	}
 	return str
 }
@@ -222,7 +619,7 @@ func (r *Request) IP(field string) net.IP {
 func (r *Request) URL(field string) *url.URL {
 	str, ok := r.Data[field].(*url.URL)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not a URL", field)
+		panic(fmt.Sprintf("Field \"%s\" is not a URL", field))
 	}
 	return str
 }
@@ -232,7 +629,7 @@ func (r *Request) URL(field string) *url.URL {
 func (r *Request) UUID(field string) uuid.UUID {
 	str, ok := r.Data[field].(uuid.UUID)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not an UUID", field)
+		panic(fmt.Sprintf("Field \"%s\" is not an UUID", field))
 	}
 	return str
 }
@@ -242,7 +639,7 @@ func (r *Request) UUID(field string) uuid.UUID {
 func (r *Request) Date(field string) time.Time {
 	str, ok := r.Data[field].(time.Time)
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not a date", field)
+		panic(fmt.Sprintf("Field \"%s\" is not a date", field))
 	}
 	return str
 }
@@ -252,11 +649,73 @@ func (r *Request) Date(field string) time.Time {
 func (r *Request) Object(field string) map[string]interface{} {
 	str, ok := r.Data[field].(map[string]interface{})
 	if !ok {
-		ErrLogger.Panicf("Field \"%s\" is not an object", field)
+		panic(fmt.Sprintf("Field \"%s\" is not an object", field))
 	}
 	return str
 }
 
+// Validated returns a copy of the request data containing only the fields
+// present in the route's validation rules. Use it to protect against mass
+// assignment, by ignoring the fields a client may send in addition to the
+// expected ones.
+//
+// Nested fields (e.g. "user.email") are copied into nested maps. Objects whose
+// fields are validated individually only contain the validated fields, like
+// with strict fields validation. Arrays are copied as a whole if the array field
+// itself has rules (e.g. "tags": {"array", ">string"}). Nested fields inside
+// arrays (e.g. "items.name" when "items" is an array) are not validated, so
+// they are not copied.
+//
+// Returns an empty map if the route doesn't have validation rules.
+func (r *Request) Validated() map[string]interface{} {
+	validated := make(map[string]interface{})
+	if r.Rules == nil {
+		return validated
+	}
+
+	paths := make(map[string]bool, len(r.Rules.Fields))
+	parents := make(map[string]bool, len(r.Rules.Fields))
+	for path := range r.Rules.Fields {
+		paths[path] = true
+		for i := strings.LastIndex(path, "."); i != -1; i = strings.LastIndex(path[:i], ".") {
+			parents[path[:i]] = true
+		}
+	}
+	for path := range paths {
+		copyValidatedField(validated, r.Data, strings.Split(path, "."), !parents[path])
+	}
+	return validated
+}
+
+// copyValidatedField copies the value identified by the given path segments from
+// "src" to "dst", creating the intermediate objects in "dst" if needed.
+// If "whole" is false and the value is an object, only an empty object is
+// created so its validated fields can be copied into it.
+func copyValidatedField(dst, src map[string]interface{}, segments []string, whole bool) {
+	value, exists := src[segments[0]]
+	if !exists {
+		return
+	}
+	object, isObject := value.(map[string]interface{})
+	if len(segments) == 1 {
+		if whole || !isObject {
+			dst[segments[0]] = value
+		} else if _, ok := dst[segments[0]].(map[string]interface{}); !ok {
+			dst[segments[0]] = make(map[string]interface{}, len(object))
+		}
+		return
+	}
+	if !isObject {
+		return
+	}
+	child, ok := dst[segments[0]].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		dst[segments[0]] = child
+	}
+	copyValidatedField(child, object, segments[1:], whole)
+}
+
 // ToStruct map the request data to a struct.
 //  type UserInsertRequest struct {
 // 	 Username string
@@ -284,3 +743,97 @@ func (r *Request) validate() validation.Errors {
 
 	return nil
 }
+
+// validateParams validates the route parameters using the rules
+// defined with "Route.ValidateParams()".
+func (r *Request) validateParams() validation.Errors {
+	if r.route == nil || r.route.paramsRules == nil {
+		return nil
+	}
+
+	params := make(map[string]interface{}, len(r.Params))
+	for name, value := range r.Params {
+		params[name] = value
+	}
+	errors := validation.Validate(params, r.route.paramsRules, false, r.Lang)
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// remoteIP returns the host part of the given remote address.
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// isTrustedProxy returns true if the given IP is in one of the
+// networks listed in the "server.trustedProxies" config entry.
+func isTrustedProxy(ip net.IP) bool {
+	if ip == nil || !config.IsLoaded() {
+		return false
+	}
+	for _, network := range getTrustedProxies() {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// trustedProxyList the parsed "server.trustedProxies" config entry
+// and a copy of the values it has been parsed from.
+type trustedProxyList struct {
+	source   []string
+	networks []*net.IPNet
+}
+
+var trustedProxiesCache atomic.Value
+
+// getTrustedProxies returns the networks listed in the "server.trustedProxies"
+// config entry. The entry is only parsed again if its values changed.
+func getTrustedProxies() []*net.IPNet {
+	proxies := config.GetStringSlice("server.trustedProxies")
+	if len(proxies) == 0 {
+		return nil
+	}
+	if cached, ok := trustedProxiesCache.Load().(trustedProxyList); ok && equalStrings(cached.source, proxies) {
+		return cached.networks
+	}
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		} else if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+				bits = 8 * net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	// The slice is copied so changes made in place to the config entry are detected
+	source := append(make([]string, 0, len(proxies)), proxies...)
+	trustedProxiesCache.Store(trustedProxyList{source, networks})
+	return networks
+}
+
+// equalStrings returns true if the two given slices contain the same values
+// in the same order. Unlike "helper.SliceEqual()", it doesn't use reflection.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
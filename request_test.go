@@ -1,6 +1,8 @@
 package goyave
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +11,8 @@ import (
 	"testing"
 	"time"
 
+	"goyave.dev/goyave/v3/clock"
+	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/cors"
 
 	"github.com/google/uuid"
@@ -22,6 +26,7 @@ func createTestRequest(rawRequest *http.Request) *Request {
 		httpRequest: rawRequest,
 		Rules:       &validation.Rules{},
 		Params:      map[string]string{},
+		startTime:   clock.Now(),
 	}
 }
 func TestRequestContentLength(t *testing.T) {
@@ -40,6 +45,23 @@ func TestRequestMethod(t *testing.T) {
 	assert.Equal(t, "POST", request.Method())
 }
 
+func TestRequestStartTime(t *testing.T) {
+	before := time.Now()
+	request := createTestRequest(httptest.NewRequest("GET", "/test-route", nil))
+	assert.False(t, request.StartTime().Before(before))
+	assert.False(t, request.StartTime().After(time.Now()))
+	time.Sleep(time.Millisecond)
+	assert.Greater(t, int64(request.Elapsed()), int64(0))
+
+	fake := clock.NewFake(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Reset()
+	request = createTestRequest(httptest.NewRequest("GET", "/test-route", nil))
+	assert.Equal(t, fake.Now(), request.StartTime())
+	fake.Advance(15 * time.Millisecond)
+	assert.Equal(t, 15*time.Millisecond, request.Elapsed())
+}
+
 func TestRequestRemoteAddress(t *testing.T) {
 	rawRequest := httptest.NewRequest("GET", "/test-route", strings.NewReader("body"))
 	request := createTestRequest(rawRequest)
@@ -135,6 +157,87 @@ func TestRequestValidate(t *testing.T) {
 	assert.Nil(t, errors)
 }
 
+func TestRequestValidated(t *testing.T) {
+	request := createTestRequest(httptest.NewRequest("POST", "/test-route", nil))
+	request.Data = map[string]interface{}{
+		"name":     "John",
+		"tags":     []string{"a", "b"},
+		"is_admin": true,
+		"password": "secret",
+	}
+	assert.Empty(t, request.Validated())
+
+	request.Rules = validation.RuleSet{
+		"name":     {"required", "string"},
+		"tags":     {"array:string", ">max:5"},
+		"optional": {"string"},
+		"password": {"required", "string", "confirmed"},
+	}.AsRules()
+	request.Data["password_confirmation"] = "secret"
+
+	validated := request.Validated()
+	assert.Equal(t, map[string]interface{}{
+		"name":     "John",
+		"tags":     []string{"a", "b"},
+		"password": "secret",
+	}, validated)
+
+	validated["name"] = "modified"
+	assert.Equal(t, "John", request.Data["name"])
+	assert.Equal(t, true, request.Data["is_admin"])
+}
+
+func TestRequestValidatedNested(t *testing.T) {
+	request := createTestRequest(httptest.NewRequest("POST", "/test-route", nil))
+	request.Data = map[string]interface{}{
+		"user": map[string]interface{}{
+			"email":    "john@example.org",
+			"is_admin": true,
+			"address": map[string]interface{}{
+				"city":   "Paris",
+				"secret": "value",
+			},
+		},
+		"settings": map[string]interface{}{
+			"theme": "dark",
+			"other": 1,
+		},
+		"tags":   []interface{}{"a", "b"},
+		"items":  []interface{}{map[string]interface{}{"name": "item", "extra": 1}},
+		"scalar": "value",
+		"empty":  map[string]interface{}{"unvalidated": 1},
+	}
+	request.Rules = validation.RuleSet{
+		"user":              {"required", "object"},
+		"user.email":        {"required", "email"},
+		"user.address.city": {"required", "string"},
+		"user.missing":      {"string"},
+		"settings.theme":    {"required", "string"},
+		"empty":             {"object"},
+		"empty.field":       {"string"},
+		"tags":              {"array", ">string"},
+		"items.name":        {"string"},
+		"scalar.nested":     {"string"},
+	}.AsRules()
+
+	validated := request.Validated()
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{
+			"email": "john@example.org",
+			"address": map[string]interface{}{
+				"city": "Paris",
+			},
+		},
+		"settings": map[string]interface{}{
+			"theme": "dark",
+		},
+		"tags":  []interface{}{"a", "b"},
+		"empty": map[string]interface{}{},
+	}, validated)
+	assert.NotContains(t, validated, "items") // "items.name" doesn't validate array elements, "extra" must not leak
+	assert.Equal(t, true, request.Data["user"].(map[string]interface{})["is_admin"])
+}
+
 func TestRequestAccessors(t *testing.T) {
 	loc, err := time.LoadLocation("America/New_York")
 	if err != nil {
@@ -184,6 +287,25 @@ func TestRequestAccessors(t *testing.T) {
 	assert.Len(t, files, 1)
 	assert.Equal(t, "image/png", files[0].MIMEType)
 
+	request.Data["jsonInt"] = json.Number("9007199254740993")
+	request.Data["jsonFloat"] = json.Number("1.5")
+	assert.Equal(t, 9007199254740993, request.Integer("jsonInt"))
+	assert.Equal(t, 1.5, request.Numeric("jsonFloat"))
+	integer, ok := request.IntegerOpt("jsonFloat")
+	assert.Equal(t, 0, integer)
+	assert.False(t, ok)
+
+	file, ok := request.FirstFile("file")
+	assert.True(t, ok)
+	assert.Equal(t, "image/png", file.MIMEType)
+	_, ok = request.FirstFile("string")
+	assert.False(t, ok)
+	_, ok = request.FirstFile("doesn't exist")
+	assert.False(t, ok)
+	assert.Equal(t, files, request.Files("file"))
+	assert.Nil(t, request.Files("string"))
+	assert.Nil(t, request.Files("doesn't exist"))
+
 	assert.Equal(t, "America/New_York", request.Timezone("timezone").String())
 	assert.Equal(t, "127.0.0.1", request.IP("ip").String())
 	assert.Equal(t, "3bbcee75-cecc-5b56-8031-b6641c1ed1f1", request.UUID("uuid").String())
@@ -247,6 +369,37 @@ func TestGetBearerToken(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestRequestAccepts(t *testing.T) {
+	request := createTestRequest(httptest.NewRequest("GET", "/test-route", nil))
+	assert.Equal(t, "application/json", request.Accepts("application/json", "text/html"))
+	assert.Empty(t, request.Accepts())
+
+	cases := []struct {
+		accept   string
+		offers   []string
+		expected string
+	}{
+		{"text/html", []string{"application/json", "text/html"}, "text/html"},
+		{"text/html", []string{"application/json"}, ""},
+		{"application/json, text/html", []string{"text/html", "application/json"}, "text/html"},
+		{"text/html;q=0.5, application/json", []string{"text/html", "application/json"}, "application/json"},
+		{"text/html;q=0.8, application/json;q=0.9", []string{"text/html", "application/json"}, "application/json"},
+		{"text/*", []string{"application/json", "text/plain"}, "text/plain"},
+		{"text/*;q=0.5, text/csv", []string{"text/plain", "text/csv"}, "text/csv"},
+		{"*/*", []string{"application/xml", "application/json"}, "application/xml"},
+		{"*/*;q=0.1, application/json", []string{"application/xml", "application/json"}, "application/json"},
+		{"text/*, text/html;q=0", []string{"text/html"}, ""},
+		{"text/*, text/html;q=0", []string{"text/html", "text/plain"}, "text/plain"},
+		{"application/json;q=0", []string{"application/json"}, ""},
+		{"TEXT/HTML", []string{"text/html; charset=utf-8"}, "text/html; charset=utf-8"},
+		{"text/html, application/xhtml+xml, application/xml;q=0.9, */*;q=0.8", []string{"application/json", "application/xml"}, "application/xml"},
+	}
+	for _, c := range cases {
+		request.Header().Set("Accept", c.accept)
+		assert.Equal(t, c.expected, request.Accepts(c.offers...), "Accept: %q", c.accept)
+	}
+}
+
 func TestToStruct(t *testing.T) {
 	type UserInsertRequest struct {
 		Username string
@@ -268,3 +421,250 @@ func TestToStruct(t *testing.T) {
 	assert.Equal(t, "johndoe", userInsertRequest.Username)
 	assert.Equal(t, "johndoe@example.org", userInsertRequest.Email)
 }
+
+func TestRequestNilData(t *testing.T) {
+	request := createTestRequest(httptest.NewRequest("GET", "/test-route", nil))
+	request.Data = nil
+	request.Rules = validation.RuleSet{"name": {"string"}}.AsRules()
+
+	assert.False(t, request.Has("name"))
+	assert.Empty(t, request.Validated())
+
+	user := struct{ Name string }{}
+	assert.Nil(t, request.ToStruct(&user))
+	assert.Empty(t, user.Name)
+}
+
+func TestRequestOptAccessors(t *testing.T) {
+	request := createTestRequest(httptest.NewRequest("POST", "/test-route", nil))
+	request.Data = map[string]interface{}{
+		"string":  "hello world",
+		"integer": 42,
+		"json":    42.0,
+		"numeric": 42.3,
+		"uint":    uint8(7),
+		"bool":    true,
+	}
+
+	str, ok := request.StringOpt("string")
+	assert.True(t, ok)
+	assert.Equal(t, "hello world", str)
+	str, ok = request.StringOpt("integer")
+	assert.False(t, ok)
+	assert.Empty(t, str)
+	_, ok = request.StringOpt("doesn't exist")
+	assert.False(t, ok)
+
+	num, ok := request.NumericOpt("numeric")
+	assert.True(t, ok)
+	assert.Equal(t, 42.3, num)
+	num, ok = request.NumericOpt("integer")
+	assert.True(t, ok)
+	assert.Equal(t, 42.0, num)
+	num, ok = request.NumericOpt("uint")
+	assert.True(t, ok)
+	assert.Equal(t, 7.0, num)
+	num, ok = request.NumericOpt("string")
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, num)
+	_, ok = request.NumericOpt("doesn't exist")
+	assert.False(t, ok)
+
+	integer, ok := request.IntegerOpt("integer")
+	assert.True(t, ok)
+	assert.Equal(t, 42, integer)
+	integer, ok = request.IntegerOpt("json")
+	assert.True(t, ok)
+	assert.Equal(t, 42, integer)
+	integer, ok = request.IntegerOpt("numeric")
+	assert.False(t, ok)
+	assert.Equal(t, 0, integer)
+	_, ok = request.IntegerOpt("string")
+	assert.False(t, ok)
+	_, ok = request.IntegerOpt("doesn't exist")
+	assert.False(t, ok)
+
+	b, ok := request.BoolOpt("bool")
+	assert.True(t, ok)
+	assert.True(t, b)
+	b, ok = request.BoolOpt("string")
+	assert.False(t, ok)
+	assert.False(t, b)
+	_, ok = request.BoolOpt("doesn't exist")
+	assert.False(t, ok)
+
+	assert.Equal(t, 42, request.Integer("json"))
+	assert.Equal(t, 42.0, request.Numeric("integer"))
+	assert.Panics(t, func() { request.Integer("numeric") })
+
+	request.Data = nil
+	_, ok = request.StringOpt("string")
+	assert.False(t, ok)
+	_, ok = request.NumericOpt("numeric")
+	assert.False(t, ok)
+}
+
+type RequestProxyTestSuite struct {
+	TestSuite
+}
+
+func (suite *RequestProxyTestSuite) TearDownTest() {
+	config.Set("server.trustedProxies", []string{})
+}
+
+func (suite *RequestProxyTestSuite) request(remoteAddr string, headers map[string]string) *Request {
+	rawRequest := httptest.NewRequest("GET", "/test-route", nil)
+	rawRequest.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		rawRequest.Header.Set(k, v)
+	}
+	return suite.CreateTestRequest(rawRequest)
+}
+
+func (suite *RequestProxyTestSuite) TestTrustedProxy() {
+	suite.False(suite.request("10.0.0.1:1234", nil).TrustedProxy())
+
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8", "192.0.2.1", "::1"})
+	suite.True(suite.request("10.0.0.1:1234", nil).TrustedProxy())
+	suite.True(suite.request("192.0.2.1:1234", nil).TrustedProxy())
+	suite.True(suite.request("[::1]:1234", nil).TrustedProxy())
+	suite.False(suite.request("192.0.2.2:1234", nil).TrustedProxy())
+	suite.False(suite.request("invalid", nil).TrustedProxy())
+}
+
+func (suite *RequestProxyTestSuite) TestTrustedProxiesCache() {
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8", "192.0.2.1"})
+	networks := getTrustedProxies()
+	suite.Len(networks, 2)
+	suite.Equal("192.0.2.1/32", networks[1].String())
+	suite.Same(networks[0], getTrustedProxies()[0])
+
+	proxies := []string{"172.16.0.0/12"}
+	config.Set("server.trustedProxies", proxies)
+	networks = getTrustedProxies()
+	suite.Len(networks, 1)
+	suite.Equal("172.16.0.0/12", networks[0].String())
+
+	// In-place changes of the entry are detected
+	proxies[0] = "192.168.0.0/16"
+	networks = getTrustedProxies()
+	suite.Len(networks, 1)
+	suite.Equal("192.168.0.0/16", networks[0].String())
+	suite.True(suite.request("192.168.1.1:1234", nil).TrustedProxy())
+	suite.False(suite.request("172.16.0.1:1234", nil).TrustedProxy())
+}
+
+func (suite *RequestProxyTestSuite) TestClientIP() {
+	headers := map[string]string{"X-Forwarded-For": "203.0.113.1, 10.0.0.2"}
+
+	// Untrusted peer: headers are ignored
+	suite.Equal("10.0.0.1", suite.request("10.0.0.1:1234", headers).ClientIP())
+	suite.Equal("::1", suite.request("[::1]:1234", nil).ClientIP())
+	suite.Equal("invalid", suite.request("invalid", nil).ClientIP())
+
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8"})
+	suite.Equal("203.0.113.1", suite.request("10.0.0.1:1234", headers).ClientIP())
+	suite.Equal("10.0.0.1", suite.request("10.0.0.1:1234", nil).ClientIP())
+	suite.Equal("192.0.2.1", suite.request("192.0.2.1:1234", headers).ClientIP())
+
+	// Spoofed addresses on the left of an untrusted one are ignored
+	headers["X-Forwarded-For"] = "198.51.100.1, 203.0.113.1, 10.0.0.2"
+	suite.Equal("203.0.113.1", suite.request("10.0.0.1:1234", headers).ClientIP())
+
+	// Only trusted proxies: the leftmost address is the client
+	headers["X-Forwarded-For"] = "10.0.0.3, 10.0.0.2"
+	suite.Equal("10.0.0.3", suite.request("10.0.0.1:1234", headers).ClientIP())
+
+	// Invalid addresses stop the search
+	headers["X-Forwarded-For"] = "203.0.113.1, invalid, 10.0.0.2"
+	suite.Equal("10.0.0.2", suite.request("10.0.0.1:1234", headers).ClientIP())
+}
+
+func (suite *RequestProxyTestSuite) TestScheme() {
+	headers := map[string]string{"X-Forwarded-Proto": "HTTPS"}
+	suite.Equal("http", suite.request("10.0.0.1:1234", headers).Scheme())
+
+	request := suite.request("10.0.0.1:1234", nil)
+	request.httpRequest.TLS = &tls.ConnectionState{}
+	suite.Equal("https", request.Scheme())
+
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8"})
+	suite.Equal("https", suite.request("10.0.0.1:1234", headers).Scheme())
+	suite.Equal("http", suite.request("192.0.2.1:1234", headers).Scheme())
+	suite.Equal("http", suite.request("10.0.0.1:1234", map[string]string{"X-Forwarded-Proto": "http, https"}).Scheme())
+	suite.Equal("http", suite.request("10.0.0.1:1234", map[string]string{"X-Forwarded-Proto": "ftp"}).Scheme())
+}
+
+func (suite *RequestProxyTestSuite) TestHost() {
+	headers := map[string]string{"X-Forwarded-Host": "example.org"}
+	suite.Equal("example.com", suite.request("10.0.0.1:1234", headers).Host())
+
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8"})
+	suite.Equal("example.org", suite.request("10.0.0.1:1234", headers).Host())
+	suite.Equal("example.com", suite.request("192.0.2.1:1234", headers).Host())
+	suite.Equal("example.com", suite.request("10.0.0.1:1234", nil).Host())
+}
+
+func (suite *RequestProxyTestSuite) TestBaseURL() {
+	// Direct HTTPS
+	request := suite.request("192.0.2.1:1234", nil)
+	request.httpRequest.TLS = &tls.ConnectionState{}
+	suite.Equal("https://example.com", request.BaseURL())
+
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8"})
+	headers := map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "example.org",
+	}
+
+	// Forwarded HTTPS over an HTTP connection from a trusted proxy
+	suite.Equal("https://example.org", suite.request("10.0.0.1:1234", headers).BaseURL())
+
+	// Untrusted source is ignored
+	suite.Equal("http://example.com", suite.request("192.0.2.1:1234", headers).BaseURL())
+}
+
+func (suite *RequestProxyTestSuite) TestAbsoluteURL() {
+	router := NewRouter()
+	router.Subrouter("/users").Get("/{id}", nil).Name("user.show")
+	current := router.Get("/current", nil)
+
+	// Direct request
+	request := suite.request("192.0.2.1:1234", nil)
+	request.route = current
+	suite.Equal("http://example.com/users/1", request.AbsoluteURL("/users/1"))
+	suite.Equal("http://example.com/users/1", request.AbsoluteURL("users/1"))
+	suite.Equal("http://example.com", request.AbsoluteURL(""))
+	suite.Equal("http://example.com/users/1", request.RouteURL("user.show", "1"))
+
+	// Proxied request with base path
+	prevBasePath := config.Get("server.basePath")
+	config.Set("server.basePath", "/api")
+	defer config.Set("server.basePath", prevBasePath)
+	config.Set("server.trustedProxies", []string{"10.0.0.0/8"})
+	request = suite.request("10.0.0.1:1234", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "example.org",
+	})
+	request.route = current
+	suite.Equal("https://example.org/api", request.BaseURL())
+	suite.Equal("https://example.org/api/users/1", request.AbsoluteURL("/users/1"))
+	suite.Equal("https://example.org/api/users/1", request.RouteURL("user.show", "1"))
+
+	suite.Panics(func() {
+		request.RouteURL("unknown")
+	})
+	suite.Panics(func() {
+		request.RouteURL("user.show")
+	})
+	suite.Panics(func() {
+		suite.request("10.0.0.1:1234", nil).RouteURL("user.show", "1")
+	})
+	suite.Panics(func() {
+		config.Set("server.basePath", "/api/")
+	})
+}
+
+func TestRequestProxyTestSuite(t *testing.T) {
+	RunTest(t, new(RequestProxyTestSuite))
+}
@@ -3,20 +3,26 @@ package goyave
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	htmltemplate "html/template"
 	"io"
+	"io/fs"
 	"net"
 	"net/http"
 	"os"
 	"runtime/debug"
 	"strconv"
+	"syscall"
 	"text/template"
+	"time"
 
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v3/config"
+	"goyave.dev/goyave/v3/encryption"
 	"goyave.dev/goyave/v3/helper/filesystem"
 )
 
@@ -40,15 +46,20 @@ type Response struct {
 	responseWriter http.ResponseWriter
 	err            interface{}
 	httpRequest    *http.Request
+	request        *Request // Set by the router, used to get the current request context
 	stacktrace     string
 	status         int
 
 	// Used to check if controller didn't write anything so
 	// core can write default 204 No Content.
 	// See RFC 7231, 6.3.5
-	empty       bool
-	wroteHeader bool
-	hijacked    bool
+	empty        bool
+	wroteHeader  bool
+	hijacked     bool
+	disconnected bool
+
+	// Body accumulated in buffered mode. See "Buffer()".
+	buffer *bytes.Buffer
 }
 
 // newResponse create a new Response using the given http.ResponseWriter and raw request.
@@ -87,9 +98,19 @@ func (r *Response) PreWrite(b []byte) {
 
 // Write writes the data as a response.
 // See http.ResponseWriter.Write
+//
+// If the client disconnected, the returned error satisfies "IsClientDisconnect()".
+// This is not a server error: the disconnection is only logged at debug level.
 func (r *Response) Write(data []byte) (int, error) {
+	if r.buffer != nil {
+		return r.writeBuffer(data)
+	}
 	r.PreWrite(data)
-	return r.writer.Write(data)
+	n, err := r.writer.Write(data)
+	if err != nil && r.isClientDisconnect(err) {
+		r.clientDisconnected(err)
+	}
+	return n, err
 }
 
 // WriteHeader sends an HTTP response header with the provided
@@ -162,6 +183,143 @@ func (r *Response) SetWriter(writer io.Writer) {
 	r.writer = writer
 }
 
+// Buffer enables the buffered mode for this response: the body is accumulated
+// and only written at the end of the request's lifecycle, after all middleware
+// have been executed. This allows middleware to set headers or change
+// the status based on the body written by the handler, for example to compute an ETag:
+//
+//  func ETagMiddleware(next goyave.Handler) goyave.Handler {
+//      return func(response *goyave.Response, request *goyave.Request) {
+//          response.Buffer()
+//          next(response, request)
+//          sum := sha1.Sum(response.BufferedBody())
+//          response.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+//      }
+//  }
+//
+// To avoid unbounded memory usage, the buffer is flushed as soon as its size
+// exceeds the "server.maxResponseBufferSize" config entry (in MiB). From this point,
+// the response is written as usual and headers cannot be changed anymore.
+//
+// Has no effect if the response header has already been written.
+func (r *Response) Buffer() {
+	if r.buffer == nil && !r.wroteHeader {
+		r.buffer = &bytes.Buffer{}
+	}
+}
+
+// BufferedBody returns the body accumulated since "Buffer()" has been called.
+// Returns nil if the response is not buffered or if the buffer has been flushed.
+// The returned slice is only valid until the next write.
+func (r *Response) BufferedBody() []byte {
+	if r.buffer == nil {
+		return nil
+	}
+	return r.buffer.Bytes()
+}
+
+// ResetBuffer discards the body accumulated since "Buffer()" has been called.
+// The response stays buffered. Has no effect if the response is not buffered.
+func (r *Response) ResetBuffer() {
+	if r.buffer != nil {
+		r.buffer.Reset()
+	}
+}
+
+func (r *Response) writeBuffer(data []byte) (int, error) {
+	r.empty = false
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, _ := r.buffer.Write(data)
+	if int64(r.buffer.Len()) > maxResponseBufferSize() {
+		return n, r.flushBuffer()
+	}
+	return n, nil
+}
+
+// flushBuffer writes the buffered body and disables the buffered mode.
+func (r *Response) flushBuffer() error {
+	data := r.buffer.Bytes()
+	r.buffer = nil
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := r.Write(data)
+	return err
+}
+
+func maxResponseBufferSize() int64 {
+	return int64(config.GetFloat("server.maxResponseBufferSize") * 1024 * 1024)
+}
+
+// clientDisconnected logs the disconnection of the client at debug level,
+// only once per response.
+func (r *Response) clientDisconnected(err error) {
+	if r.disconnected {
+		return
+	}
+	r.disconnected = true
+	GetLogger().Debugf("%sclient disconnected: %v", r.logPrefix(), err)
+}
+
+// deadlineExceeded logs the request took too long to be processed
+// and sets the status to "504 Gateway Timeout".
+func (r *Response) deadlineExceeded(err error) {
+	GetLogger().Warnf("%srequest deadline exceeded: %v", r.logPrefix(), err)
+	r.err = err
+	r.status = http.StatusGatewayTimeout
+}
+
+func (r *Response) logPrefix() string {
+	if r.httpRequest == nil {
+		return ""
+	}
+	return r.httpRequest.Method + " " + r.httpRequest.URL.Path + " "
+}
+
+// IsClientDisconnect returns true if the given error is caused by the client
+// closing the connection before the response was entirely written: broken pipe,
+// connection reset, canceled request context or "http.ErrAbortHandler".
+//
+// "context.Canceled" is only considered a client disconnection if the given
+// request context is done too. Otherwise, the cancellation comes from
+// the server itself and should be treated as any other error.
+//
+//  if _, err := response.Write(data); IsClientDisconnect(request.Request().Context(), err) {
+//  	return
+//  }
+func IsClientDisconnect(ctx context.Context, err error) bool {
+	if errors.Is(err, context.Canceled) && ctx != nil && ctx.Err() != nil {
+		return true
+	}
+	return errors.Is(err, http.ErrAbortHandler) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// isDeadlineExceeded returns true if the given error is caused by the
+// deadline of the request context being exceeded.
+func (r *Response) isDeadlineExceeded(err error) bool {
+	var ctx context.Context
+	if r.request != nil {
+		ctx = r.request.Context()
+	} else if r.httpRequest != nil {
+		ctx = r.httpRequest.Context()
+	}
+	return ctx != nil && ctx.Err() == context.DeadlineExceeded && errors.Is(err, context.DeadlineExceeded)
+}
+
+// isClientDisconnect returns true if the given error is caused by
+// the client closing the connection. See "IsClientDisconnect()".
+func (r *Response) isClientDisconnect(err error) bool {
+	var ctx context.Context
+	if r.httpRequest != nil {
+		ctx = r.httpRequest.Context()
+	}
+	return IsClientDisconnect(ctx, err)
+}
+
 func (r *Response) close() error {
 	if wr, ok := r.writer.(io.Closer); ok {
 		return wr.Close()
@@ -212,28 +370,121 @@ func (r *Response) Status(status int) {
 }
 
 // JSON write json data as a response.
-// Also sets the "Content-Type" header automatically.
+// Also sets the "Content-Type" and "Content-Length" headers automatically.
 func (r *Response) JSON(responseCode int, data interface{}) error {
 	r.responseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
 	r.status = responseCode
-	return json.NewEncoder(r).Encode(data)
+	var buffer bytes.Buffer
+	if err := json.NewEncoder(&buffer).Encode(data); err != nil {
+		return err
+	}
+	return r.writeBody(responseCode, buffer.Bytes())
 }
 
-// String write a string as a response
+// String write a string as a response.
+// Also sets the "Content-Length" header automatically.
 func (r *Response) String(responseCode int, message string) error {
+	return r.writeBody(responseCode, []byte(message))
+}
+
+// Bytes write raw data as a response. The "Content-Type" header is set
+// to the given content type, unless it is empty. The "Content-Length"
+// header is set automatically.
+//
+//  response.Bytes(http.StatusOK, "image/png", image)
+func (r *Response) Bytes(responseCode int, contentType string, data []byte) error {
+	if contentType != "" {
+		r.responseWriter.Header().Set("Content-Type", contentType)
+	}
+	return r.writeBody(responseCode, data)
+}
+
+// writeBody writes the given data with the given status. The "Content-Length"
+// header is set if the data is the whole body: nothing has been written yet
+// and the status allows a body.
+func (r *Response) writeBody(responseCode int, data []byte) error {
 	r.status = responseCode
-	_, err := r.Write([]byte(message))
+	if !r.wroteHeader && r.empty && bodyAllowedForStatus(responseCode) {
+		r.responseWriter.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	}
+	_, err := r.Write(data)
 	return err
 }
 
-func (r *Response) writeFile(file string, disposition string) (int64, error) {
+// bodyAllowedForStatus reports whether a given response status code
+// permits a body. See RFC 7230, section 3.3.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
+
+func (r *Response) writeFile(file string, disposition string) error {
 	if !filesystem.FileExists(file) {
 		r.Status(http.StatusNotFound)
-		return 0, &os.PathError{Op: "open", Path: file, Err: fmt.Errorf("no such file or directory")}
+		return &os.PathError{Op: "open", Path: file, Err: fmt.Errorf("no such file or directory")}
 	}
+	mime, _ := filesystem.GetMIMEType(file)
+	f, _ := os.Open(file)
+	// No need to check for errors, filesystem.FileExists(file) and
+	// filesystem.GetMIMEType(file) already handled that.
+	defer f.Close()
+	var modTime time.Time
+	if stat, err := f.Stat(); err == nil {
+		modTime = stat.ModTime()
+	}
+	r.serveContent(f, modTime, mime, disposition)
+	return nil
+}
+
+func (r *Response) writeFileFS(fsys fs.FS, file string, disposition string) error {
+	f, err := fsys.Open(file)
+	if err != nil {
+		r.Status(http.StatusNotFound)
+		return err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		r.Status(http.StatusNotFound)
+		return err
+	}
+	if stat.IsDir() {
+		r.Status(http.StatusNotFound)
+		return &fs.PathError{Op: "open", Path: file, Err: fmt.Errorf("is a directory")}
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+	buffer := make([]byte, 512)
+	n, err := io.ReadFull(content, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r.serveContent(content, stat.ModTime(), filesystem.DetectMIMEType(file, buffer[:n]), disposition)
+	return nil
+}
+
+// serveContent writes the given content, handling range and conditional requests.
+// The "Content-Type" header is set to the given MIME type if not already set.
+func (r *Response) serveContent(content io.ReadSeeker, modTime time.Time, mime string, disposition string) {
 	r.empty = false
 	r.status = http.StatusOK
-	mime, size := filesystem.GetMIMEType(file)
 	header := r.responseWriter.Header()
 	header.Set("Content-Disposition", disposition)
 
@@ -241,13 +492,24 @@ func (r *Response) writeFile(file string, disposition string) (int64, error) {
 		header.Set("Content-Type", mime)
 	}
 
-	header.Set("Content-Length", strconv.FormatInt(size, 10))
+	request := r.httpRequest
+	if request == nil {
+		// Responses created without request (in tests for example)
+		// are sent entirely.
+		request = &http.Request{Method: http.MethodGet, Header: http.Header{}}
+	}
+	http.ServeContent(fileResponseWriter{r}, request, "", modTime, content)
+}
 
-	f, _ := os.Open(file)
-	// No need to check for errors, filesystem.FileExists(file) and
-	// filesystem.GetMIMEType(file) already handled that.
-	defer f.Close()
-	return io.Copy(r, f)
+// fileResponseWriter defers writing the status chosen by "http.ServeContent"
+// until the first write, so pre-writers (such as the gzip middleware) can
+// still alter the headers.
+type fileResponseWriter struct {
+	*Response
+}
+
+func (w fileResponseWriter) WriteHeader(status int) {
+	w.status = status
 }
 
 // File write a file as an inline element.
@@ -255,10 +517,14 @@ func (r *Response) writeFile(file string, disposition string) (int64, error) {
 // If the file doesn't exist, respond with status 404 Not Found.
 // The given path can be relative or absolute.
 //
+// Range requests are supported: the requested part of the file is
+// sent with status 206 Partial Content, or status 416 Range Not Satisfiable
+// is returned if the range is invalid. Conditional requests using the file's
+// modification time are handled as well.
+//
 // If you want the file to be sent as a download ("Content-Disposition: attachment"), use the "Download" function instead.
 func (r *Response) File(file string) error {
-	_, err := r.writeFile(file, "inline")
-	return err
+	return r.writeFile(file, "inline")
 }
 
 // Download write a file as an attachment element.
@@ -269,10 +535,27 @@ func (r *Response) File(file string) error {
 // The "fileName" parameter defines the name the client will see. In other words, it sets the header "Content-Disposition" to
 // "attachment; filename="${fileName}""
 //
+// Range requests are supported the same way as with the "File" function.
+//
 // If you want the file to be sent as an inline element ("Content-Disposition: inline"), use the "File" function instead.
 func (r *Response) Download(file string, fileName string) error {
-	_, err := r.writeFile(file, fmt.Sprintf("attachment; filename=\"%s\"", fileName))
-	return err
+	return r.writeFile(file, fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+}
+
+// FileFS write a file from the given file system as an inline element.
+// This works like "File", but the file is read from the given file system
+// (an "embed.FS" for example) instead of the disk.
+// If the file doesn't exist or is a directory, respond with status 404 Not Found.
+func (r *Response) FileFS(fsys fs.FS, file string) error {
+	return r.writeFileFS(fsys, file, "inline")
+}
+
+// DownloadFS write a file from the given file system as an attachment element.
+// This works like "Download", but the file is read from the given file system
+// (an "embed.FS" for example) instead of the disk.
+// If the file doesn't exist or is a directory, respond with status 404 Not Found.
+func (r *Response) DownloadFS(fsys fs.FS, file string, fileName string) error {
+	return r.writeFileFS(fsys, file, fmt.Sprintf("attachment; filename=\"%s\"", fileName))
 }
 
 // Error print the error in the console and return it with an error code 500.
@@ -280,19 +563,59 @@ func (r *Response) Download(file string, fileName string) error {
 // and the stacktrace is printed in the console.
 // If debugging is not enabled, only the status code is set, which means you can still
 // write to the response, or use your error status handler.
+//
+// Errors caused by a client disconnection (see "IsClientDisconnect()") are not server
+// errors: they are only logged at debug level and the status is left untouched.
+// If the error is caused by the deadline of the request context being exceeded
+// ("context.DeadlineExceeded"), a warning is logged and the status is set to
+// "504 Gateway Timeout" instead. Other deadlines, such as the one of a database
+// query, are server errors.
 func (r *Response) Error(err interface{}) error {
-	ErrLogger.Println(err)
+	if e, ok := err.(error); ok {
+		if r.isDeadlineExceeded(e) {
+			r.deadlineExceeded(e)
+			return nil
+		}
+		if r.isClientDisconnect(e) {
+			r.err = err
+			r.clientDisconnected(e)
+			return nil
+		}
+	}
+	GetLogger().Errorf("%v", err)
 	return r.error(err)
 }
 
+// ErrorMessage write an error response using the standard error envelope:
+//  {"error": {"code": 404, "message": "User not found."}}
+// The message is written as is. Use "lang.Get()" with the request's
+// language to localize it:
+//
+//  response.ErrorMessage(http.StatusNotFound, lang.Get(request.Lang, "user-not-found"))
+//
+// The framework's error responses use the same envelope.
+func (r *Response) ErrorMessage(status int, message string) error {
+	return r.ErrorJSON(status, map[string]interface{}{
+		"code":    status,
+		"message": message,
+	})
+}
+
+// ErrorJSON write an error response, wrapping the given payload in the
+// standard error envelope: {"error": payload}.
+// Prefer "ErrorMessage()" unless you need to send additional information.
+func (r *Response) ErrorJSON(status int, payload interface{}) error {
+	return r.JSON(status, map[string]interface{}{"error": payload})
+}
+
 func (r *Response) error(err interface{}) error {
 	r.err = err
 	if config.GetBool("app.debug") {
-		stacktrace := r.stacktrace
-		if stacktrace == "" {
-			stacktrace = string(debug.Stack())
+		if r.stacktrace == "" {
+			// Stacktraces of recovered panics are already logged
+			// by the recovery middleware.
+			GetLogger().Errorf("%s", debug.Stack())
 		}
-		ErrLogger.Print(stacktrace)
 		if !r.Hijacked() {
 			var message interface{}
 			if e, ok := err.(error); ok {
@@ -316,6 +639,40 @@ func (r *Response) Cookie(cookie *http.Cookie) {
 	http.SetCookie(r.responseWriter, cookie)
 }
 
+// SignedCookie add a Set-Cookie header to the response. The value of the
+// cookie is signed using the application key so it cannot be tampered with
+// by the client. If "encrypt" is true, the value is also encrypted using
+// AES-GCM so the client cannot read it.
+//
+// Use "request.SignedCookie()" to read and verify the cookie.
+// Returns an error if the "app.key" config entry is not set.
+func (r *Response) SignedCookie(cookie *http.Cookie, encrypt bool) error {
+	value := base64.RawURLEncoding.EncodeToString([]byte(cookie.Value))
+	if encrypt {
+		encrypted, err := encryption.Encrypt([]byte(cookie.Value))
+		if err != nil {
+			return err
+		}
+		value = encrypted
+	}
+
+	signature, err := encryption.Sign(signedCookieMessage(cookie.Name, value))
+	if err != nil {
+		return err
+	}
+
+	c := *cookie
+	c.Value = value + "." + signature
+	r.Cookie(&c)
+	return nil
+}
+
+// signedCookieMessage the cookie name is part of the signed message
+// so a signed value cannot be used for another cookie.
+func signedCookieMessage(name, value string) []byte {
+	return []byte(name + "=" + value)
+}
+
 // Redirect send a permanent redirect response
 func (r *Response) Redirect(url string) {
 	http.Redirect(r, r.httpRequest, url, http.StatusPermanentRedirect)
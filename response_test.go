@@ -2,6 +2,8 @@ package goyave
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -12,11 +14,14 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/database"
+	"goyave.dev/goyave/v3/encryption"
 )
 
 type ResponseTestSuite struct {
@@ -224,6 +229,164 @@ func (suite *ResponseTestSuite) TestResponseJSON() {
 		panic(err)
 	}
 	suite.Equal("{\"code\":200,\"status\":\"ok\"}\n", string(body))
+	suite.Equal(strconv.Itoa(len(body)), resp.Header.Get("Content-Length"))
+}
+
+func (suite *ResponseTestSuite) TestResponseContentLength() {
+	rawRequest := httptest.NewRequest("GET", "/test-route", nil)
+	response := newResponse(httptest.NewRecorder(), rawRequest)
+	suite.Nil(response.String(http.StatusOK, "hello world"))
+	resp := response.responseWriter.(*httptest.ResponseRecorder).Result()
+	suite.Equal("11", resp.Header.Get("Content-Length"))
+	resp.Body.Close()
+
+	// Bytes
+	response = newResponse(httptest.NewRecorder(), rawRequest)
+	suite.Nil(response.Bytes(http.StatusCreated, "image/png", []byte{1, 2, 3}))
+	resp = response.responseWriter.(*httptest.ResponseRecorder).Result()
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Equal("image/png", resp.Header.Get("Content-Type"))
+	suite.Equal("3", resp.Header.Get("Content-Length"))
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	suite.Nil(err)
+	suite.Equal([]byte{1, 2, 3}, body)
+
+	// Empty content type
+	response = newResponse(httptest.NewRecorder(), rawRequest)
+	response.Header().Set("Content-Type", "application/octet-stream")
+	suite.Nil(response.Bytes(http.StatusOK, "", []byte{1}))
+	resp = response.responseWriter.(*httptest.ResponseRecorder).Result()
+	suite.Equal("application/octet-stream", resp.Header.Get("Content-Type"))
+	resp.Body.Close()
+
+	// Status without body
+	response = newResponse(httptest.NewRecorder(), rawRequest)
+	response.String(http.StatusNoContent, "")
+	resp = response.responseWriter.(*httptest.ResponseRecorder).Result()
+	suite.Empty(resp.Header.Get("Content-Length"))
+	resp.Body.Close()
+
+}
+
+func (suite *ResponseTestSuite) TestBuffer() {
+	etag := func(next Handler) Handler {
+		return func(response *Response, request *Request) {
+			response.Buffer()
+			next(response, request)
+			if !response.IsHeaderWritten() {
+				response.Header().Set("ETag", "\""+strconv.Itoa(len(response.BufferedBody()))+"\"")
+			}
+		}
+	}
+
+	request := suite.CreateTestRequest(nil)
+	result := suite.Middleware(etag, request, func(response *Response, r *Request) {
+		response.Header().Set("Content-Type", "text/plain")
+		response.Status(http.StatusCreated)
+		response.Write([]byte("hello "))
+		response.Write([]byte("world"))
+		suite.False(response.IsEmpty())
+		suite.False(response.IsHeaderWritten())
+		suite.Equal([]byte("hello world"), response.BufferedBody())
+	})
+	body, err := ioutil.ReadAll(result.Body)
+	result.Body.Close()
+	suite.Nil(err)
+	suite.Equal(http.StatusCreated, result.StatusCode)
+	suite.Equal("\"11\"", result.Header.Get("ETag"))
+	suite.Equal("11", result.Header.Get("Content-Length"))
+	suite.Equal("hello world", string(body))
+
+	// Status handler
+	request = suite.CreateTestRequest(nil)
+	result = suite.Middleware(etag, request, func(response *Response, r *Request) {
+		response.Status(http.StatusNotFound)
+	})
+	body, err = ioutil.ReadAll(result.Body)
+	result.Body.Close()
+	suite.Nil(err)
+	suite.Equal(http.StatusNotFound, result.StatusCode)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", string(body))
+
+	// Spill: the buffer is flushed when it exceeds the limit
+	prev := config.Get("server.maxResponseBufferSize")
+	config.Set("server.maxResponseBufferSize", 10.0/1024/1024)
+	defer config.Set("server.maxResponseBufferSize", prev)
+	request = suite.CreateTestRequest(nil)
+	result = suite.Middleware(etag, request, func(response *Response, r *Request) {
+		response.Write([]byte("hello "))
+		suite.False(response.IsHeaderWritten())
+		response.Write([]byte("world"))
+		suite.True(response.IsHeaderWritten())
+		suite.Nil(response.BufferedBody())
+	})
+	body, err = ioutil.ReadAll(result.Body)
+	result.Body.Close()
+	suite.Nil(err)
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Empty(result.Header.Get("ETag"))
+	suite.Equal("hello world", string(body))
+
+	// Reset
+	response := newResponse(httptest.NewRecorder(), nil)
+	response.Buffer()
+	response.Write([]byte("hello"))
+	response.ResetBuffer()
+	suite.Empty(response.BufferedBody())
+	response.Write([]byte("world"))
+	suite.Equal([]byte("world"), response.BufferedBody())
+
+	// Header already written
+	response = newResponse(httptest.NewRecorder(), nil)
+	response.WriteHeader(http.StatusOK)
+	response.Buffer()
+	suite.Nil(response.BufferedBody())
+}
+
+func (suite *ResponseTestSuite) TestResponseFileRange() {
+	rawRequest := httptest.NewRequest("GET", "/test-route", nil)
+	rawRequest.Header.Set("Range", "bytes=3-7")
+	response := newResponse(httptest.NewRecorder(), rawRequest)
+	suite.Nil(response.File("resources/test_file.txt"))
+	resp := response.responseWriter.(*httptest.ResponseRecorder).Result()
+
+	suite.Equal(http.StatusPartialContent, resp.StatusCode)
+	suite.Equal(http.StatusPartialContent, response.GetStatus())
+	suite.Equal("bytes 3-7/25", resp.Header.Get("Content-Range"))
+	suite.Equal("5", resp.Header.Get("Content-Length"))
+	suite.Equal("bytes", resp.Header.Get("Accept-Ranges"))
+	suite.Equal("inline", resp.Header.Get("Content-Disposition"))
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	suite.Nil(err)
+	suite.Equal("utf-8", string(body))
+
+	// Download
+	rawRequest = httptest.NewRequest("GET", "/test-route", nil)
+	rawRequest.Header.Set("Range", "bytes=-7")
+	response = newResponse(httptest.NewRecorder(), rawRequest)
+	suite.Nil(response.Download("resources/test_file.txt", "file.txt"))
+	resp = response.responseWriter.(*httptest.ResponseRecorder).Result()
+
+	suite.Equal(http.StatusPartialContent, resp.StatusCode)
+	suite.Equal("bytes 18-24/25", resp.Header.Get("Content-Range"))
+	suite.Equal("attachment; filename=\"file.txt\"", resp.Header.Get("Content-Disposition"))
+	body, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	suite.Nil(err)
+	suite.Equal("content", string(body))
+
+	// Unsatisfiable range
+	rawRequest = httptest.NewRequest("GET", "/test-route", nil)
+	rawRequest.Header.Set("Range", "bytes=100-200")
+	response = newResponse(httptest.NewRecorder(), rawRequest)
+	suite.Nil(response.File("resources/test_file.txt"))
+	resp = response.responseWriter.(*httptest.ResponseRecorder).Result()
+
+	suite.Equal(http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+	suite.Equal("bytes */25", resp.Header.Get("Content-Range"))
+	resp.Body.Close()
 }
 
 func (suite *ResponseTestSuite) TestResponseDownload() {
@@ -304,6 +467,72 @@ func (suite *ResponseTestSuite) TestResponseCookie() {
 	resp.Body.Close()
 }
 
+func (suite *ResponseTestSuite) TestResponseSignedCookie() {
+	config.Set("app.key", "WxBpCcrFrC635jRiwNGKaIxTqJ0hB9T5XNPGSTxPLfM=")
+	defer config.Set("app.key", nil)
+
+	for _, encrypt := range []bool{false, true} {
+		rawRequest := httptest.NewRequest("GET", "/test-route", nil)
+		response := newResponse(httptest.NewRecorder(), rawRequest)
+		suite.Nil(response.SignedCookie(&http.Cookie{
+			Name:  "cookie-name",
+			Value: "user_id=1; admin",
+		}, encrypt))
+
+		resp := response.responseWriter.(*httptest.ResponseRecorder).Result()
+		resp.Body.Close()
+		cookies := resp.Cookies()
+		if !suite.Equal(1, len(cookies)) {
+			return
+		}
+		suite.Equal("cookie-name", cookies[0].Name)
+		suite.NotContains(cookies[0].Value, "user_id")
+
+		rawRequest = httptest.NewRequest("GET", "/test-route", nil)
+		rawRequest.AddCookie(cookies[0])
+		value, err := createTestRequest(rawRequest).SignedCookie("cookie-name", encrypt)
+		suite.Nil(err)
+		suite.Equal("user_id=1; admin", value)
+
+		// Tampered value
+		tampered := *cookies[0]
+		if tampered.Value[0] == 'A' {
+			tampered.Value = "B" + tampered.Value[1:]
+		} else {
+			tampered.Value = "A" + tampered.Value[1:]
+		}
+		rawRequest = httptest.NewRequest("GET", "/test-route", nil)
+		rawRequest.AddCookie(&tampered)
+		value, err = createTestRequest(rawRequest).SignedCookie("cookie-name", encrypt)
+		suite.Equal(encryption.ErrInvalidSignature, err)
+		suite.Empty(value)
+
+		// Signed value used for another cookie
+		tampered = *cookies[0]
+		tampered.Name = "other-cookie"
+		rawRequest = httptest.NewRequest("GET", "/test-route", nil)
+		rawRequest.AddCookie(&tampered)
+		value, err = createTestRequest(rawRequest).SignedCookie("other-cookie", encrypt)
+		suite.Equal(encryption.ErrInvalidSignature, err)
+		suite.Empty(value)
+	}
+
+	rawRequest := httptest.NewRequest("GET", "/test-route", nil)
+	rawRequest.AddCookie(&http.Cookie{Name: "unsigned", Value: "test"})
+	request := createTestRequest(rawRequest)
+	value, err := request.SignedCookie("unsigned", false)
+	suite.Equal(encryption.ErrInvalidSignature, err)
+	suite.Empty(value)
+
+	value, err = request.SignedCookie("missing", false)
+	suite.Equal(http.ErrNoCookie, err)
+	suite.Empty(value)
+
+	config.Set("app.key", nil)
+	response := newResponse(httptest.NewRecorder(), rawRequest)
+	suite.Equal(encryption.ErrNoKey, response.SignedCookie(&http.Cookie{Name: "cookie-name", Value: "test"}, false))
+}
+
 func (suite *ResponseTestSuite) TestResponseWrite() {
 	rawRequest := httptest.NewRequest("GET", "/test-route", strings.NewReader("body"))
 	response := newResponse(httptest.NewRecorder(), rawRequest)
@@ -471,6 +700,7 @@ func (suite *ResponseTestSuite) TestHead() {
 		body := suite.GetBody(resp)
 		suite.Equal(http.StatusOK, resp.StatusCode)
 		suite.Equal("text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+		suite.Equal("11", resp.Header.Get("Content-Length"))
 		suite.Empty(body)
 	})
 
@@ -652,6 +882,129 @@ func (suite *ResponseTestSuite) TestChainedWriter() {
 	suite.Equal("hello world", string(body))
 }
 
+func (suite *ResponseTestSuite) TestIsClientDisconnect() {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.True(IsClientDisconnect(ctx, http.ErrAbortHandler))
+	suite.True(IsClientDisconnect(ctx, &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)}))
+	suite.True(IsClientDisconnect(ctx, fmt.Errorf("wrapped: %w", syscall.ECONNRESET)))
+	suite.False(IsClientDisconnect(ctx, fmt.Errorf("random error")))
+	suite.False(IsClientDisconnect(ctx, context.DeadlineExceeded))
+
+	// Cancellation not coming from the client
+	suite.False(IsClientDisconnect(ctx, context.Canceled))
+	suite.False(IsClientDisconnect(nil, context.Canceled))
+
+	cancel()
+	suite.True(IsClientDisconnect(ctx, context.Canceled))
+	suite.True(IsClientDisconnect(ctx, fmt.Errorf("wrapped: %w", context.Canceled)))
+}
+
+func (suite *ResponseTestSuite) TestClientDisconnectError() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response := newResponse(httptest.NewRecorder(), httptest.NewRequest("GET", "/test-route", nil).WithContext(ctx))
+	suite.Nil(response.Error(context.Canceled))
+	suite.Equal(context.Canceled, response.GetError())
+	suite.Equal(0, response.GetStatus())
+	suite.True(response.IsEmpty())
+
+	request := suite.CreateTestRequest(nil)
+	suite.PanicsWithValue(http.ErrAbortHandler, func() {
+		suite.Middleware(recoveryMiddleware, request, func(response *Response, r *Request) {
+			panic(http.ErrAbortHandler)
+		})
+	})
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if suite.Len(logger.entries, 2) {
+		suite.Equal(logEntry{"debug", "GET /test-route client disconnected: context canceled"}, logger.entries[0])
+		suite.Equal(logEntry{"debug", "client disconnected: net/http: abort Handler"}, logger.entries[1])
+	}
+}
+
+func (suite *ResponseTestSuite) TestServerCancellationError() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	// The request context is not done: the cancellation comes from the server
+	response := newResponse(httptest.NewRecorder(), httptest.NewRequest("GET", "/test-route", nil))
+	suite.Nil(response.Error(context.Canceled))
+	suite.Equal(context.Canceled, response.GetError())
+	suite.Equal(http.StatusInternalServerError, response.GetStatus())
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if suite.NotEmpty(logger.entries) {
+		suite.Equal(logEntry{"error", "context canceled"}, logger.entries[0])
+	}
+}
+
+func (suite *ResponseTestSuite) TestClientDisconnect() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	var requestCtx context.Context
+	suite.RunServer(func(router *Router) {
+		router.Get("/stream", func(response *Response, r *Request) {
+			requestCtx = r.Request().Context()
+			chunk := bytes.Repeat([]byte("a"), 32*1024)
+			close(started)
+			for i := 0; i < 10000; i++ {
+				if _, err := response.Write(chunk); err != nil {
+					response.Error(err)
+					done <- err
+					return
+				}
+			}
+			done <- nil
+		})
+	}, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, "GET", BaseURL()+"/stream", nil)
+		if err != nil {
+			panic(err)
+		}
+		resp, err := suite.getHTTPClient().Do(req)
+		suite.Nil(err)
+		if err != nil {
+			cancel()
+			return
+		}
+		<-started
+		_, err = resp.Body.Read(make([]byte, 1024))
+		suite.Nil(err)
+		cancel()
+		resp.Body.Close()
+
+		select {
+		case err := <-done:
+			suite.True(IsClientDisconnect(requestCtx, err), err)
+		case <-time.After(5 * time.Second):
+			suite.Fail("Handler didn't stop after client disconnect")
+		}
+	})
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	debug := 0
+	for _, entry := range logger.entries {
+		suite.NotEqual("error", entry.level, entry.message)
+		if entry.level == "debug" && strings.HasPrefix(entry.message, "GET /stream client disconnected: ") {
+			debug++
+		}
+	}
+	suite.Equal(1, debug)
+}
+
 func TestResponseTestSuite(t *testing.T) {
 	RunTest(t, new(ResponseTestSuite))
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"goyave.dev/goyave/v3/validation"
 )
@@ -16,6 +17,9 @@ type Route struct {
 	parent          *Router
 	handler         Handler
 	validationRules *validation.Rules
+	paramsRules     *validation.Rules
+	mergedRules     *validation.Rules
+	mergedRulesOnce sync.Once
 	middlewareHolder
 	parameterizable
 }
@@ -30,13 +34,13 @@ func newRoute(handler Handler) *Route {
 	return &Route{
 		handler: handler,
 		middlewareHolder: middlewareHolder{
-			middleware: []Middleware{recoveryMiddleware, parseRequestMiddleware, languageMiddleware},
+			middleware: []Middleware{recoveryMiddleware, languageMiddleware, parseRequestMiddleware},
 		},
 	}
 }
 
 func (r *Route) match(req *http.Request, match *routeMatch) bool {
-	if params := r.parameterizable.regex.FindStringSubmatch(match.currentPath); params != nil {
+	if params := r.matchPath(match.currentPath); params != nil {
 		if r.checkMethod(req.Method) {
 			if len(params) > 1 {
 				match.mergeParams(r.makeParameters(params))
@@ -44,7 +48,11 @@ func (r *Route) match(req *http.Request, match *routeMatch) bool {
 			match.route = r
 			return true
 		}
+		if match.err != errMatchMethodNotAllowed {
+			match.router = r.parent
+		}
 		match.err = errMatchMethodNotAllowed
+		match.addAllowedMethods(r.methods)
 		return false
 	}
 
@@ -98,6 +106,23 @@ func (r *Route) Validate(validationRules validation.Ruler) *Route {
 	return r
 }
 
+// ValidateParams adds validation rules to the parameters of this route.
+// Parameters are validated like form fields, after they have been matched
+// using their pattern and before the request body is validated:
+//
+//  router.Get("/products/{id:[0-9]+}", product.Show).ValidateParams(validation.RuleSet{
+//      "id": {"required", "integer", "between:1,1000"},
+//  })
+//
+// If a parameter doesn't pass validation, the user receives an error explaining
+// what is wrong, with the status defined by the "server.validationErrorStatus" config entry.
+//
+// Returns itself.
+func (r *Route) ValidateParams(validationRules validation.Ruler) *Route {
+	r.paramsRules = validationRules.AsRules()
+	return r
+}
+
 // Middleware register middleware for this route only.
 //
 // Returns itself.
@@ -199,6 +224,44 @@ func (r *Route) GetValidationRules() *validation.Rules {
 	return r.validationRules
 }
 
+// getMergedValidationRules returns the validation rules of this route merged
+// with the validation rules of its parent routers. Rules defined at a deeper
+// level override the rules defined for the same field at a higher level.
+// The result is computed once, on the first request served by this route.
+func (r *Route) getMergedValidationRules() *validation.Rules {
+	r.mergedRulesOnce.Do(func() {
+		r.mergedRules = r.mergeValidationRules()
+	})
+	return r.mergedRules
+}
+
+func (r *Route) mergeValidationRules() *validation.Rules {
+	parents := make([]*validation.Rules, 0, 3)
+	for router := r.parent; router != nil; router = router.parent {
+		if router.validationRules != nil {
+			parents = append(parents, router.validationRules)
+		}
+	}
+	if len(parents) == 0 {
+		return r.validationRules
+	}
+
+	merged := &validation.Rules{Fields: validation.FieldMap{}}
+	for i := len(parents) - 1; i >= 0; i-- {
+		for name, field := range parents[i].Fields {
+			merged.Fields[name] = field
+		}
+		merged.StrictFields = merged.StrictFields || parents[i].StrictFields
+	}
+	if r.validationRules != nil {
+		for name, field := range r.validationRules.Fields {
+			merged.Fields[name] = field
+		}
+		merged.StrictFields = merged.StrictFields || r.validationRules.StrictFields
+	}
+	return merged.AsRules()
+}
+
 // GetFullURIAndParameters get the full uri and parameters for this route and all its parent routers.
 func (r *Route) GetFullURIAndParameters() (string, []string) {
 	router := r.parent
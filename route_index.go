@@ -0,0 +1,169 @@
+package goyave
+
+import "net/http"
+
+// routeIndex speeds up the matching of the routes and subrouters of a router.
+// Routes are bucketed by method, then stored in a prefix tree using the literal
+// prefix of their URI. Only the routes and subrouters whose literal prefix is
+// a prefix of the request path are candidates, so the regex is only evaluated
+// for the parameterized part of the few candidates left.
+//
+// Candidates are returned in the order of the routes and subrouters slices,
+// so the matching result is the same as testing them one by one.
+type routeIndex struct {
+	methods    map[string]*prefixTree
+	routes     *prefixTree // All routes, used to find the allowed methods
+	subrouters *prefixTree
+}
+
+// newRouteIndex builds the index of the routes and subrouters of the given router.
+func newRouteIndex(router *Router) *routeIndex {
+	index := &routeIndex{
+		methods:    make(map[string]*prefixTree, 8),
+		routes:     &prefixTree{},
+		subrouters: &prefixTree{},
+	}
+	for i, route := range router.routes {
+		index.routes.insert(route.literalPrefix, i)
+		for _, method := range route.methods {
+			tree, ok := index.methods[method]
+			if !ok {
+				tree = &prefixTree{}
+				index.methods[method] = tree
+			}
+			tree.insert(route.literalPrefix, i)
+		}
+	}
+	for i, subrouter := range router.subrouters {
+		index.subrouters.insert(subrouter.literalPrefix, i)
+	}
+	return index
+}
+
+// getIndex returns the index of this router, building it if the
+// routes or subrouters changed since the last request.
+func (r *Router) getIndex() *routeIndex {
+	index, _ := r.index.Load().(*routeIndex)
+	if index == nil {
+		index = newRouteIndex(r)
+		r.index.Store(index)
+	}
+	return index
+}
+
+// resetIndex discards the index of this router. Must be called every
+// time the routes or subrouters of this router are changed.
+func (r *Router) resetIndex() {
+	r.index.Store((*routeIndex)(nil))
+}
+
+// matchRoutes checks if one of the routes of this router matches
+// the given request. If none of them accepts the request's method,
+// the routes matching the path are tested so the method not allowed
+// error and the allowed methods are set.
+func (r *Router) matchRoutes(index *routeIndex, req *http.Request, match *routeMatch) bool {
+	var buf [8]int
+	if tree, ok := index.methods[req.Method]; ok {
+		for _, i := range tree.collect(match.currentPath, buf[:0]) {
+			if r.routes[i].match(req, match) {
+				return true
+			}
+		}
+	}
+	for _, i := range index.routes.collect(match.currentPath, buf[:0]) {
+		if r.routes[i].match(req, match) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixTree a radix tree of literal URI prefixes. Each node holds the
+// positions of the routes or subrouters whose literal prefix ends on it.
+type prefixTree struct {
+	prefix   string
+	values   []int
+	children []*prefixTree
+}
+
+// insert the given value using the given key. The values are expected
+// to be inserted in ascending order.
+func (t *prefixTree) insert(key string, value int) {
+	node := t
+	for key != "" {
+		var child *prefixTree
+		for _, c := range node.children {
+			if c.prefix[0] == key[0] {
+				child = c
+				break
+			}
+		}
+		if child == nil {
+			node.children = append(node.children, &prefixTree{prefix: key, values: []int{value}})
+			return
+		}
+
+		length := commonPrefixLength(child.prefix, key)
+		if length < len(child.prefix) {
+			// Split the child so the common part becomes its own node
+			child.children = []*prefixTree{{
+				prefix:   child.prefix[length:],
+				values:   child.values,
+				children: child.children,
+			}}
+			child.prefix = child.prefix[:length]
+			child.values = nil
+		}
+		node = child
+		key = key[length:]
+	}
+	node.values = append(node.values, value)
+}
+
+// collect appends to dst the values of all the keys that are a prefix
+// of the given path, in ascending order, and returns the resulting slice.
+func (t *prefixTree) collect(path string, dst []int) []int {
+	node := t
+	for {
+		dst = mergeSorted(dst, node.values)
+		if path == "" {
+			return dst
+		}
+		var next *prefixTree
+		for _, c := range node.children {
+			if c.prefix[0] == path[0] {
+				next = c
+				break
+			}
+		}
+		if next == nil || len(path) < len(next.prefix) || path[:len(next.prefix)] != next.prefix {
+			return dst
+		}
+		path = path[len(next.prefix):]
+		node = next
+	}
+}
+
+// mergeSorted inserts the given ascending values into the
+// ascending dst slice, keeping it sorted.
+func mergeSorted(dst []int, values []int) []int {
+	for _, v := range values {
+		dst = append(dst, v)
+		i := len(dst) - 1
+		for ; i > 0 && dst[i-1] > v; i-- {
+			dst[i] = dst[i-1]
+		}
+		dst[i] = v
+	}
+	return dst
+}
+
+// commonPrefixLength returns the length of the longest common prefix
+// of the two given strings.
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
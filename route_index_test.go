@@ -0,0 +1,181 @@
+package goyave
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"goyave.dev/goyave/v3/cors"
+)
+
+type RouteIndexTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RouteIndexTestSuite) TestPrefixTree() {
+	tree := &prefixTree{}
+	keys := []string{"/product", "/product/", "", "/products/", "/p", "/product/", "/user/", "/product/export"}
+	for i, key := range keys {
+		tree.insert(key, i)
+	}
+
+	suite.Equal([]int{2}, tree.collect("", nil))
+	suite.Equal([]int{2}, tree.collect("/", nil))
+	suite.Equal([]int{2, 4}, tree.collect("/p", nil))
+	suite.Equal([]int{0, 2, 4}, tree.collect("/product", nil))
+	suite.Equal([]int{0, 1, 2, 4, 5}, tree.collect("/product/42", nil))
+	suite.Equal([]int{0, 1, 2, 4, 5, 7}, tree.collect("/product/export.csv", nil))
+	suite.Equal([]int{0, 2, 3, 4}, tree.collect("/products/42", nil))
+	suite.Equal([]int{2, 6}, tree.collect("/user/1", nil))
+	suite.Equal([]int{2}, tree.collect("/users", nil))
+
+	var buf [2]int
+	suite.Equal([]int{0, 1, 2, 4, 5}, tree.collect("/product/42", buf[:0]))
+}
+
+// linearMatch is the reference matching algorithm: the subrouters and
+// routes are tested one by one, without index.
+func linearMatch(r *Router, req *http.Request, match *routeMatch) bool {
+	var params []string
+	if r.parameterizable.regex != nil {
+		params = r.regex.FindStringSubmatch(match.currentPath)
+	} else {
+		params = []string{""}
+	}
+
+	if params != nil {
+		if r.prefix != "" && match.err != errMatchMethodNotAllowed && (match.router == nil || r.depth() > match.router.depth()) {
+			match.router = r
+		}
+		match.trimCurrentPath(params[0])
+		if len(params) > 1 {
+			match.mergeParams(r.makeParameters(params))
+		}
+
+		for _, router := range r.subrouters {
+			if linearMatch(router, req, match) {
+				if router.prefix == "" && match.isMethodNotAllowed() {
+					break
+				}
+				return true
+			}
+		}
+
+		for _, route := range r.routes {
+			if route.match(req, match) {
+				match.corsOptions = r.corsOptions
+				return true
+			}
+		}
+	}
+
+	match.corsOptions = r.corsOptions
+	if match.err == errMatchMethodNotAllowed {
+		match.route = methodNotAllowedRoute
+		if req.Method == http.MethodOptions {
+			match.route = optionsRoute
+		}
+		return true
+	}
+
+	match.route = notFoundRoute
+	return false
+}
+
+func (suite *RouteIndexTestSuite) TestMatchIsLinear() {
+	router := registerLargeRouter()
+	registerRouter(router, sampleRouteDefinition)
+
+	api := router.Subrouter("/api")
+	api.CORS(cors.Default())
+	api.Get("/", handler)
+	api.Get("/users", handler)
+	api.Post("/users", handler)
+	api.Get("/users/{id:[0-9]+}", handler)
+	api.Route("PUT|PATCH", "/users/{id:[0-9]+}", handler)
+	api.Get("/users/{id:[0-9]+}/export.{format:csv|json}", handler)
+	api.Get("/users/export", handler)
+	api.Get("/{resource}/{id}", handler)
+	api.Delete("/{resource}/{id}", handler)
+	api.Override("DELETE", "/users/{id:[0-9]+}", handler)
+	api.Get("/files/{path:.*}", handler)
+
+	group := api.Group()
+	group.Post("/users/{id:[0-9]+}/avatar", handler)
+	group.Get("/group-only", handler)
+
+	module := NewRouter()
+	module.Get("/", handler)
+	module.Get("/{id}", handler)
+	module.Subrouter("/nested").Post("/items", handler)
+	router.Merge("/module", module)
+
+	router.Subrouter("/{tenant:[a-z]+}").Get("/dashboard", handler)
+
+	methods := []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	paths := []string{
+		"", "/", "/hello", "/world", "/param", "/unknown",
+		"/product", "/product/", "/product/1", "/product/test", "/products",
+		"/resource0", "/resource0/", "/resource42/search", "/resource99/export.csv",
+		"/resource99/exportXcsv", "/resource99/42", "/resource99/42/comments/7",
+		"/resource99/abc/details", "/resource9/42", "/resource999/42",
+		"/flat/route999/42", "/flat/route99/42", "/flat/route1/abc", "/flat/unknown",
+		"/api", "/api/", "/api/users", "/api/users/", "/api/users/42",
+		"/api/users/42/export.csv", "/api/users/42/export.xml", "/api/users/export",
+		"/api/users/42/avatar", "/api/group-only", "/api/posts/42", "/api/files/a/b.txt",
+		"/module", "/module/", "/module/42", "/module/nested/items", "/module/nested",
+		"/acme/dashboard", "/acme/other", "/ACME/dashboard",
+	}
+
+	for _, method := range methods {
+		for _, path := range paths {
+			req := httptest.NewRequest(method, "http://example.org/", nil)
+			req.URL.Path = path
+
+			expected := &routeMatch{currentPath: path}
+			expectedResult := linearMatch(router, req, expected)
+			actual := &routeMatch{currentPath: path}
+			actualResult := router.match(req, actual)
+
+			msg := method + " " + path
+			suite.Equal(expectedResult, actualResult, msg)
+			suite.Same(expected.route, actual.route, msg)
+			suite.Same(expected.corsOptions, actual.corsOptions, msg)
+			suite.Equal(expected.parameters, actual.parameters, msg)
+			if expected.route == notFoundRoute || expected.isMethodNotAllowed() {
+				// The router and allowed methods are only used if no route matched
+				suite.Same(expected.router, actual.router, msg)
+				suite.Equal(expected.allowedMethods, actual.allowedMethods, msg)
+			}
+		}
+	}
+}
+
+func (suite *RouteIndexTestSuite) TestResetIndex() {
+	router := NewRouter()
+	router.Get("/users", handler)
+	match := &routeMatch{currentPath: "/posts"}
+	suite.False(router.match(httptest.NewRequest(http.MethodGet, "/posts", nil), match))
+	suite.NotNil(router.index.Load())
+
+	route := router.Get("/posts", handler)
+	match = &routeMatch{currentPath: "/posts"}
+	suite.True(router.match(httptest.NewRequest(http.MethodGet, "/posts", nil), match))
+	suite.Same(route, match.route)
+
+	router.Override("GET", "/posts", handler)
+	match = &routeMatch{currentPath: "/posts"}
+	suite.True(router.match(httptest.NewRequest(http.MethodGet, "/posts", nil), match))
+	suite.NotSame(route, match.route)
+
+	subrouter := router.Subrouter("/admin")
+	route = subrouter.Get("/", handler)
+	match = &routeMatch{currentPath: "/admin"}
+	suite.True(router.match(httptest.NewRequest(http.MethodGet, "/admin", nil), match))
+	suite.Same(route, match.route)
+}
+
+func TestRouteIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(RouteIndexTestSuite))
+}
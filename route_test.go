@@ -229,6 +229,18 @@ func (suite *RouteTestSuite) TestValidate() {
 	suite.Equal(rules, route.validationRules)
 }
 
+func (suite *RouteTestSuite) TestValidateParams() {
+	route := &Route{
+		name:    "route-name",
+		uri:     "/product/{id}",
+		methods: []string{"GET", "POST"},
+	}
+	rules := validation.RuleSet{"id": {"required", "integer"}}
+	suite.Equal(route, route.ValidateParams(rules))
+	suite.Equal(rules.AsRules(), route.paramsRules)
+	suite.Nil(route.validationRules)
+}
+
 func (suite *RouteTestSuite) TestMiddleware() {
 	route := &Route{
 		name:    "route-name",
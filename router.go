@@ -1,14 +1,27 @@
 package goyave
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"html"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
+	"goyave.dev/goyave/v3/clock"
+	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/cors"
+	"goyave.dev/goyave/v3/helper"
 	"goyave.dev/goyave/v3/helper/filesystem"
+	"goyave.dev/goyave/v3/lang"
+	"goyave.dev/goyave/v3/validation"
 )
 
 type routeMatcher interface {
@@ -21,7 +34,12 @@ type Router struct {
 	corsOptions    *cors.Options
 	statusHandlers map[int]Handler
 	namedRoutes    map[string]*Route
-	regexCache     map[string]*regexp.Regexp
+
+	notFoundHandler         Handler
+	methodNotAllowedHandler Handler
+	regexCache              map[string]*regexp.Regexp
+
+	validationRules *validation.Rules
 
 	parameterizable
 	middlewareHolder
@@ -29,6 +47,7 @@ type Router struct {
 	prefix            string
 	routes            []*Route
 	subrouters        []*Router
+	index             atomic.Value // *routeIndex, see "getIndex()"
 	hasCORSMiddleware bool
 }
 
@@ -43,11 +62,18 @@ type middlewareHolder struct {
 }
 
 type routeMatch struct {
-	route       *Route
-	corsOptions *cors.Options
-	parameters  map[string]string
-	err         error
-	currentPath string
+	route          *Route
+	corsOptions    *cors.Options
+	parameters     map[string]string
+	err            error
+	currentPath    string
+	allowedMethods []string
+
+	// router the deepest router with a non-empty prefix matching the
+	// request path, or the router of the first route matching the path
+	// if the method is not allowed. Used to find the not found and
+	// method not allowed handlers.
+	router *Router
 }
 
 var (
@@ -57,11 +83,92 @@ var (
 	methodNotAllowedRoute = newRoute(func(response *Response, request *Request) {
 		response.Status(http.StatusMethodNotAllowed)
 	})
+	optionsRoute = newRoute(func(response *Response, request *Request) {
+		response.Status(http.StatusNoContent)
+	})
 	notFoundRoute = newRoute(func(response *Response, request *Request) {
+		if notFoundHandler != nil {
+			notFoundHandler(response, request)
+			return
+		}
 		response.Status(http.StatusNotFound)
 	})
+
+	notFoundHandler Handler
+	errorHandler    = DefaultErrorHandler
 )
 
+// SetNotFoundHandler replace the handler executed when no route matches
+// the request. The handler is executed after the core middleware.
+// If the handler doesn't write anything to the response, the regular status
+// handlers are executed. Passing nil restores the default behavior.
+// Handlers defined on routers with "Router.SetNotFoundHandler()" take precedence.
+func SetNotFoundHandler(handler Handler) {
+	notFoundHandler = handler
+}
+
+// SetErrorHandler replace the function rendering the body of error responses
+// ("404 Not Found", "405 Method Not Allowed", "5xx", etc). This function is called
+// by the "ErrorStatusHandler" and "PanicStatusHandler" status handlers, so it can be
+// used to return a consistent error format across the whole application.
+// Passing nil restores "DefaultErrorHandler".
+func SetErrorHandler(handler func(*Response, *Request, int)) {
+	if handler == nil {
+		handler = DefaultErrorHandler
+	}
+	errorHandler = handler
+}
+
+// DefaultErrorHandler renders the given error status.
+// Clients accepting HTML in priority get a simple HTML page, the others get
+// the standard error envelope (see "Response.ErrorMessage()"):
+//  {"error": {"code": 404, "message": "Not Found"}}
+//
+// The message is localized using the "http.<code>" language line
+// (for example "http.404") of the request's language, or of the default
+// language if the line is not translated. If the line is not defined at all,
+// the status text is used.
+func DefaultErrorHandler(response *Response, request *Request, status int) {
+	message := statusMessage(request, status)
+	if acceptsHTML(request) {
+		response.Header().Set("Content-Type", "text/html; charset=utf-8")
+		title := strconv.Itoa(status) + " " + html.EscapeString(message)
+		response.String(status, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>"+title+"</title></head>\n<body><h1>"+title+"</h1></body>\n</html>\n")
+		return
+	}
+	response.ErrorMessage(status, message)
+}
+
+// statusMessage returns the localized message of the given status.
+func statusMessage(request *Request, status int) string {
+	line := "http." + strconv.Itoa(status)
+	if message := localize(request, line); message != line {
+		return message
+	}
+	return http.StatusText(status)
+}
+
+// localize returns the given language line in the request's language,
+// or in the default language if it is not translated.
+// Returns the line name if it doesn't exist in any of these languages.
+func localize(request *Request, line string, placeholders ...string) string {
+	if message := lang.Get(request.Lang, line, placeholders...); message != line {
+		return message
+	}
+	if !config.IsLoaded() {
+		return line
+	}
+	return lang.Get(config.GetString("app.defaultLanguage"), line, placeholders...)
+}
+
+// acceptsHTML returns true if the media type the client prefers is HTML,
+// according to the "Accept" header. JSON is preferred if the client
+// accepts both with the same priority, or if the header is missing.
+func acceptsHTML(request *Request) bool {
+	accepted := request.Accepts("application/json", "text/html", "application/xhtml+xml")
+	return accepted == "text/html" || accepted == "application/xhtml+xml"
+}
+
 func init() {
 	methodNotAllowedRoute.name = "method-not-allowed"
 }
@@ -69,25 +176,35 @@ func init() {
 // PanicStatusHandler for the HTTP 500 error.
 // If debugging is enabled, writes the error details to the response and
 // print stacktrace in the console.
-// If debugging is not enabled, writes `{"error": "Internal Server Error"}`
-// to the response.
+// If debugging is not enabled, renders the error using the function
+// defined with "SetErrorHandler()".
 func PanicStatusHandler(response *Response, request *Request) {
 	response.error(response.GetError())
 	if response.empty {
-		message := map[string]string{
-			"error": http.StatusText(response.GetStatus()),
-		}
-		response.JSON(response.GetStatus(), message)
+		errorHandler(response, request, response.GetStatus())
 	}
 }
 
 // ErrorStatusHandler a generic status handler for non-success codes.
-// Writes the corresponding status message to the response.
+// Writes the corresponding status message to the response, using the
+// function defined with "SetErrorHandler()".
 func ErrorStatusHandler(response *Response, request *Request) {
-	message := map[string]string{
-		"error": http.StatusText(response.GetStatus()),
-	}
-	response.JSON(response.GetStatus(), message)
+	errorHandler(response, request, response.GetStatus())
+}
+
+// PayloadTooLargeStatusHandler for HTTP 413 errors.
+// Writes a localized message explaining the request body exceeds
+// the "server.maxUploadSize" limit.
+func PayloadTooLargeStatusHandler(response *Response, request *Request) {
+	message := localize(request, "payload-too-large", ":max", strconv.FormatFloat(config.GetFloat("server.maxUploadSize"), 'f', -1, 64))
+	response.ErrorMessage(response.GetStatus(), message)
+}
+
+// GatewayTimeoutStatusHandler for HTTP 504 errors.
+// Writes a localized message explaining the request took too long
+// to be processed.
+func GatewayTimeoutStatusHandler(response *Response, request *Request) {
+	response.ErrorMessage(response.GetStatus(), localize(request, "request-timeout"))
 }
 
 // ValidationStatusHandler for HTTP 400 and HTTP 422 errors.
@@ -98,7 +215,7 @@ func ValidationStatusHandler(response *Response, request *Request) {
 }
 
 // NewRouter create a new root-level Router that is pre-configured with core
-// middleware (recovery, parse and language), as well as status handlers
+// middleware (recovery, language and parse), as well as status handlers
 // for all standard HTTP status codes.
 //
 // You don't need to manually build your router using this function
@@ -122,12 +239,14 @@ func NewRouter() *Router {
 	for i := 401; i <= 418; i++ {
 		router.StatusHandler(ErrorStatusHandler, i)
 	}
+	router.StatusHandler(PayloadTooLargeStatusHandler, http.StatusRequestEntityTooLarge)
 	for i := 423; i <= 426; i++ {
 		router.StatusHandler(ErrorStatusHandler, i)
 	}
 	router.StatusHandler(ErrorStatusHandler, 421, 428, 429, 431, 444, 451)
-	router.StatusHandler(ErrorStatusHandler, 501, 502, 503, 504, 505, 506, 507, 508, 510, 511)
-	router.Middleware(recoveryMiddleware, parseRequestMiddleware, languageMiddleware)
+	router.StatusHandler(ErrorStatusHandler, 501, 502, 503, 505, 506, 507, 508, 510, 511)
+	router.StatusHandler(GatewayTimeoutStatusHandler, http.StatusGatewayTimeout)
+	router.Middleware(recoveryMiddleware, languageMiddleware, parseRequestMiddleware)
 	return router
 }
 
@@ -178,21 +297,27 @@ func (r *Router) match(req *http.Request, match *routeMatch) bool {
 	// Check if router itself matches
 	var params []string
 	if r.parameterizable.regex != nil {
-		params = r.parameterizable.regex.FindStringSubmatch(match.currentPath)
+		params = r.matchPath(match.currentPath)
 	} else {
 		params = []string{""}
 	}
 
 	if params != nil {
+		if r.prefix != "" && match.err != errMatchMethodNotAllowed && (match.router == nil || r.depth() > match.router.depth()) {
+			match.router = r
+		}
 		match.trimCurrentPath(params[0])
 		if len(params) > 1 {
 			match.mergeParams(r.makeParameters(params))
 		}
 
 		// Check in subrouters first
-		for _, router := range r.subrouters {
+		index := r.getIndex()
+		var buf [8]int
+		for _, i := range index.subrouters.collect(match.currentPath, buf[:0]) {
+			router := r.subrouters[i]
 			if router.match(req, match) {
-				if router.prefix == "" && match.route == methodNotAllowedRoute {
+				if router.prefix == "" && match.isMethodNotAllowed() {
 					// This allows route groups with subrouters having empty prefix.
 					break
 				}
@@ -201,17 +326,20 @@ func (r *Router) match(req *http.Request, match *routeMatch) bool {
 		}
 
 		// Check if any route matches
-		for _, route := range r.routes {
-			if route.match(req, match) {
-				match.corsOptions = r.corsOptions
-				return true
-			}
+		if r.matchRoutes(index, req, match) {
+			match.corsOptions = r.corsOptions
+			return true
 		}
 	}
 
 	match.corsOptions = r.corsOptions
 	if match.err == errMatchMethodNotAllowed {
 		match.route = methodNotAllowedRoute
+		if req.Method == http.MethodOptions {
+			// Automatic response to OPTIONS requests for paths
+			// without explicit OPTIONS route.
+			match.route = optionsRoute
+		}
 		return true
 	}
 
@@ -246,9 +374,116 @@ func (r *Router) Subrouter(prefix string) *Router {
 	}
 	router.compileParameters(router.prefix, false, r.regexCache)
 	r.subrouters = append(r.subrouters, router)
+	r.resetIndex()
 	return router
 }
 
+// Merge mounts the given router, built independently (for example by a
+// reusable module), as a subrouter of this router with the given prefix.
+// Returns the created subrouter.
+//
+// The routes, subrouters, middleware, CORS options and validation rules of
+// the given router are grafted into the new subrouter, so its routes are
+// matched under the prefix. The core middleware added by "NewRouter()" are not
+// duplicated and the status handlers of the given router are ignored: the ones
+// of this router apply.
+//
+// Named routes are namespaced using the prefix: a route named "users.index"
+// merged with the "/admin" prefix is named "admin.users.index". Prefix segments
+// are separated by dots and route parameters are kept as is.
+// Panics if a namespaced name collides with an existing named route.
+//
+// The given router must not be used anymore after being merged.
+func (r *Router) Merge(prefix string, other *Router) *Router {
+	router := r.Subrouter(prefix)
+	namespace := strings.ReplaceAll(strings.Trim(router.prefix, "/"), "/", ".")
+
+	for _, m := range other.middleware {
+		if isCoreMiddleware(m) || (router.hasCORSMiddleware && isSameMiddleware(m, corsMiddleware)) {
+			continue
+		}
+		router.Middleware(m)
+	}
+	if other.corsOptions != nil {
+		router.corsOptions = other.corsOptions
+		router.hasCORSMiddleware = true
+	}
+	router.validationRules = other.validationRules
+	router.notFoundHandler = other.notFoundHandler
+	router.methodNotAllowedHandler = other.methodNotAllowedHandler
+
+	for _, route := range other.routes {
+		route.parent = router
+		router.routes = append(router.routes, route)
+	}
+	for _, subrouter := range other.subrouters {
+		subrouter.parent = router
+		router.subrouters = append(router.subrouters, subrouter)
+	}
+	router.resetIndex()
+	router.adopt(other.namedRoutes, namespace)
+	return router
+}
+
+// adopt the routes of this router and its subrouters after a merge: named routes
+// from the given map are namespaced and moved to the named routes of this router,
+// and the CORS options are propagated. Routes with the "/" URI are updated the same
+// way as in "registerRoute()" so they match the prefix without trailing slash.
+func (r *Router) adopt(namedRoutes map[string]*Route, namespace string) {
+	defer r.resetIndex()
+	for _, route := range r.routes {
+		if route.uri == "/" && r.hasPrefix() {
+			route.uri = ""
+			regexCache := r.regexCache
+			if regexCache == nil {
+				regexCache = make(map[string]*regexp.Regexp, 1)
+			}
+			route.compileParameters(route.uri, true, regexCache)
+		}
+		if r.corsOptions != nil && !route.checkMethod(http.MethodOptions) {
+			route.methods = append(route.methods, http.MethodOptions)
+		}
+		if route.name == "" || namedRoutes[route.name] != route {
+			continue
+		}
+		if namespace != "" {
+			route.name = namespace + "." + route.name
+		}
+		if _, ok := r.namedRoutes[route.name]; ok {
+			panic(fmt.Errorf("Route %q already exists", route.name))
+		}
+		r.namedRoutes[route.name] = route
+	}
+	for _, subrouter := range r.subrouters {
+		subrouter.namedRoutes = r.namedRoutes
+		if subrouter.corsOptions == nil {
+			subrouter.corsOptions = r.corsOptions
+			subrouter.hasCORSMiddleware = r.hasCORSMiddleware
+		}
+		subrouter.adopt(namedRoutes, namespace)
+	}
+}
+
+// hasPrefix returns true if this router or one of its parents has a non-empty prefix.
+func (r *Router) hasPrefix() bool {
+	for router := r; router != nil; router = router.parent {
+		if router.prefix != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isCoreMiddleware(middleware Middleware) bool {
+	return isSameMiddleware(middleware, recoveryMiddleware) ||
+		isSameMiddleware(middleware, languageMiddleware) ||
+		isSameMiddleware(middleware, parseRequestMiddleware)
+}
+
+func isSameMiddleware(m1 Middleware, m2 Middleware) bool {
+	return reflect.ValueOf(m1).Pointer() == reflect.ValueOf(m2).Pointer()
+}
+
 // Group create a new sub-router with an empty prefix.
 func (r *Router) Group() *Router {
 	return r.Subrouter("")
@@ -262,6 +497,17 @@ func (r *Router) Middleware(middleware ...Middleware) {
 	r.middleware = append(r.middleware, middleware...)
 }
 
+// Validate adds validation rules applied to every route of this router
+// and its subrouters. These rules are merged with the route's own rules
+// before validation: fields are united and, if a field is defined at multiple
+// levels, the definition of the deepest level (the route being the deepest) is used.
+//
+// The rules are merged when a route serves its first request, so they must be
+// defined before the server starts.
+func (r *Router) Validate(validationRules validation.Ruler) {
+	r.validationRules = validationRules.AsRules()
+}
+
 // Route register a new route.
 //
 // Multiple methods can be passed using a pipe-separated string.
@@ -276,12 +522,130 @@ func (r *Router) Middleware(middleware ...Middleware) {
 // If the router has CORS options set, the "OPTIONS" method is automatically added
 // to the matcher if it's missing, so it allows preflight requests.
 //
+// OPTIONS requests to a path having routes but no OPTIONS route are answered
+// automatically with "204 No Content" and an "Allow" header listing the methods
+// available for this path. The same header is set on "405 Method Not Allowed" responses.
+//
+// Routes of the same router are matched by specificity rather than by
+// registration order. URIs are compared segment by segment, from left to right,
+// and the first segment that differs decides which route is tried first:
+//  1. static segments: "/users/me"
+//  2. segments with a regex-constrained parameter: "/users/{id:[0-9]+}"
+//  3. segments with an open parameter: "/users/{name}"
+//  4. wildcards, parameters able to match a slash: "/users/{path:.*}"
+// Routes with the same specificity are matched in registration order.
+// Subrouters are still matched before the routes of their parent.
+//
 // Returns the generated route.
 func (r *Router) Route(methods string, uri string, handler Handler) *Route {
 	return r.registerRoute(methods, uri, handler)
 }
 
+// Override registers a new route for the given method(s) and URI, replacing
+// the routes which would conflict with it: routes of the whole router tree having
+// the same compiled full URI pattern and at least one method in common.
+//
+// Unlike "Route()", which panics when a route conflicts with a previously
+// registered one, this method is meant for intentional replacements, for example
+// to replace a route registered by a third-party package. The methods of the
+// replaced routes which are not overridden are kept.
+//
+//  router.Override("GET", "/users", customUserIndex)
+func (r *Router) Override(methods string, uri string, handler Handler) *Route {
+	route, _ := r.newRoute(methods, uri, handler)
+	r.removeConflicts(route)
+	r.insertRoute(route)
+	return route
+}
+
+// registerRoute registers a new route. Panics if the route conflicts with
+// a previously registered route of the router tree (this router, its parents,
+// its subrouters and their subrouters), such as the same route registered
+// in two route groups.
 func (r *Router) registerRoute(methods string, uri string, handler Handler) *Route {
+	route, requested := r.newRoute(methods, uri, handler)
+	r.root().forEachConflict(route.fullPattern(), "", func(_ *Router, existing *Route) {
+		for _, m := range requested {
+			if existing.checkMethod(m) {
+				panic(fmt.Errorf("Route %s %q conflicts with the route %s %q registered before. Use \"Router.Override()\" to replace it intentionally", methods, route.GetFullURI(), strings.Join(existing.methods, "|"), existing.GetFullURI()))
+			}
+		}
+	})
+	r.insertRoute(route)
+	return route
+}
+
+// root returns the main router of the tree this router belongs to.
+func (r *Router) root() *Router {
+	root := r
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// fullPattern returns the compiled pattern of the prefixes of this router
+// and its parents, without the leading "^".
+func (r *Router) fullPattern() string {
+	pattern := ""
+	for router := r; router != nil; router = router.parent {
+		if router.regex != nil {
+			pattern = router.regex.String()[1:] + pattern
+		}
+	}
+	return pattern
+}
+
+// fullPattern returns the compiled pattern of the full URI of this route,
+// without the leading "^". Routes having the same full pattern match
+// the same paths, whatever the router they belong to.
+func (r *Route) fullPattern() string {
+	return r.parent.fullPattern() + r.regex.String()[1:]
+}
+
+// forEachConflict calls the given function for each route of this router and its
+// subrouters having the given full pattern. "prefix" is the full pattern of the
+// parent of this router. Subrouters whose prefix doesn't match are skipped.
+func (r *Router) forEachConflict(pattern string, prefix string, fn func(*Router, *Route)) {
+	if r.regex != nil {
+		prefix += r.regex.String()[1:]
+	}
+	if !strings.HasPrefix(pattern, prefix) {
+		return
+	}
+	rest := pattern[len(prefix):]
+	for _, route := range r.routes {
+		if route.regex.String()[1:] == rest {
+			fn(r, route)
+		}
+	}
+	for _, subrouter := range r.subrouters {
+		subrouter.forEachConflict(pattern, prefix, fn)
+	}
+}
+
+// insertRoute adds the given route to this router, before the first route
+// it is more specific than. Routes with the same specificity keep their
+// registration order.
+func (r *Router) insertRoute(route *Route) {
+	i := len(r.routes)
+	for j, existing := range r.routes {
+		if route.isMoreSpecific(&existing.parameterizable) {
+			i = j
+			break
+		}
+	}
+	r.routes = append(r.routes, nil)
+	copy(r.routes[i+1:], r.routes[i:])
+	r.routes[i] = route
+	r.resetIndex()
+}
+
+// newRoute creates a new route for this router, adding the implicit
+// methods (HEAD for GET routes, OPTIONS if CORS is enabled). The methods
+// requested by the user are returned as well.
+func (r *Router) newRoute(methods string, uri string, handler Handler) (*Route, []string) {
+	requested := strings.Split(methods, "|")
 	if r.corsOptions != nil && !strings.Contains(methods, "OPTIONS") {
 		methods += "|OPTIONS"
 	}
@@ -302,8 +666,52 @@ func (r *Router) registerRoute(methods string, uri string, handler Handler) *Rou
 		handler: handler,
 	}
 	route.compileParameters(route.uri, true, r.regexCache)
-	r.routes = append(r.routes, route)
-	return route
+	return route, requested
+}
+
+// removeConflicts removes the methods of the given route from the routes of
+// the router tree having the same compiled full URI pattern. Routes left
+// without methods are removed.
+func (r *Router) removeConflicts(route *Route) {
+	conflicts := map[*Router]map[*Route]bool{}
+	r.root().forEachConflict(route.fullPattern(), "", func(router *Router, existing *Route) {
+		if conflicts[router] == nil {
+			conflicts[router] = map[*Route]bool{}
+		}
+		conflicts[router][existing] = true
+	})
+	for router, routes := range conflicts {
+		router.removeMethods(route, routes)
+	}
+}
+
+// removeMethods removes the methods of the given route from the given
+// routes of this router. Routes left without methods are removed.
+func (r *Router) removeMethods(route *Route, conflicts map[*Route]bool) {
+	routes := r.routes[:0]
+	for _, existing := range r.routes {
+		if conflicts[existing] {
+			methods := make([]string, 0, len(existing.methods))
+			for _, m := range existing.methods {
+				if !route.checkMethod(m) {
+					methods = append(methods, m)
+				}
+			}
+			existing.methods = methods
+			if len(methods) == 0 {
+				if existing.name != "" && r.namedRoutes[existing.name] == existing {
+					delete(r.namedRoutes, existing.name)
+				}
+				continue
+			}
+		}
+		routes = append(routes, existing)
+	}
+	for i := len(routes); i < len(r.routes); i++ {
+		r.routes[i] = nil
+	}
+	r.routes = routes
+	r.resetIndex()
 }
 
 // Get registers a new route with the GET and HEAD methods.
@@ -347,11 +755,37 @@ func (r *Router) GetRoute(name string) *Route {
 // instead of an inline element.
 //
 // If no file is given in the url, or if the given file is a directory, the handler will
-// send the "index.html" file if it exists.
+// send the "index.html" file if it exists. If the file doesn't exist, the not found handler
+// of this router is executed (see "SetNotFoundHandler()").
+//
+// Pre-compressed files are served to the clients accepting their encoding: if
+// "script.js" is requested and the client accepts gzip, "script.js.gz" is sent
+// with the "Content-Encoding: gzip" header and the content type of "script.js".
+// Brotli files (".br") are supported too and preferred over gzip. The plain file
+// must exist as well and is sent to the other clients. If the "Gzip" middleware
+// is used, the plain file is compressed by the middleware instead.
 func (r *Router) Static(uri string, directory string, download bool, middleware ...Middleware) {
 	r.registerRoute(http.MethodGet, uri+"{resource:.*}", staticHandler(directory, download)).Middleware(middleware...)
 }
 
+// StaticFS serve a directory and its subdirectories of static resources from
+// the given file system, such as an "embed.FS". This works like "Static": set
+// the "download" parameter to true if you want the files to be sent as an
+// attachment instead of an inline element, and the "index.html" file is sent
+// if no file is given in the url or if the given file is a directory.
+//
+// Paths are relative to the root of the file system. Use "fs.Sub()" to serve
+// a sub-directory:
+//
+//  //go:embed dist
+//  var dist embed.FS
+//
+//  assets, _ := fs.Sub(dist, "dist")
+//  router.StaticFS("/", assets, false)
+func (r *Router) StaticFS(uri string, fsys fs.FS, download bool, middleware ...Middleware) {
+	r.registerRoute(http.MethodGet, uri+"{resource:.*}", staticFSHandler(fsys, download)).Middleware(middleware...)
+}
+
 // CORS set the CORS options for this route group.
 // If the options are not nil, the CORS middleware is automatically added.
 func (r *Router) CORS(options *cors.Options) {
@@ -362,6 +796,46 @@ func (r *Router) CORS(options *cors.Options) {
 	}
 }
 
+// SetNotFoundHandler replace the handler executed when no route matches
+// a request whose path starts with the prefix of this router. Subrouters
+// without handler of their own use the handler of their parent. If no router
+// defines a handler, the global one (see "goyave.SetNotFoundHandler()") is used.
+//
+// Routers without prefix (groups) don't own any path, so their not found
+// handler is only used by their subrouters.
+//
+// The handler is also executed when a file requested to a static route
+// of this router (see "Static()" and "StaticFS()") doesn't exist. This allows
+// serving an HTML page for missing assets while the API returns JSON:
+//
+//  assets := router.Subrouter("/assets")
+//  assets.SetNotFoundHandler(func(response *goyave.Response, request *goyave.Request) {
+//  	response.Bytes(http.StatusNotFound, "text/html; charset=utf-8", notFoundPage)
+//  })
+//  assets.Static("/", "public", false)
+//
+// The handler is executed after the core middleware. If it doesn't write
+// anything to the response, the regular status handlers are executed.
+//
+//  api := router.Subrouter("/api")
+//  api.SetNotFoundHandler(func(response *goyave.Response, request *goyave.Request) {
+//  	response.JSON(http.StatusNotFound, map[string]string{"error": "Resource not found"})
+//  })
+func (r *Router) SetNotFoundHandler(handler Handler) {
+	r.notFoundHandler = handler
+}
+
+// SetMethodNotAllowedHandler replace the handler executed when a route of
+// this router matches the request's path but not its method. Subrouters
+// without handler of their own use the handler of their parent.
+//
+// The handler is executed after the core middleware. If it doesn't write
+// anything to the response, the regular status handlers are executed.
+// The "Allow" header is set before the handler is executed.
+func (r *Router) SetMethodNotAllowedHandler(handler Handler) {
+	r.methodNotAllowedHandler = handler
+}
+
 // StatusHandler set a handler for responses with an empty body.
 // The handler will be automatically executed if the request's life-cycle reaches its end
 // and nothing has been written in the response body.
@@ -386,6 +860,15 @@ func staticHandler(directory string, download bool) Handler {
 		file := r.Params["resource"]
 		path := cleanStaticPath(directory, file)
 
+		if filesystem.FileExists(path) {
+			if compressed, encoding := precompressedFile(response, r, path); compressed != "" {
+				mime, _ := filesystem.GetMIMEType(path)
+				response.Header().Set("Content-Type", mime)
+				response.Header().Set("Content-Encoding", encoding)
+				path = compressed
+			}
+		}
+
 		var err error
 		if download {
 			err = response.Download(path, file[strings.LastIndex(file, "/")+1:])
@@ -393,12 +876,101 @@ func staticHandler(directory string, download bool) Handler {
 			err = response.File(path)
 		}
 
-		if _, ok := err.(*os.PathError); err != nil && !ok {
-			ErrLogger.Println(err)
+		if _, ok := err.(*os.PathError); ok {
+			staticNotFound(response, r)
+		} else if err != nil {
+			GetLogger().Errorf("%v", err)
 		}
 	}
 }
 
+// precompressedEncodings the encodings of the pre-compressed static files,
+// associated with their file extension, by order of preference.
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// precompressedFile returns the path of the pre-compressed sibling of the given
+// file (for example "script.js.gz") best matching the encodings accepted by the client,
+// and the corresponding encoding. Returns empty strings if there is no such file.
+// The "Vary" header is set if the file has at least one pre-compressed sibling.
+func precompressedFile(response *Response, request *Request, path string) (string, string) {
+	var accepted []helper.HeaderValue
+	if header := request.Header().Get("Accept-Encoding"); header != "" {
+		accepted = helper.ParseMultiValuesHeader(header)
+	}
+
+	compressed, encoding := "", ""
+	priority := 0.0
+	for _, e := range precompressedEncodings {
+		if !filesystem.FileExists(path + e.extension) {
+			continue
+		}
+		response.Header().Set("Vary", "Accept-Encoding")
+		for _, a := range accepted {
+			if a.Value == e.encoding && a.Priority > priority {
+				compressed, encoding, priority = path+e.extension, e.encoding, a.Priority
+			}
+		}
+	}
+	return compressed, encoding
+}
+
+func staticFSHandler(fsys fs.FS, download bool) Handler {
+	return func(response *Response, r *Request) {
+		file := cleanStaticFSPath(fsys, r.Params["resource"])
+
+		var err error
+		if download {
+			err = response.DownloadFS(fsys, file, path.Base(file))
+		} else {
+			err = response.FileFS(fsys, file)
+		}
+
+		if _, ok := err.(*fs.PathError); ok {
+			staticNotFound(response, r)
+		} else if err != nil {
+			GetLogger().Errorf("%v", err)
+		}
+	}
+}
+
+// staticNotFound executes the not found handler of the router of the
+// static route, or of the closest of its parents, if the requested file
+// doesn't exist. Otherwise, the global not found handler is used.
+// If there is no handler, the regular 404 status handler is executed.
+func staticNotFound(response *Response, request *Request) {
+	handler := notFoundHandler
+	if request.route != nil {
+		for router := request.route.parent; router != nil; router = router.parent {
+			if router.notFoundHandler != nil {
+				handler = router.notFoundHandler
+				break
+			}
+		}
+	}
+	if handler != nil {
+		handler(response, request)
+	}
+}
+
+// cleanStaticFSPath returns the path of the given file in the file system.
+// Paths escaping the root of the file system are not valid and cannot be opened.
+func cleanStaticFSPath(fsys fs.FS, file string) string {
+	file = path.Clean("/" + file)[1:]
+	if file == "" {
+		file = "."
+	}
+	if stat, err := fs.Stat(fsys, file); err == nil && stat.IsDir() {
+		file = path.Join(file, "index.html")
+	}
+	return file
+}
+
 func cleanStaticPath(directory string, file string) string {
 	file = strings.TrimPrefix(file, "/")
 	path := directory + "/" + file
@@ -424,12 +996,20 @@ func (r *Router) requestHandler(match *routeMatch, w http.ResponseWriter, rawReq
 		httpRequest: rawRequest,
 		route:       match.route,
 		corsOptions: match.corsOptions,
-		Rules:       match.route.validationRules,
+		Rules:       match.route.getMergedValidationRules(),
 		Params:      match.parameters,
 		Extra:       map[string]interface{}{},
+		startTime:   clock.Now(),
 	}
 	response := newResponse(w, rawRequest)
+	response.request = request
+	if match.isMethodNotAllowed() {
+		response.Header().Set("Allow", match.allowHeader())
+	}
 	handler := match.route.handler
+	if h := r.findErrorHandler(match); h != nil {
+		handler = h
+	}
 
 	// Validate last.
 	// Allows custom middleware to be executed after core
@@ -450,8 +1030,43 @@ func (r *Router) requestHandler(match *routeMatch, w http.ResponseWriter, rawReq
 	r.finalize(response, request)
 }
 
+// findErrorHandler returns the not found or method not allowed handler
+// defined on the router owning the unmatched path, or on the closest of
+// its parents. Returns nil if the route was matched or if no handler is defined.
+func (r *Router) findErrorHandler(match *routeMatch) Handler {
+	if match.route != notFoundRoute && match.route != methodNotAllowedRoute {
+		return nil
+	}
+	router := match.router
+	if router == nil {
+		router = r
+	}
+	for ; router != nil; router = router.parent {
+		if match.route == notFoundRoute && router.notFoundHandler != nil {
+			return router.notFoundHandler
+		}
+		if match.route == methodNotAllowedRoute && router.methodNotAllowedHandler != nil {
+			return router.methodNotAllowedHandler
+		}
+	}
+	return nil
+}
+
+// depth returns the number of parents of this router.
+func (r *Router) depth() int {
+	depth := 0
+	for router := r.parent; router != nil; router = router.parent {
+		depth++
+	}
+	return depth
+}
+
 // finalize the request's life-cycle.
 func (r *Router) finalize(response *Response, request *Request) {
+	if response.empty && !response.wroteHeader && (response.status == 0 || response.status == http.StatusInternalServerError) &&
+		request.httpRequest.Context().Err() == context.DeadlineExceeded {
+		response.deadlineExceeded(context.DeadlineExceeded)
+	}
 	if response.empty {
 		if response.status == 0 {
 			// If the response is empty, return status 204 to
@@ -464,6 +1079,15 @@ func (r *Router) finalize(response *Response, request *Request) {
 		}
 	}
 
+	if response.buffer != nil && !response.hijacked {
+		if !response.wroteHeader && bodyAllowedForStatus(response.status) {
+			response.Header().Set("Content-Length", strconv.Itoa(response.buffer.Len()))
+		}
+		if err := response.flushBuffer(); err != nil && !response.isClientDisconnect(err) {
+			GetLogger().Errorf("%s%v", response.logPrefix(), err)
+		}
+	}
+
 	if !response.wroteHeader && !response.hijacked {
 		response.WriteHeader(response.status)
 	}
@@ -487,6 +1111,30 @@ func (rm *routeMatch) mergeParams(params map[string]string) {
 	}
 }
 
+func (rm *routeMatch) addAllowedMethods(methods []string) {
+	for _, m := range methods {
+		if !helper.ContainsStr(rm.allowedMethods, m) {
+			rm.allowedMethods = append(rm.allowedMethods, m)
+		}
+	}
+}
+
+// isMethodNotAllowed returns true if at least one route matched the path
+// but none of them accepted the request's method.
+func (rm *routeMatch) isMethodNotAllowed() bool {
+	return rm.route == methodNotAllowedRoute || rm.route == optionsRoute
+}
+
+// allowHeader returns the value of the "Allow" header listing the methods
+// accepted for the matched path.
+func (rm *routeMatch) allowHeader() string {
+	methods := rm.allowedMethods
+	if !helper.ContainsStr(methods, http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+	return strings.Join(methods, ", ")
+}
+
 func (rm *routeMatch) trimCurrentPath(fullMatch string) {
 	rm.currentPath = rm.currentPath[len(fullMatch):]
 }
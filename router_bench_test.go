@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strconv"
 	"testing"
 
 	"goyave.dev/goyave/v3/validation"
@@ -226,6 +227,85 @@ func BenchmarkMatchAll(b *testing.B) {
 	}
 }
 
+// registerLargeRouter registers 1000 routes: 100 resources having
+// 10 routes each, half of them using parameters.
+func registerLargeRouter() *Router {
+	router := NewRouter()
+	for i := 0; i < 100; i++ {
+		resource := router.Subrouter("/resource" + strconv.Itoa(i))
+		resource.Get("/", handler)
+		resource.Post("/", handler)
+		resource.Get("/search", handler)
+		resource.Get("/export.csv", handler)
+		resource.Get("/{id:[0-9]+}", handler)
+		resource.Put("/{id:[0-9]+}", handler)
+		resource.Delete("/{id:[0-9]+}", handler)
+		resource.Get("/{id:[0-9]+}/comments", handler)
+		resource.Get("/{id:[0-9]+}/comments/{commentID:[0-9]+}", handler)
+		resource.Get("/{slug}/details", handler)
+	}
+	for i := 0; i < 1000; i++ {
+		router.Get("/flat/route"+strconv.Itoa(i)+"/{id:[0-9]+}", handler)
+	}
+	return router
+}
+
+var largeRouterRequests []*http.Request = []*http.Request{
+	httptest.NewRequest("GET", "/resource99", nil),
+	httptest.NewRequest("GET", "/resource99/export.csv", nil),
+	httptest.NewRequest("DELETE", "/resource99/42", nil),
+	httptest.NewRequest("GET", "/resource99/42/comments/7", nil),
+	httptest.NewRequest("GET", "/resource99/abc/details", nil),
+	httptest.NewRequest("GET", "/flat/route999/42", nil),
+	httptest.NewRequest("GET", "/flat/unknown", nil), // 404
+}
+
+func BenchmarkLargeRouterMatch(b *testing.B) {
+	router := registerLargeRouter()
+	b.ReportAllocs()
+	runtime.GC()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, r := range largeRouterRequests {
+			router.match(r, &routeMatch{currentPath: r.URL.Path})
+		}
+	}
+}
+
+// BenchmarkLargeRouterMatchPath and BenchmarkLargeRouterRegex compare the cost
+// of testing a path against the 1000 root-level routes of the large router
+// with and without the literal prefix shortcut.
+func BenchmarkLargeRouterMatchPath(b *testing.B) {
+	routes := registerLargeRouter().routes
+	b.ReportAllocs()
+	runtime.GC()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, r := range largeRouterRequests {
+			for _, route := range routes {
+				route.matchPath(r.URL.Path)
+			}
+		}
+	}
+}
+
+func BenchmarkLargeRouterRegex(b *testing.B) {
+	routes := registerLargeRouter().routes
+	b.ReportAllocs()
+	runtime.GC()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, r := range largeRouterRequests {
+			for _, route := range routes {
+				route.regex.FindStringSubmatch(r.URL.Path)
+			}
+		}
+	}
+}
+
 func setupRouteBench(b *testing.B) *Router {
 	router := registerAll(sampleRouteDefinition)
 	b.ReportAllocs()
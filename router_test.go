@@ -1,16 +1,29 @@
 package goyave
 
 import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/cors"
+	"goyave.dev/goyave/v3/lang"
+	"goyave.dev/goyave/v3/validation"
 )
 
+//go:embed resources/template
+var embeddedResources embed.FS
+
 type RouterTestSuite struct {
 	TestSuite
 	middlewareExecuted bool
@@ -71,7 +84,7 @@ func (suite *RouterTestSuite) TestRouterRegisterRoute() {
 	suite.Equal("/", route.uri)
 	suite.Equal(router, route.parent)
 
-	route = router.Route("GET|POST", "/", func(resp *Response, r *Request) {})
+	route = router.Route("GET|POST", "/multiple", func(resp *Response, r *Request) {})
 	suite.Equal([]string{"GET", "POST", "HEAD"}, route.methods)
 	suite.Equal(router, route.parent)
 
@@ -80,14 +93,182 @@ func (suite *RouterTestSuite) TestRouterRegisterRoute() {
 	suite.Equal("", route.uri)
 
 	group := router.Group()
-	route = group.Route("GET", "/", func(resp *Response, r *Request) {})
+	route = group.Route("POST", "/", func(resp *Response, r *Request) {})
 	suite.Equal("/", route.uri)
 
 	group2 := router.Subrouter("/")
-	route = group2.Route("GET", "/", func(resp *Response, r *Request) {})
+	route = group2.Route("PUT", "/", func(resp *Response, r *Request) {})
 	suite.Equal("/", route.uri)
 }
 
+func (suite *RouterTestSuite) TestRouterDuplicateRoute() {
+	handler := func(resp *Response, r *Request) {}
+	router := NewRouter()
+	router.Get("/users", handler)
+	suite.PanicsWithError("Route GET \"/users\" conflicts with the route GET|HEAD \"/users\" registered before. Use \"Router.Override()\" to replace it intentionally", func() {
+		router.Get("/users", handler)
+	})
+	suite.Panics(func() { router.Route("POST|HEAD", "/users", handler) })
+
+	// Same compiled pattern with different parameter names
+	router.Get("/users/{id:[0-9]+}", handler)
+	suite.Panics(func() { router.Get("/users/{userID:[0-9]+}", handler) })
+
+	// Different methods or patterns don't conflict
+	suite.NotPanics(func() {
+		router.Post("/users", handler)
+		router.Get("/users/{name}", handler)
+		router.Route("HEAD", "/articles", handler)
+		router.Get("/articles", handler) // Implicit HEAD doesn't conflict
+	})
+
+	// Implicit OPTIONS added by CORS don't conflict
+	router = NewRouter()
+	router.CORS(cors.Default())
+	suite.NotPanics(func() {
+		router.Get("/cors", handler)
+		router.Post("/cors", handler)
+	})
+
+	// The whole router tree is checked
+	router = NewRouter()
+	router.Get("/sub", handler)
+	subrouter := router.Subrouter("/sub")
+	suite.PanicsWithError("Route GET \"/sub\" conflicts with the route GET|HEAD \"/sub\" registered before. Use \"Router.Override()\" to replace it intentionally", func() {
+		subrouter.Get("/", handler)
+	})
+	subrouter.Get("/{id:[0-9]+}", handler)
+	suite.Panics(func() { router.Get("/sub/{userID:[0-9]+}", handler) })
+	suite.Panics(func() { router.Subrouter("/sub").Get("/{id:[0-9]+}", handler) })
+
+	// Route groups
+	router = NewRouter()
+	api := router.Subrouter("/api")
+	api.Group().Get("/users", handler)
+	suite.PanicsWithError("Route GET \"/api/users\" conflicts with the route GET|HEAD \"/api/users\" registered before. Use \"Router.Override()\" to replace it intentionally", func() {
+		api.Group().Get("/users", handler)
+	})
+	suite.NotPanics(func() {
+		api.Group().Post("/users", handler)
+		router.Subrouter("/other").Get("/users", handler)
+	})
+}
+
+func (suite *RouterTestSuite) TestRouterOverrideAcrossRouters() {
+	router := NewRouter()
+	api := router.Subrouter("/api")
+	original := api.Group().Route("GET|POST", "/users", func(resp *Response, r *Request) {})
+
+	override := api.Override("GET", "/users", func(resp *Response, r *Request) {})
+	suite.Equal([]string{"POST"}, original.methods)
+
+	match := routeMatch{currentPath: "/api/users"}
+	suite.True(router.match(httptest.NewRequest("GET", "/api/users", nil), &match))
+	suite.Same(override, match.route)
+
+	router.Override("POST", "/api/users", func(resp *Response, r *Request) {})
+	suite.Empty(api.subrouters[0].routes)
+}
+
+func (suite *RouterTestSuite) TestRouterOverride() {
+	router := NewRouter()
+	original := router.Route("GET|POST", "/users", func(resp *Response, r *Request) {
+		resp.String(http.StatusOK, "original")
+	}).Name("users")
+
+	override := router.Override("GET", "/users", func(resp *Response, r *Request) {
+		resp.String(http.StatusOK, "override")
+	})
+	suite.Equal([]string{"POST"}, original.methods)
+	suite.Equal([]string{"GET", "HEAD"}, override.methods)
+	suite.Len(router.routes, 2)
+	suite.Same(original, router.GetRoute("users"))
+
+	match := routeMatch{currentPath: "/users"}
+	suite.True(router.match(httptest.NewRequest("GET", "/users", nil), &match))
+	suite.Same(override, match.route)
+	match = routeMatch{currentPath: "/users"}
+	suite.True(router.match(httptest.NewRequest("POST", "/users", nil), &match))
+	suite.Same(original, match.route)
+
+	// Overriding all methods removes the route
+	router.Override("POST", "/users", func(resp *Response, r *Request) {}).Name("new-users")
+	suite.Len(router.routes, 2)
+	suite.NotContains(router.routes, original)
+	suite.Nil(router.GetRoute("users"))
+	suite.NotNil(router.GetRoute("new-users"))
+
+	// Override without conflict
+	route := router.Override("GET", "/articles", func(resp *Response, r *Request) {})
+	suite.Contains(router.routes, route)
+}
+
+func (suite *RouterTestSuite) TestRouterSpecificity() {
+	router := NewRouter()
+	wildcard := router.Get("/users/{path:.*}", nil)
+	open := router.Get("/users/{name}", nil)
+	constrained := router.Get("/users/{id:[0-9]+}", nil)
+	static := router.Get("/users/me", nil)
+	suite.Equal([]*Route{static, constrained, open, wildcard}, router.routes)
+
+	match := routeMatch{currentPath: "/users/me"}
+	suite.True(router.match(httptest.NewRequest("GET", "/users/me", nil), &match))
+	suite.Same(static, match.route)
+
+	match = routeMatch{currentPath: "/users/42"}
+	suite.True(router.match(httptest.NewRequest("GET", "/users/42", nil), &match))
+	suite.Same(constrained, match.route)
+	suite.Equal("42", match.parameters["id"])
+
+	match = routeMatch{currentPath: "/users/john"}
+	suite.True(router.match(httptest.NewRequest("GET", "/users/john", nil), &match))
+	suite.Same(open, match.route)
+
+	match = routeMatch{currentPath: "/users/john/avatar"}
+	suite.True(router.match(httptest.NewRequest("GET", "/users/john/avatar", nil), &match))
+	suite.Same(wildcard, match.route)
+
+	// The first segment with a different rank decides
+	router = NewRouter()
+	param := router.Get("/{category}/latest", nil)
+	literal := router.Get("/articles/{id}", nil)
+	suite.Equal([]*Route{literal, param}, router.routes)
+
+	// Same specificity: registration order is kept
+	router = NewRouter()
+	first := router.Get("/{a}/{b}", nil)
+	second := router.Get("/{c}-{d}/{e}", nil)
+	suite.Equal([]*Route{first, second}, router.routes)
+
+	// Overrides are sorted too
+	router = NewRouter()
+	open = router.Get("/users/{id}", nil)
+	static = router.Override("GET", "/users/me", nil)
+	suite.Equal([]*Route{static, open}, router.routes)
+
+	suite.RunServer(func(router *Router) {
+		router.Get("/users/{id}", func(response *Response, request *Request) {
+			response.String(http.StatusOK, "user "+request.Params["id"])
+		})
+		router.Get("/users/me", func(response *Response, request *Request) {
+			response.String(http.StatusOK, "me")
+		})
+	}, func() {
+		resp, err := suite.Get("/users/me", nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal("me", string(suite.GetBody(resp)))
+		}
+		resp, err = suite.Get("/users/1", nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal("user 1", string(suite.GetBody(resp)))
+		}
+	})
+}
+
 func (suite *RouterTestSuite) TestRouterMiddleware() {
 	router := NewRouter()
 	router.Middleware(suite.routerTestMiddleware)
@@ -236,7 +417,7 @@ func (suite *RouterTestSuite) TestRequestHandler() {
 	}
 	result.Body.Close()
 	suite.Equal(404, result.StatusCode)
-	suite.Equal("{\"error\":\""+http.StatusText(404)+"\"}\n", string(body))
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\""+http.StatusText(404)+"\"}}\n", string(body))
 }
 
 func (suite *RouterTestSuite) TestCORS() {
@@ -355,7 +536,389 @@ func (suite *RouterTestSuite) TestErrorStatusHandler() {
 		panic(err)
 	}
 	result.Body.Close()
-	suite.Equal("{\"error\":\""+http.StatusText(404)+"\"}\n", string(body))
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\""+http.StatusText(404)+"\"}}\n", string(body))
+}
+
+func (suite *RouterTestSuite) serveTestRequest(router *Router, method, url string, headers map[string]string) (int, string, string) {
+	rawRequest := httptest.NewRequest(method, url, nil)
+	for k, v := range headers {
+		rawRequest.Header.Set(k, v)
+	}
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, rawRequest)
+	result := writer.Result()
+	body, err := ioutil.ReadAll(result.Body)
+	if err != nil {
+		panic(err)
+	}
+	result.Body.Close()
+	return result.StatusCode, result.Header.Get("Content-Type"), string(body)
+}
+
+func (suite *RouterTestSuite) TestStaticFS() {
+	assets, err := fs.Sub(embeddedResources, "resources")
+	if err != nil {
+		panic(err)
+	}
+	router := NewRouter()
+	router.StaticFS("/assets", assets, false)
+	router.StaticFS("/download", assets, true)
+	router.StaticFS("/spa", fstest.MapFS{
+		"index.html":     {Data: []byte("<!DOCTYPE html><html><body>index</body></html>")},
+		"app/index.html": {Data: []byte("<!DOCTYPE html><html><body>app</body></html>")},
+	}, false)
+
+	status, contentType, body := suite.serveTestRequest(router, http.MethodGet, "/assets/template/error.txt", nil)
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("text/plain; charset=utf-8", contentType)
+	suite.Equal("Error {{.Status}}: {{.Message}}", body)
+
+	rawRequest := httptest.NewRequest(http.MethodGet, "/download/template/error.txt", nil)
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, rawRequest)
+	result := writer.Result()
+	result.Body.Close()
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Equal("attachment; filename=\"error.txt\"", result.Header.Get("Content-Disposition"))
+
+	// Range
+	rawRequest = httptest.NewRequest(http.MethodGet, "/assets/template/error.txt", nil)
+	rawRequest.Header.Set("Range", "bytes=0-4")
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, rawRequest)
+	result = writer.Result()
+	body2, _ := ioutil.ReadAll(result.Body)
+	result.Body.Close()
+	suite.Equal(http.StatusPartialContent, result.StatusCode)
+	suite.Equal("Error", string(body2))
+
+	// Index
+	status, contentType, body = suite.serveTestRequest(router, http.MethodGet, "/spa", nil)
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("text/html; charset=utf-8", contentType)
+	suite.Equal("<!DOCTYPE html><html><body>index</body></html>", body)
+	_, _, body = suite.serveTestRequest(router, http.MethodGet, "/spa/app/", nil)
+	suite.Equal("<!DOCTYPE html><html><body>app</body></html>", body)
+
+	// Missing files
+	for _, url := range []string{"/assets/nothing", "/assets/template", "/assets/../go.mod", "/assets/template/../../go.mod"} {
+		status, _, _ = suite.serveTestRequest(router, http.MethodGet, url, nil)
+		suite.Equal(http.StatusNotFound, status, url)
+	}
+}
+
+func (suite *RouterTestSuite) TestStaticNotFound() {
+	router := NewRouter()
+	api := router.Subrouter("/api")
+	api.Get("/users", func(response *Response, request *Request) {
+		response.JSON(http.StatusOK, []string{})
+	})
+	assets := router.Subrouter("/assets")
+	assets.SetNotFoundHandler(func(response *Response, request *Request) {
+		response.Bytes(http.StatusNotFound, "text/html; charset=utf-8", []byte("<h1>Asset not found</h1>"))
+	})
+	assets.Static("/", "resources", false)
+	assets.StaticFS("/embedded", embeddedResources, false)
+	router.Static("/plain", "resources", false)
+
+	status, _, body := suite.serveTestRequest(router, http.MethodGet, "/assets/test_file.txt", nil)
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("\ufeffutf-8 with BOM content", body)
+
+	status, contentType, body := suite.serveTestRequest(router, http.MethodGet, "/assets/missing.txt", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("text/html; charset=utf-8", contentType)
+	suite.Equal("<h1>Asset not found</h1>", body)
+
+	status, contentType, body = suite.serveTestRequest(router, http.MethodGet, "/assets/embedded/missing.txt", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("text/html; charset=utf-8", contentType)
+	suite.Equal("<h1>Asset not found</h1>", body)
+
+	// No handler: regular status handler
+	status, contentType, body = suite.serveTestRequest(router, http.MethodGet, "/plain/missing.txt", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("application/json; charset=utf-8", contentType)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", body)
+
+	status, contentType, body = suite.serveTestRequest(router, http.MethodGet, "/api/missing", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("application/json; charset=utf-8", contentType)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", body)
+}
+
+func (suite *RouterTestSuite) TestStaticPrecompressed() {
+	dir, err := ioutil.TempDir("", "goyave-static")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"script.js":     "console.log('plain')",
+		"script.js.gz":  "gzip content",
+		"style.css":     "body{color:red}",
+		"style.css.gz":  "gzip content",
+		"style.css.br":  "brotli content",
+		"orphan.txt.gz": "gzip content",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(dir+"/"+name, []byte(content), 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	router := NewRouter()
+	router.Static("/assets", dir, false)
+
+	request := func(url, acceptEncoding string) *http.Response {
+		rawRequest := httptest.NewRequest(http.MethodGet, url, nil)
+		if acceptEncoding != "" {
+			rawRequest.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, rawRequest)
+		return writer.Result()
+	}
+	assertResponse := func(resp *http.Response, encoding, contentType, content string) {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		suite.Nil(err)
+		suite.Equal(http.StatusOK, resp.StatusCode)
+		suite.Equal(encoding, resp.Header.Get("Content-Encoding"))
+		suite.Equal(contentType, resp.Header.Get("Content-Type"))
+		suite.Equal("Accept-Encoding", resp.Header.Get("Vary"))
+		suite.Equal(content, string(body))
+	}
+
+	assertResponse(request("/assets/script.js", "gzip, deflate"), "gzip", "text/javascript", "gzip content")
+	assertResponse(request("/assets/script.js", "br"), "", "text/javascript", "console.log('plain')")
+	assertResponse(request("/assets/script.js", ""), "", "text/javascript", "console.log('plain')")
+	assertResponse(request("/assets/script.js", "gzip;q=0"), "", "text/javascript", "console.log('plain')")
+
+	assertResponse(request("/assets/style.css", "gzip, deflate, br"), "br", "text/css", "brotli content")
+	assertResponse(request("/assets/style.css", "gzip, br;q=0.5"), "gzip", "text/css", "gzip content")
+	assertResponse(request("/assets/style.css", "gzip"), "gzip", "text/css", "gzip content")
+
+	// The plain file must exist
+	resp := request("/assets/orphan.txt", "gzip")
+	resp.Body.Close()
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+	suite.Empty(resp.Header.Get("Content-Encoding"))
+}
+
+func (suite *RouterTestSuite) TestSetNotFoundHandler() {
+	SetNotFoundHandler(func(response *Response, request *Request) {
+		response.JSON(http.StatusNotFound, map[string]interface{}{
+			"status":  http.StatusNotFound,
+			"message": "no route for " + request.URI().Path,
+			"lang":    request.Lang,
+		})
+	})
+	defer SetNotFoundHandler(nil)
+
+	router := NewRouter()
+	router.Get("/hello", helloHandler)
+
+	status, _, body := suite.serveTestRequest(router, http.MethodGet, "/unknown", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("{\"lang\":\"en-US\",\"message\":\"no route for /unknown\",\"status\":404}\n", body)
+
+	status, _, body = suite.serveTestRequest(router, http.MethodGet, "/hello", nil)
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("Hi!", body)
+
+	// Handler not writing anything falls back to status handlers
+	SetNotFoundHandler(func(response *Response, request *Request) {
+		response.Status(http.StatusNotFound)
+	})
+	status, _, body = suite.serveTestRequest(router, http.MethodGet, "/unknown", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", body)
+}
+
+func (suite *RouterTestSuite) TestSetErrorHandler() {
+	SetErrorHandler(func(response *Response, request *Request, status int) {
+		response.JSON(status, map[string]interface{}{"code": status, "success": false})
+	})
+	defer SetErrorHandler(nil)
+
+	prev := config.Get("app.debug")
+	config.Set("app.debug", false)
+	defer config.Set("app.debug", prev)
+
+	router := NewRouter()
+	router.Get("/hello", helloHandler)
+	router.Get("/panic", func(response *Response, request *Request) {
+		panic("test panic")
+	})
+
+	status, _, body := suite.serveTestRequest(router, http.MethodGet, "/unknown", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("{\"code\":404,\"success\":false}\n", body)
+
+	status, _, body = suite.serveTestRequest(router, http.MethodPost, "/hello", nil)
+	suite.Equal(http.StatusMethodNotAllowed, status)
+	suite.Equal("{\"code\":405,\"success\":false}\n", body)
+
+	status, _, body = suite.serveTestRequest(router, http.MethodGet, "/panic", nil)
+	suite.Equal(http.StatusInternalServerError, status)
+	suite.Equal("{\"code\":500,\"success\":false}\n", body)
+
+	SetErrorHandler(nil)
+	status, _, body = suite.serveTestRequest(router, http.MethodGet, "/unknown", nil)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", body)
+}
+
+func (suite *RouterTestSuite) TestDefaultErrorHandlerNegotiation() {
+	router := NewRouter()
+
+	headers := map[string]string{"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"}
+	status, contentType, body := suite.serveTestRequest(router, http.MethodGet, "/unknown", headers)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("text/html; charset=utf-8", contentType)
+	suite.Contains(body, "<title>404 Not Found</title>")
+	suite.Contains(body, "<h1>404 Not Found</h1>")
+
+	headers = map[string]string{"Accept": "application/json, text/html;q=0.5"}
+	status, contentType, body = suite.serveTestRequest(router, http.MethodGet, "/unknown", headers)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("application/json; charset=utf-8", contentType)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", body)
+
+	status, contentType, _ = suite.serveTestRequest(router, http.MethodGet, "/unknown", map[string]string{"Accept": "*/*"})
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("application/json; charset=utf-8", contentType)
+
+	// Priorities are used, not the order of the values
+	for _, accept := range []string{"application/json;q=0.5, text/html", "application/xml, application/xhtml+xml;q=0.9, application/json;q=0.1"} {
+		_, contentType, _ = suite.serveTestRequest(router, http.MethodGet, "/unknown", map[string]string{"Accept": accept})
+		suite.Equal("text/html; charset=utf-8", contentType, accept)
+	}
+	for _, accept := range []string{"text/html;q=0.5, application/json", "text/*;q=0.5, */*", ""} {
+		_, contentType, _ = suite.serveTestRequest(router, http.MethodGet, "/unknown", map[string]string{"Accept": accept})
+		suite.Equal("application/json; charset=utf-8", contentType, accept)
+	}
+}
+
+func (suite *RouterTestSuite) TestErrorEnvelope() {
+	response := newResponse(httptest.NewRecorder(), nil)
+	suite.Nil(response.ErrorMessage(http.StatusNotFound, "User not found."))
+	result := response.responseWriter.(*httptest.ResponseRecorder).Result()
+	body, _ := ioutil.ReadAll(result.Body)
+	result.Body.Close()
+	suite.Equal(http.StatusNotFound, result.StatusCode)
+	suite.Equal("application/json; charset=utf-8", result.Header.Get("Content-Type"))
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"User not found.\"}}\n", string(body))
+
+	response = newResponse(httptest.NewRecorder(), nil)
+	suite.Nil(response.ErrorJSON(http.StatusConflict, map[string]interface{}{"code": 409, "message": "Conflict", "field": "email"}))
+	result = response.responseWriter.(*httptest.ResponseRecorder).Result()
+	body, _ = ioutil.ReadAll(result.Body)
+	result.Body.Close()
+	suite.Equal(http.StatusConflict, result.StatusCode)
+	suite.Equal("{\"error\":{\"code\":409,\"field\":\"email\",\"message\":\"Conflict\"}}\n", string(body))
+
+	// Localized framework messages
+	dir, err := ioutil.TempDir("", "goyave-lang")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(dir+"/locale.json", []byte(`{"http.404": "Page introuvable"}`), 0644); err != nil {
+		panic(err)
+	}
+	lang.Load("envelope-TEST", dir)
+
+	request := suite.CreateTestRequest(nil)
+	request.Lang = "envelope-TEST"
+	for status, message := range map[int]string{http.StatusNotFound: "Page introuvable", http.StatusServiceUnavailable: "Service Unavailable"} {
+		response = newResponse(httptest.NewRecorder(), nil)
+		DefaultErrorHandler(response, request, status)
+		result = response.responseWriter.(*httptest.ResponseRecorder).Result()
+		body, _ = ioutil.ReadAll(result.Body)
+		result.Body.Close()
+		suite.Equal(status, result.StatusCode)
+		suite.Equal("{\"error\":{\"code\":"+strconv.Itoa(status)+",\"message\":\""+message+"\"}}\n", string(body))
+	}
+}
+
+func (suite *RouterTestSuite) TestLocalizedErrors() {
+	dir, err := ioutil.TempDir("", "goyave-lang")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(dir+"/locale.json", []byte(`{"http.404": "Page introuvable", "http.500": "Erreur interne"}`), 0644); err != nil {
+		panic(err)
+	}
+	lang.Load("fr-FR", dir)
+
+	router := NewRouter()
+	router.Get("/panic", func(response *Response, request *Request) {
+		panic("test panic")
+	})
+	prevDebug := config.Get("app.debug")
+	config.Set("app.debug", false)
+	defer config.Set("app.debug", prevDebug)
+	SetLogger(&testLogger{})
+	defer SetLogger(nil)
+
+	headers := map[string]string{"Accept-Language": "fr-FR, en;q=0.5"}
+	status, _, body := suite.serveTestRequest(router, http.MethodGet, "/unknown", headers)
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Page introuvable\"}}\n", body)
+
+	status, _, body = suite.serveTestRequest(router, http.MethodGet, "/panic", headers)
+	suite.Equal(http.StatusInternalServerError, status)
+	suite.Equal("{\"error\":{\"code\":500,\"message\":\"Erreur interne\"}}\n", body)
+
+	// Missing translation: fall back to the default language
+	status, _, body = suite.serveTestRequest(router, http.MethodPost, "/panic", headers)
+	suite.Equal(http.StatusMethodNotAllowed, status)
+	suite.Equal("{\"error\":{\"code\":405,\"message\":\"Method Not Allowed\"}}\n", body)
+
+	// Default language
+	status, _, body = suite.serveTestRequest(router, http.MethodGet, "/unknown", map[string]string{"Accept-Language": "en-US"})
+	suite.Equal(http.StatusNotFound, status)
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", body)
+}
+
+func (suite *RouterTestSuite) TestPayloadTooLarge() {
+	prev := config.Get("server.maxUploadSize")
+	config.Set("server.maxUploadSize", 0.00001) // ~10 bytes
+	defer config.Set("server.maxUploadSize", prev)
+
+	executed := false
+	router := NewRouter()
+	router.Post("/upload", func(response *Response, request *Request) {
+		executed = true
+		response.Status(http.StatusNoContent)
+	})
+
+	rawRequest := httptest.NewRequest("POST", "/upload", strings.NewReader("string=a"))
+	rawRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, rawRequest)
+	result := writer.Result()
+	result.Body.Close()
+	suite.Equal(http.StatusNoContent, result.StatusCode)
+	suite.True(executed)
+
+	executed = false
+	rawRequest = httptest.NewRequest("POST", "/upload", strings.NewReader("string=too long to fit"))
+	rawRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, rawRequest)
+	result = writer.Result()
+	body, err := ioutil.ReadAll(result.Body)
+	if err != nil {
+		panic(err)
+	}
+	result.Body.Close()
+	suite.Equal(http.StatusRequestEntityTooLarge, result.StatusCode)
+	suite.False(executed)
+	suite.Equal("{\"error\":{\"code\":413,\"message\":\"The request body may not be larger than 0.00001 MiB.\"}}\n", string(body))
 }
 
 func (suite *RouterTestSuite) TestStatusHandlers() {
@@ -464,6 +1027,101 @@ func (suite *RouterTestSuite) TestRouteNoMatch() {
 	result.Body.Close()
 }
 
+func (suite *RouterTestSuite) TestAutomaticOptions() {
+	suite.RunServer(func(router *Router) {
+		router.Get("/users", func(response *Response, request *Request) {})
+		router.Post("/users", func(response *Response, request *Request) {})
+		router.Route("GET|OPTIONS", "/articles", func(response *Response, request *Request) {
+			if request.Method() == http.MethodOptions {
+				response.String(http.StatusOK, "explicit")
+			}
+		})
+	}, func() {
+		resp, err := suite.Request(http.MethodOptions, "/users", nil, nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusNoContent, resp.StatusCode)
+			suite.Equal("GET, HEAD, POST, OPTIONS", resp.Header.Get("Allow"))
+			suite.Empty(suite.GetBody(resp))
+		}
+
+		resp, err = suite.Request(http.MethodDelete, "/users", nil, nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+			suite.Equal("GET, HEAD, POST, OPTIONS", resp.Header.Get("Allow"))
+		}
+
+		// Explicit OPTIONS route takes precedence
+		resp, err = suite.Request(http.MethodOptions, "/articles", nil, nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusOK, resp.StatusCode)
+			suite.Empty(resp.Header.Get("Allow"))
+			suite.Equal("explicit", string(suite.GetBody(resp)))
+		}
+
+		// Unknown path
+		resp, err = suite.Request(http.MethodOptions, "/unknown", nil, nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusNotFound, resp.StatusCode)
+			suite.Empty(resp.Header.Get("Allow"))
+		}
+	})
+}
+
+func (suite *RouterTestSuite) TestSubrouterErrorHandlers() {
+	suite.RunServer(func(router *Router) {
+		router.SetNotFoundHandler(func(response *Response, request *Request) {
+			response.Header().Set("Content-Type", "text/html; charset=utf-8")
+			response.String(http.StatusNotFound, "<h1>Page not found</h1>")
+		})
+		router.Get("/home", func(response *Response, request *Request) {})
+
+		api := router.Subrouter("/api")
+		api.SetNotFoundHandler(func(response *Response, request *Request) {
+			response.JSON(http.StatusNotFound, map[string]string{"error": "api not found"})
+		})
+		api.SetMethodNotAllowedHandler(func(response *Response, request *Request) {
+			response.JSON(http.StatusMethodNotAllowed, map[string]string{"error": "api method not allowed"})
+		})
+		api.Get("/users", func(response *Response, request *Request) {})
+
+		// Inherited from "/api"
+		v2 := api.Subrouter("/v2")
+		v2.Get("/users", func(response *Response, request *Request) {})
+
+		// Groups don't own any path
+		group := router.Group()
+		group.SetNotFoundHandler(func(response *Response, request *Request) {
+			response.String(http.StatusNotFound, "group")
+		})
+		group.Get("/group", func(response *Response, request *Request) {})
+	}, func() {
+		check := func(method, route string, status int, body string) {
+			resp, err := suite.Request(method, route, nil, nil)
+			suite.Nil(err)
+			if err == nil {
+				defer resp.Body.Close()
+				suite.Equal(status, resp.StatusCode, route)
+				suite.Equal(body, string(suite.GetBody(resp)), route)
+			}
+		}
+		check(http.MethodGet, "/api/unknown", http.StatusNotFound, "{\"error\":\"api not found\"}\n")
+		check(http.MethodGet, "/api/v2/unknown", http.StatusNotFound, "{\"error\":\"api not found\"}\n")
+		check(http.MethodPost, "/api/users", http.StatusMethodNotAllowed, "{\"error\":\"api method not allowed\"}\n")
+		check(http.MethodPost, "/api/v2/users", http.StatusMethodNotAllowed, "{\"error\":\"api method not allowed\"}\n")
+		check(http.MethodGet, "/unknown", http.StatusNotFound, "<h1>Page not found</h1>")
+		check(http.MethodPost, "/home", http.StatusMethodNotAllowed, "{\"error\":{\"code\":405,\"message\":\"Method Not Allowed\"}}\n")
+		check(http.MethodPost, "/group", http.StatusMethodNotAllowed, "{\"error\":{\"code\":405,\"message\":\"Method Not Allowed\"}}\n")
+	})
+}
+
 func (suite *RouterTestSuite) TestNamedRoutes() {
 	r := NewRouter()
 	route := r.Route("GET", "/uri", func(resp *Response, r *Request) {})
@@ -486,6 +1144,83 @@ func (suite *RouterTestSuite) TestNamedRoutes() {
 	router = nil
 }
 
+func (suite *RouterTestSuite) TestRequestRoute() {
+	r := NewRouter()
+	var matched *Route
+	route := r.Route("GET|POST", "/product/{id:[0-9]+}", func(response *Response, request *Request) {
+		matched = request.Route()
+		response.Status(http.StatusOK)
+	}).Name("product.show")
+	r.ClearRegexCache()
+
+	writer := httptest.NewRecorder()
+	r.ServeHTTP(writer, httptest.NewRequest("POST", "/product/42", nil))
+	result := writer.Result()
+	result.Body.Close()
+
+	suite.Equal(http.StatusOK, result.StatusCode)
+	suite.Equal(route, matched)
+	if matched != nil {
+		suite.Equal("product.show", matched.GetName())
+		suite.Equal([]string{"GET", "POST", "HEAD"}, matched.GetMethods())
+		suite.Equal("/product/{id:[0-9]+}", matched.GetFullURI())
+	}
+}
+
+func (suite *RouterTestSuite) TestGroupValidation() {
+	r := NewRouter()
+	group := r.Subrouter("/admin")
+	group.Validate(validation.RuleSet{
+		"token": {"required", "string"},
+		"page":  {"integer"},
+	})
+	group.Get("/users", func(response *Response, request *Request) {
+		response.String(http.StatusOK, "users")
+	})
+	route := group.Post("/users", func(response *Response, request *Request) {
+		response.String(http.StatusOK, "created")
+	}).Validate(validation.RuleSet{
+		"token": {"required", "numeric"},
+		"name":  {"required", "string"},
+	})
+	r.ClearRegexCache()
+
+	request := func(method, uri string) (int, string) {
+		writer := httptest.NewRecorder()
+		r.ServeHTTP(writer, httptest.NewRequest(method, uri, nil))
+		result := writer.Result()
+		body, _ := ioutil.ReadAll(result.Body)
+		result.Body.Close()
+		return result.StatusCode, string(body)
+	}
+
+	status, body := request("GET", "/admin/users")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+	suite.Contains(body, "token")
+
+	status, body = request("GET", "/admin/users?token=abc&page=2")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("users", body)
+
+	status, body = request("POST", "/admin/users?token=abc&name=john")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+	suite.Contains(body, "token")
+
+	status, body = request("POST", "/admin/users?token=12&name=john&page=abc")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+	suite.Contains(body, "page")
+	suite.NotContains(body, "token")
+
+	status, body = request("POST", "/admin/users?token=12&name=john")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("created", body)
+
+	// Merged rules are computed once
+	rules := route.getMergedValidationRules()
+	suite.Same(rules, route.getMergedValidationRules())
+	suite.Len(rules.Fields, 3)
+}
+
 func (suite *RouterTestSuite) TestMiddleware() {
 	// Test the middleware execution order
 	result := ""
@@ -537,7 +1272,7 @@ func (suite *RouterTestSuite) TestCoreMiddleware() {
 	}
 	result.Body.Close()
 	suite.Equal(500, result.StatusCode)
-	suite.Equal("{\"error\":\"Internal Server Error\"}\n", string(body))
+	suite.Equal("{\"error\":{\"code\":500,\"message\":\"Internal Server Error\"}}\n", string(body))
 
 	lang := ""
 	param := ""
@@ -720,7 +1455,7 @@ func (suite *RouterTestSuite) TestScheme() {
 	result.Body.Close()
 
 	suite.Equal(http.StatusNotFound, result.StatusCode)
-	suite.Equal("{\"error\":\"Not Found\"}\n", string(body))
+	suite.Equal("{\"error\":{\"code\":404,\"message\":\"Not Found\"}}\n", string(body))
 }
 
 func (suite *RouterTestSuite) TestConflictingRoutes() {
@@ -733,7 +1468,7 @@ func (suite *RouterTestSuite) TestConflictingRoutes() {
 	subrouter := router.Subrouter("/product")
 	routeSub := subrouter.Route("GET", "/{id:[0-9]+}", handler)
 
-	router.Route("GET", "/product/{id:[0-9]+}", handler)
+	router.Route("GET", "/product/{id}", handler)
 
 	req := httptest.NewRequest("GET", "/product/2", nil)
 	match := routeMatch{currentPath: req.URL.Path}
@@ -765,13 +1500,14 @@ func (suite *RouterTestSuite) TestSubrouterEmptyPrefix() {
 
 	// This route group has an empty prefix, the full path is identical to productRouter.
 	// However this group has a middleware and some conflicting routes with productRouter.
-	// Conflict should be resolved and both routes should be able to match.
+	// Routes with different methods should all be able to match, and
+	// the conflicting route has to be overridden explicitly.
 	groupProductRouter := productRouter.Subrouter("/")
 	groupProductRouter.Middleware(suite.createOrderedTestMiddleware(&result, "1"))
 	groupProductRouter.Route("POST", "/", handler).Name("product.store")
 	groupProductRouter.Route("GET", "/hardpath", handler).Name("product.hardpath.get")
 	groupProductRouter.Route("PUT", "/{id:[0-9]+}", handler).Name("product.update")
-	groupProductRouter.Route("GET", "/conflict", handler).Name("product.conflict.group")
+	groupProductRouter.Override("GET", "/conflict", handler).Name("product.conflict.group")
 	groupProductRouter.Route("POST", "/method", handler).Name("product.method")
 
 	req := httptest.NewRequest("GET", "/product", nil)
@@ -928,6 +1664,226 @@ func (suite *RouterTestSuite) TestGetSubrouters() {
 	suite.NotSame(router.subrouters, subrouters)
 }
 
+func (suite *RouterTestSuite) TestMerge() {
+	middlewareCalls := 0
+	countMiddleware := func(next Handler) Handler {
+		return func(response *Response, request *Request) {
+			middlewareCalls++
+			next(response, request)
+		}
+	}
+	handler := func(name string) Handler {
+		return func(response *Response, request *Request) {
+			response.String(http.StatusOK, name+request.Params["id"])
+		}
+	}
+
+	admin := NewRouter()
+	admin.Middleware(countMiddleware)
+	admin.Get("/", handler("home")).Name("home")
+	admin.Get("/users/{id:[0-9]+}", handler("user")).Name("users.show")
+	admin.Post("/users", handler("store")).Validate(validation.RuleSet{
+		"name": {"required", "string"},
+	})
+	settings := admin.Subrouter("/settings")
+	settings.Get("/", handler("settings")).Name("settings")
+
+	router := NewRouter()
+	router.Get("/home", handler("main")).Name("home")
+	merged := router.Merge("/admin", admin)
+	suite.Equal("/admin", merged.prefix)
+	suite.Same(router, merged.parent)
+	suite.Contains(router.subrouters, merged)
+	suite.Len(merged.middleware, 1)
+
+	serve := func(method, url string) (int, string) {
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, httptest.NewRequest(method, url, nil))
+		result := writer.Result()
+		body, err := ioutil.ReadAll(result.Body)
+		if err != nil {
+			panic(err)
+		}
+		result.Body.Close()
+		return result.StatusCode, string(body)
+	}
+
+	status, body := serve("GET", "/admin")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("home", body)
+	suite.Equal(1, middlewareCalls)
+
+	status, body = serve("GET", "/admin/users/42")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("user42", body)
+
+	status, body = serve("GET", "/admin/settings")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("settings", body)
+	suite.Equal(3, middlewareCalls)
+
+	status, _ = serve("POST", "/admin/users")
+	suite.Equal(http.StatusUnprocessableEntity, status)
+
+	status, body = serve("GET", "/home")
+	suite.Equal(http.StatusOK, status)
+	suite.Equal("main", body)
+	suite.Equal(4, middlewareCalls) // Main route doesn't use the merged middleware
+
+	status, _ = serve("GET", "/users/42")
+	suite.Equal(http.StatusNotFound, status)
+
+	// Named routes are namespaced
+	suite.Equal("/home", router.GetRoute("home").GetFullURI())
+	suite.Equal("/admin", router.GetRoute("admin.home").GetFullURI())
+	suite.Equal("/admin/users/{id:[0-9]+}", router.GetRoute("admin.users.show").GetFullURI())
+	suite.Equal("/admin/users/42", router.GetRoute("admin.users.show").BuildURI("42"))
+	suite.Equal("/admin/settings", router.GetRoute("admin.settings").GetFullURI())
+	suite.Nil(router.GetRoute("users.show"))
+
+	// Routes named after the merge are registered in the parent
+	merged.Get("/logs", handler("logs")).Name("admin.logs")
+	suite.NotNil(router.GetRoute("admin.logs"))
+	settings.Get("/profile", handler("profile")).Name("admin.profile")
+	suite.NotNil(router.GetRoute("admin.profile"))
+
+	// Collision
+	other := NewRouter()
+	other.Get("/", handler("home")).Name("home")
+	suite.Panics(func() {
+		router.Merge("/admin", other)
+	})
+}
+
+func (suite *RouterTestSuite) TestMergeCORS() {
+	router := NewRouter()
+	router.CORS(cors.Default())
+
+	module := NewRouter()
+	module.Get("/hello", helloHandler)
+	merged := router.Merge("/module", module)
+	suite.Same(router.corsOptions, merged.corsOptions)
+	suite.Empty(merged.middleware)
+	suite.Contains(merged.routes[0].methods, http.MethodOptions)
+
+	module = NewRouter()
+	options := cors.Default()
+	module.CORS(options)
+	module.Get("/hello", helloHandler)
+	merged = router.Merge("/other", module)
+	suite.Same(options, merged.corsOptions)
+	suite.Empty(merged.middleware) // CORS middleware already present in parent
+
+	router = NewRouter()
+	merged = router.Merge("/other", module)
+	suite.Same(options, merged.corsOptions)
+	suite.Len(merged.middleware, 1)
+}
+
+func (suite *RouterTestSuite) TestDeadlineExceeded() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	timeout := func(next Handler) Handler {
+		return func(response *Response, request *Request) {
+			ctx, cancel := context.WithTimeout(request.Context(), 50*time.Millisecond)
+			defer cancel()
+			request.SetContext(ctx)
+			next(response, request)
+		}
+	}
+
+	suite.RunServer(func(router *Router) {
+		router.Middleware(timeout)
+		router.Get("/slow", func(response *Response, request *Request) {
+			<-request.Context().Done()
+		})
+		router.Get("/error", func(response *Response, request *Request) {
+			<-request.Context().Done()
+			response.Error(request.Context().Err())
+		})
+		router.Get("/panic", func(response *Response, request *Request) {
+			<-request.Context().Done()
+			panic(request.Context().Err())
+		})
+		router.Get("/fast", func(response *Response, request *Request) {
+			response.Status(http.StatusInternalServerError)
+		})
+	}, func() {
+		for _, route := range []string{"/slow", "/error", "/panic"} {
+			resp, err := suite.Get(route, nil)
+			suite.Nil(err)
+			if err != nil {
+				continue
+			}
+			body := suite.GetBody(resp)
+			resp.Body.Close()
+			suite.Equal(http.StatusGatewayTimeout, resp.StatusCode, route)
+			suite.Equal("{\"error\":{\"code\":504,\"message\":\"The request took too long to be processed.\"}}\n", string(body))
+		}
+
+		resp, err := suite.Get("/fast", nil)
+		suite.Nil(err)
+		if err == nil {
+			resp.Body.Close()
+			suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+		}
+	})
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	warnings := []string{}
+	for _, entry := range logger.entries {
+		suite.NotEqual("error", entry.level, entry.message)
+		if entry.level == "warn" {
+			warnings = append(warnings, entry.message)
+		}
+	}
+	suite.Equal([]string{
+		"GET /slow request deadline exceeded: context deadline exceeded",
+		"GET /error request deadline exceeded: context deadline exceeded",
+		"GET /panic request deadline exceeded: context deadline exceeded",
+	}, warnings)
+}
+
+func (suite *RouterTestSuite) TestOtherDeadlineExceeded() {
+	logger := &testLogger{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+
+	queryTimeout := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		return fmt.Errorf("query: %w", ctx.Err())
+	}
+
+	suite.RunServer(func(router *Router) {
+		router.Get("/error", func(response *Response, request *Request) {
+			response.Error(queryTimeout())
+		})
+		router.Get("/panic", func(response *Response, request *Request) {
+			panic(queryTimeout())
+		})
+	}, func() {
+		for _, route := range []string{"/error", "/panic"} {
+			resp, err := suite.Get(route, nil)
+			suite.Nil(err)
+			if err == nil {
+				resp.Body.Close()
+				suite.Equal(http.StatusInternalServerError, resp.StatusCode, route)
+			}
+		}
+	})
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	for _, entry := range logger.entries {
+		suite.NotEqual("warn", entry.level, entry.message)
+	}
+}
+
 func TestRouterTestSuite(t *testing.T) {
 	RunTest(t, new(RouterTestSuite))
 }
@@ -0,0 +1,70 @@
+package session
+
+// flashKey the session key under which flashed values are stored
+// until the next request.
+const flashKey = "_flash"
+
+// Flash a value in the session. Flashed values are only available
+// on the next request, using "GetFlash()", and are purged afterwards.
+// Useful for one-shot messages in post-redirect-get flows.
+func (s *Session) Flash(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flash, ok := s.values[flashKey].(map[string]interface{})
+	if !ok {
+		flash = make(map[string]interface{})
+	}
+	flash[key] = value
+	s.values[flashKey] = flash
+	if !s.started || s.destroyed {
+		s.start()
+	}
+}
+
+// GetFlash returns the value flashed during the previous request
+// and identified by the given key.
+// Returns nil if the value doesn't exist.
+func (s *Session) GetFlash(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.flash[key]
+}
+
+// HasFlash returns true if a value identified by the given key has
+// been flashed during the previous request.
+func (s *Session) HasFlash(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.flash[key]
+	return ok
+}
+
+// Reflash keeps all the values flashed during the previous request
+// for an additional request.
+func (s *Session) Reflash() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.flash) == 0 {
+		return
+	}
+	flash, ok := s.values[flashKey].(map[string]interface{})
+	if !ok {
+		flash = make(map[string]interface{}, len(s.flash))
+	}
+	for k, v := range s.flash {
+		if _, exists := flash[k]; !exists {
+			flash[k] = v
+		}
+	}
+	s.values[flashKey] = flash
+}
+
+// ageFlash makes the values flashed during the previous request
+// available to the current one and removes them from the session
+// so they are purged once the request has been handled.
+func (s *Session) ageFlash() {
+	if flash, ok := s.values[flashKey].(map[string]interface{}); ok {
+		s.flash = flash
+	}
+	delete(s.values, flashKey)
+}
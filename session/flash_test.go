@@ -0,0 +1,51 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+
+	"goyave.dev/goyave/v3"
+)
+
+func (suite *SessionTestSuite) TestFlash() {
+	store := NewMemoryStore()
+	suite.RunServer(func(router *goyave.Router) {
+		router.Middleware(Middleware(store, Options{AllowInsecure: true}))
+		router.Get("/form", func(response *goyave.Response, request *goyave.Request) {
+			session := FromRequest(request)
+			session.Flash("message", "saved")
+			suite.False(session.HasFlash("message")) // Only available on next request
+			response.TemporaryRedirect("/result")
+		})
+		router.Get("/result", func(response *goyave.Response, request *goyave.Request) {
+			session := FromRequest(request)
+			suite.Nil(session.Get("message"))
+			response.String(http.StatusOK, fmt.Sprintf("%v %v", session.HasFlash("message"), session.GetFlash("message")))
+		})
+		router.Get("/flash", func(response *goyave.Response, request *goyave.Request) {
+			FromRequest(request).Flash("message", "saved")
+			response.Status(http.StatusNoContent)
+		})
+		router.Get("/reflash", func(response *goyave.Response, request *goyave.Request) {
+			FromRequest(request).Reflash()
+			response.String(http.StatusOK, fmt.Sprintf("%v", FromRequest(request).GetFlash("message")))
+		})
+	}, func() {
+		client := suite.newClient()
+
+		status, body := suite.get(client, "/form") // Follows the redirect
+		suite.Equal(http.StatusOK, status)
+		suite.Equal("true saved", body)
+
+		_, body = suite.get(client, "/result")
+		suite.Equal("false <nil>", body)
+
+		suite.get(client, "/flash")
+		_, body = suite.get(client, "/reflash")
+		suite.Equal("saved", body)
+		_, body = suite.get(client, "/result")
+		suite.Equal("true saved", body)
+		_, body = suite.get(client, "/result")
+		suite.Equal("false <nil>", body)
+	})
+}
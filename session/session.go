@@ -0,0 +1,269 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v3"
+)
+
+// ExtraKey the key used to store the session in "request.Extra".
+// Prefer using "session.FromRequest()" to access the session.
+const ExtraKey = "session"
+
+// Options for the session middleware. Zero values are replaced
+// by the defaults.
+type Options struct {
+	// CookieName the name of the cookie holding the session ID.
+	// Defaults to "goyave_session".
+	CookieName string
+
+	// Path of the session cookie. Defaults to "/".
+	Path string
+
+	// Domain of the session cookie. Defaults to the host of the request.
+	Domain string
+
+	// Lifetime duration after which the session expires if there is no
+	// activity. Defaults to two hours.
+	Lifetime time.Duration
+
+	// SameSite attribute of the session cookie. Defaults to "Lax".
+	SameSite http.SameSite
+
+	// AllowInsecure disables the "Secure" flag of the session cookie,
+	// allowing it to be sent over plain HTTP.
+	// Should only be enabled for local development.
+	AllowInsecure bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.CookieName == "" {
+		o.CookieName = "goyave_session"
+	}
+	if o.Path == "" {
+		o.Path = "/"
+	}
+	if o.Lifetime == 0 {
+		o.Lifetime = 2 * time.Hour
+	}
+	if o.SameSite == 0 {
+		o.SameSite = http.SameSiteLaxMode
+	}
+	return o
+}
+
+// Session holds the values associated with a client across
+// multiple requests. Values are persisted in the Store once the
+// request has been handled.
+//
+// Values must be modified before the response is written, so
+// the session cookie can be sent to the client.
+type Session struct {
+	id         string
+	previousID string
+	values     map[string]interface{}
+	flash      map[string]interface{}
+	response   *goyave.Response
+	options    *Options
+	started    bool
+	destroyed  bool
+	mu         sync.RWMutex
+}
+
+// FromRequest returns the session associated with the given request.
+// Returns nil if the session middleware is not applied to the route.
+func FromRequest(request *goyave.Request) *Session {
+	s, _ := request.Extra[ExtraKey].(*Session)
+	return s
+}
+
+// ID returns the session identifier.
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// Get returns the value identified by the given key.
+// Returns nil if the value doesn't exist.
+// Flashed values are not accessible with this method, use "GetFlash()" instead.
+func (s *Session) Get(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// Has returns true if the session contains a value identified by the given key.
+func (s *Session) Has(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.values[key]
+	return ok
+}
+
+// Set a value in the session. Starts the session if needed.
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	if !s.started || s.destroyed {
+		s.start()
+	}
+}
+
+// Delete removes the value identified by the given key from the session.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Clear removes all values from the session.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]interface{})
+}
+
+// Regenerate gives a new ID to the session, keeping its values.
+// Regenerate the session after authentication to prevent session fixation.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previousID == "" && s.started {
+		s.previousID = s.id
+	}
+	s.id = generateID()
+	s.start()
+}
+
+// Destroy removes the session from the store and expires the
+// session cookie.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]interface{})
+	s.destroyed = true
+	s.setCookie(&http.Cookie{
+		Name:     s.options.CookieName,
+		Value:    "",
+		Path:     s.options.Path,
+		Domain:   s.options.Domain,
+		MaxAge:   -1,
+		Secure:   !s.options.AllowInsecure,
+		HttpOnly: true,
+		SameSite: s.options.SameSite,
+	})
+}
+
+// start marks the session as started and sends the session cookie.
+func (s *Session) start() {
+	s.started = true
+	s.destroyed = false
+	s.setCookie(&http.Cookie{
+		Name:     s.options.CookieName,
+		Value:    s.id,
+		Path:     s.options.Path,
+		Domain:   s.options.Domain,
+		MaxAge:   int(s.options.Lifetime.Seconds()),
+		Secure:   !s.options.AllowInsecure,
+		HttpOnly: true,
+		SameSite: s.options.SameSite,
+	})
+}
+
+// setCookie sends the given session cookie, replacing the one
+// previously set during this request, if any.
+func (s *Session) setCookie(cookie *http.Cookie) {
+	header := s.response.Header()
+	prefix := cookie.Name + "="
+	cookies := make([]string, 0, len(header["Set-Cookie"]))
+	for _, c := range header["Set-Cookie"] {
+		if !strings.HasPrefix(c, prefix) {
+			cookies = append(cookies, c)
+		}
+	}
+	header["Set-Cookie"] = cookies
+	s.response.Cookie(cookie)
+}
+
+func (s *Session) save(store Store) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previousID != "" {
+		if err := store.Destroy(s.previousID); err != nil {
+			return err
+		}
+	}
+	if s.destroyed {
+		return store.Destroy(s.id)
+	}
+	if !s.started {
+		return nil
+	}
+	return store.Save(s.id, s.values, s.options.Lifetime)
+}
+
+// Middleware loads the session identified by the session cookie from the
+// given store and makes it available to the next handlers through
+// "session.FromRequest()". The session is persisted in the store once
+// the request has been handled.
+//
+// A session is only started, and its cookie sent, when a value is set.
+// Existing sessions have their lifetime extended on each request.
+//
+// Store errors are considered server errors and make the middleware panic.
+func Middleware(store Store, opts Options) goyave.Middleware {
+	options := opts.withDefaults()
+	return func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			session := &Session{
+				response: response,
+				options:  &options,
+			}
+
+			if cookie, err := request.Request().Cookie(options.CookieName); err == nil && cookie.Value != "" {
+				values, err := store.Load(cookie.Value)
+				if err != nil {
+					panic(err)
+				}
+				if values != nil {
+					session.id = cookie.Value
+					session.values = values
+					session.ageFlash()
+					session.start()
+				}
+			}
+
+			if session.values == nil {
+				session.id = generateID()
+				session.values = make(map[string]interface{})
+			}
+
+			if request.Extra == nil {
+				request.Extra = make(map[string]interface{})
+			}
+			request.Extra[ExtraKey] = session
+
+			next(response, request)
+
+			if err := session.save(store); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+func generateID() string {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
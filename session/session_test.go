@@ -0,0 +1,194 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3"
+)
+
+type SessionTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *SessionTestSuite) newClient() *http.Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		panic(err)
+	}
+	return &http.Client{Jar: jar, Timeout: suite.Timeout()}
+}
+
+func (suite *SessionTestSuite) get(client *http.Client, route string) (int, string) {
+	resp, err := client.Get(goyave.BaseURL() + route)
+	if err != nil {
+		suite.Fail(err.Error())
+		return 0, ""
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, string(suite.GetBody(resp))
+}
+
+func (suite *SessionTestSuite) registerRoutes(store Store) func(*goyave.Router) {
+	return func(router *goyave.Router) {
+		router.Middleware(Middleware(store, Options{AllowInsecure: true}))
+		router.Get("/set/{value}", func(response *goyave.Response, request *goyave.Request) {
+			FromRequest(request).Set("value", request.Params["value"])
+			response.Status(http.StatusNoContent)
+		})
+		router.Get("/get", func(response *goyave.Response, request *goyave.Request) {
+			response.String(http.StatusOK, fmt.Sprintf("%v", FromRequest(request).Get("value")))
+		})
+		router.Get("/regenerate", func(response *goyave.Response, request *goyave.Request) {
+			FromRequest(request).Regenerate()
+			response.Status(http.StatusNoContent)
+		})
+		router.Get("/destroy", func(response *goyave.Response, request *goyave.Request) {
+			FromRequest(request).Destroy()
+			response.Status(http.StatusNoContent)
+		})
+	}
+}
+
+func (suite *SessionTestSuite) TestSession() {
+	store := NewMemoryStore()
+	suite.RunServer(suite.registerRoutes(store), func() {
+		client := suite.newClient()
+
+		status, body := suite.get(client, "/get")
+		suite.Equal(http.StatusOK, status)
+		suite.Equal("<nil>", body)
+		suite.Empty(store.sessions) // Session not started
+
+		status, _ = suite.get(client, "/set/hello")
+		suite.Equal(http.StatusNoContent, status)
+		suite.Len(store.sessions, 1)
+
+		_, body = suite.get(client, "/get")
+		suite.Equal("hello", body)
+
+		// Other client doesn't share the session
+		_, body = suite.get(suite.newClient(), "/get")
+		suite.Equal("<nil>", body)
+
+		var previousID string
+		for id := range store.sessions {
+			previousID = id
+		}
+		suite.get(client, "/regenerate")
+		suite.Len(store.sessions, 1)
+		suite.NotContains(store.sessions, previousID)
+		_, body = suite.get(client, "/get")
+		suite.Equal("hello", body)
+
+		suite.get(client, "/destroy")
+		suite.Empty(store.sessions)
+		_, body = suite.get(client, "/get")
+		suite.Equal("<nil>", body)
+	})
+}
+
+func (suite *SessionTestSuite) TestCookie() {
+	store := NewMemoryStore()
+	request := suite.CreateTestRequest(nil)
+	request.Extra = nil
+	result := suite.Middleware(Middleware(store, Options{}), request, func(response *goyave.Response, r *goyave.Request) {
+		session := FromRequest(r)
+		suite.NotNil(session)
+		suite.NotEmpty(session.ID())
+		session.Set("key", "value")
+		session.Set("key2", "value2")
+		suite.True(session.Has("key"))
+		session.Delete("key")
+		suite.False(session.Has("key"))
+		response.Status(http.StatusNoContent)
+	})
+	result.Body.Close()
+
+	cookies := result.Cookies()
+	if !suite.Len(cookies, 1) {
+		return
+	}
+	cookie := cookies[0]
+	suite.Equal("goyave_session", cookie.Name)
+	suite.True(cookie.Secure)
+	suite.True(cookie.HttpOnly)
+	suite.Equal(http.SameSiteLaxMode, cookie.SameSite)
+	suite.Equal("/", cookie.Path)
+	suite.Equal(7200, cookie.MaxAge)
+
+	values, err := store.Load(cookie.Value)
+	suite.Nil(err)
+	suite.Equal(map[string]interface{}{"key2": "value2"}, values)
+
+	// Unknown session ID is ignored
+	rawRequest := httptest.NewRequest(http.MethodGet, "/", nil)
+	rawRequest.AddCookie(&http.Cookie{Name: "goyave_session", Value: "unknown"})
+	result = suite.Middleware(Middleware(store, Options{}), suite.CreateTestRequest(rawRequest), func(response *goyave.Response, r *goyave.Request) {
+		suite.NotEqual("unknown", FromRequest(r).ID())
+		suite.Nil(FromRequest(r).Get("key2"))
+	})
+	result.Body.Close()
+	suite.Empty(result.Cookies())
+
+	suite.Nil(FromRequest(suite.CreateTestRequest(nil)))
+}
+
+func (suite *SessionTestSuite) TestMemoryStoreExpiry() {
+	store := NewMemoryStore()
+	suite.Nil(store.Save("id", map[string]interface{}{"key": "value"}, time.Hour))
+	suite.Nil(store.Save("expired", map[string]interface{}{"key": "value"}, -time.Second))
+
+	values, err := store.Load("expired")
+	suite.Nil(err)
+	suite.Nil(values)
+	suite.NotContains(store.sessions, "expired")
+
+	values, err = store.Load("id")
+	suite.Nil(err)
+	values["key"] = "modified" // Store keeps a copy
+	values, _ = store.Load("id")
+	suite.Equal("value", values["key"])
+
+	suite.Nil(store.Save("expired", map[string]interface{}{}, -time.Second))
+	store.lastSweep = time.Now().Add(-sweepInterval)
+	suite.Nil(store.Save("other", map[string]interface{}{}, time.Hour))
+	suite.NotContains(store.sessions, "expired")
+	suite.Len(store.sessions, 2)
+
+	suite.Nil(store.Destroy("id"))
+	suite.Nil(store.Destroy("id"))
+	values, _ = store.Load("id")
+	suite.Nil(values)
+}
+
+func (suite *SessionTestSuite) TestMemoryStoreExpiryFakeClock() {
+	clock := suite.UseFakeClock(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+	defer suite.RestoreClock()
+
+	store := NewMemoryStore()
+	suite.Nil(store.Save("id", map[string]interface{}{"key": "value"}, time.Hour))
+	suite.Nil(store.Save("other", map[string]interface{}{"key": "value"}, 2*time.Hour))
+
+	clock.Advance(time.Hour)
+	values, _ := store.Load("id")
+	suite.Equal("value", values["key"])
+
+	clock.Advance(time.Second)
+	values, _ = store.Load("id")
+	suite.Nil(values)
+
+	// Sweep on save
+	clock.Advance(time.Hour)
+	suite.Nil(store.Save("new", map[string]interface{}{}, time.Hour))
+	suite.NotContains(store.sessions, "other")
+	suite.Len(store.sessions, 1)
+}
+
+func TestSessionSuite(t *testing.T) {
+	goyave.RunTest(t, new(SessionTestSuite))
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v3/clock"
+)
+
+// Store is the persistence layer of sessions. Implement this interface
+// to store sessions in a database, a cache such as Redis, etc.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Load returns the values of the session identified by the given ID.
+	// Returns nil and no error if the session doesn't exist or has expired.
+	Load(id string) (map[string]interface{}, error)
+
+	// Save persists the values of the session identified by the given ID.
+	// The session expires after the given lifetime.
+	Save(id string, values map[string]interface{}, lifetime time.Duration) error
+
+	// Destroy removes the session identified by the given ID.
+	// Destroying a session that doesn't exist is not an error.
+	Destroy(id string) error
+}
+
+type memorySession struct {
+	values    map[string]interface{}
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store keeping sessions in memory.
+// Sessions are lost when the application stops and are not shared between
+// instances, so this store is suitable for development, testing and
+// single-instance deployments only.
+type MemoryStore struct {
+	sessions  map[string]*memorySession
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// sweepInterval the minimum interval between two removals
+// of the expired sessions of a MemoryStore.
+const sweepInterval = time.Minute
+
+// NewMemoryStore create a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:  make(map[string]*memorySession),
+		lastSweep: clock.Now(),
+	}
+}
+
+// Load returns the values of the session identified by the given ID.
+// Returns nil if the session doesn't exist or has expired.
+func (s *MemoryStore) Load(id string) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	if clock.Now().After(session.expiresAt) {
+		delete(s.sessions, id)
+		return nil, nil
+	}
+	return copyValues(session.values), nil
+}
+
+// Save persists a copy of the given session values.
+// Expired sessions are removed from time to time when saving.
+func (s *MemoryStore) Save(id string, values map[string]interface{}, lifetime time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := clock.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, session := range s.sessions {
+			if now.After(session.expiresAt) {
+				delete(s.sessions, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.sessions[id] = &memorySession{
+		values:    copyValues(values),
+		expiresAt: now.Add(lifetime),
+	}
+	return nil
+}
+
+// Destroy removes the session identified by the given ID.
+func (s *MemoryStore) Destroy(id string) error {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	return nil
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	cpy := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		cpy[k] = v
+	}
+	return cpy
+}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -24,8 +27,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	testify "github.com/stretchr/testify/suite"
+	"goyave.dev/goyave/v3/clock"
 	"goyave.dev/goyave/v3/config"
-	"goyave.dev/goyave/v3/lang"
 )
 
 // ITestSuite is an extension of testify's Suite for
@@ -62,7 +65,13 @@ type TestSuite struct {
 	testify.Suite
 	httpClient *http.Client
 	timeout    time.Duration // Timeout for functional tests
+	tx         *gorm.DB
 	mu         sync.Mutex
+	dirty      bool // Database populated outside of a transaction
+
+	transactional      bool
+	previousHTTPClient *http.Client
+	httpStubbed        bool
 }
 
 var _ ITestSuite = (*TestSuite)(nil) // implements ITestSuite
@@ -84,6 +93,120 @@ func (s *TestSuite) SetTimeout(timeout time.Duration) {
 	s.mu.Unlock()
 }
 
+// Transactional returns true if each test of the suite runs
+// inside a database transaction rolled back after the test.
+func (s *TestSuite) Transactional() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transactional
+}
+
+// SetTransactional make each test of the suite run inside a database
+// transaction rolled back after the test, so the database is reset
+// without deleting records (see "ClearDatabase()").
+// Call this before running the suite:
+//
+//  suite := new(MyTestSuite)
+//  suite.SetTransactional(true)
+//  goyave.RunTest(t, suite)
+//
+// If your suite defines "SetupTest()" or "TearDownTest()", make sure they
+// call "suite.TestSuite.SetupTest()" and "suite.TestSuite.TearDownTest()".
+func (s *TestSuite) SetTransactional(transactional bool) {
+	s.mu.Lock()
+	s.transactional = transactional
+	s.mu.Unlock()
+}
+
+// SetupTest begins a database transaction if the suite is transactional.
+func (s *TestSuite) SetupTest() {
+	if s.Transactional() {
+		s.BeginTransaction()
+	}
+}
+
+// TearDownTest rolls back the database transaction started
+// in "SetupTest()", if any. If the database was populated using
+// "Seed()" or "Factory()" outside of a transaction, it is cleared
+// using "ClearDatabase()".
+func (s *TestSuite) TearDownTest() {
+	s.RollbackTransaction()
+	s.mu.Lock()
+	dirty := s.dirty
+	s.dirty = false
+	s.mu.Unlock()
+	if dirty {
+		s.ClearDatabase()
+	}
+}
+
+// Seed executes the database seeders registered with the given names
+// (see "database.RegisterSeeder()").
+//
+// The seeded records are removed automatically after the test, either by
+// rolling back the test transaction or using "ClearDatabase()".
+func (s *TestSuite) Seed(seeders ...string) {
+	s.markDirty()
+	database.Seed(seeders...)
+}
+
+// Factory returns a new factory for the given model, using the generator
+// registered with "database.RegisterFactory()".
+//
+//  users := suite.Factory(&model.User{}).Save(5).([]*model.User)
+//
+// The records saved using this factory are removed automatically after
+// the test, either by rolling back the test transaction or using "ClearDatabase()".
+func (s *TestSuite) Factory(model interface{}) *database.Factory {
+	s.markDirty()
+	return database.GetFactory(model)
+}
+
+func (s *TestSuite) markDirty() {
+	s.mu.Lock()
+	if s.tx == nil {
+		s.dirty = true
+	}
+	s.mu.Unlock()
+}
+
+// BeginTransaction begins a database transaction and makes it the connection
+// returned by "database.GetConnection()" until "RollbackTransaction()"
+// is called. Request handlers executed in the meantime, including with
+// "RunServer()", use this transaction as well. Returns the existing
+// transaction if one was already started.
+func (s *TestSuite) BeginTransaction() *gorm.DB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tx != nil {
+		return s.tx
+	}
+	tx := database.GetConnection().Begin()
+	if tx.Error != nil {
+		panic(tx.Error)
+	}
+	database.SetConnection(tx)
+	s.tx = tx
+	return tx
+}
+
+// RollbackTransaction rolls back the transaction started with
+// "BeginTransaction()" and restores the global database connection.
+// Does nothing if no transaction was started.
+func (s *TestSuite) RollbackTransaction() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tx == nil {
+		return
+	}
+	database.SetConnection(nil)
+	err := s.tx.Rollback().Error
+	s.tx = nil
+	if err != nil {
+		panic(err)
+	}
+}
+
 // CreateTestRequest create a "goyave.Request" from the given raw request.
 // This function is aimed at making it easier to unit test Requests.
 //
@@ -106,6 +229,7 @@ func (s *TestSuite) CreateTestRequest(rawRequest *http.Request) *Request {
 		Lang:        "en-US",
 		Params:      map[string]string{},
 		Extra:       map[string]interface{}{},
+		startTime:   clock.Now(),
 	}
 }
 
@@ -189,6 +313,110 @@ func (s *TestSuite) Middleware(middleware Middleware, request *Request, procedur
 	return recorder.Result()
 }
 
+// UseFakeClock replace the global clock with a fake clock set at the
+// given time and returns it. Time-dependent features, such as rate limiting,
+// sessions or JWT expiry, then only see time passing when the fake clock
+// is advanced, making tests deterministic and free of real sleeps.
+//
+//  clock := suite.UseFakeClock(time.Now())
+//  defer suite.RestoreClock()
+//  clock.Advance(time.Minute)
+func (s *TestSuite) UseFakeClock(now time.Time) *clock.Fake {
+	fake := clock.NewFake(now)
+	clock.Set(fake)
+	return fake
+}
+
+// RestoreClock restore the real global clock after a call to "UseFakeClock()".
+func (s *TestSuite) RestoreClock() {
+	clock.Reset()
+}
+
+// HTTPStub a "http.RoundTripper" returning canned responses instead of
+// executing the requests, for hermetic tests of code calling external services.
+// Requests without a matching stub fail with an error.
+type HTTPStub struct {
+	handlers map[string]http.HandlerFunc
+	mu       sync.Mutex
+}
+
+// Handle register a handler writing the response to requests with the given
+// method and URL. If the given URL has no query, it matches all the queries.
+func (s *HTTPStub) Handle(method, url string, handler http.HandlerFunc) *HTTPStub {
+	s.mu.Lock()
+	s.handlers[method+" "+url] = handler
+	s.mu.Unlock()
+	return s
+}
+
+// Respond register a canned response with the given status and body to
+// requests with the given method and URL. If the given URL has no query,
+// it matches all the queries.
+func (s *HTTPStub) Respond(method, url string, status int, body string) *HTTPStub {
+	return s.Handle(method, url, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if _, err := io.WriteString(w, body); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// RoundTrip executes the handler matching the request.
+func (s *HTTPStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	u := *req.URL
+	s.mu.Lock()
+	handler, ok := s.handlers[req.Method+" "+u.String()]
+	if !ok {
+		u.RawQuery = ""
+		handler, ok = s.handlers[req.Method+" "+u.String()]
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("No HTTP stub registered for %s %s", req.Method, req.URL)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// StubHTTPClient replace the client returned by "HTTPClient()" with a client
+// using a stub transport and returns the stub, so tests can register canned
+// responses for the external services called by the application.
+// The stubbed client keeps the other settings of the original client.
+//
+//  stub := suite.StubHTTPClient()
+//  defer suite.RestoreHTTPClient()
+//  stub.Respond("GET", "https://api.example.org/users", http.StatusOK, `[{"id":1}]`)
+func (s *TestSuite) StubHTTPClient() *HTTPStub {
+	stub := &HTTPStub{handlers: map[string]http.HandlerFunc{}}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := setHTTPTransport(stub)
+	if !s.httpStubbed {
+		s.previousHTTPClient = previous
+		s.httpStubbed = true
+	}
+	return stub
+}
+
+// RestoreHTTPClient restore the client returned by "HTTPClient()"
+// after a call to "StubHTTPClient()".
+func (s *TestSuite) RestoreHTTPClient() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpStubbed {
+		SetHTTPClient(s.previousHTTPClient)
+		s.previousHTTPClient = nil
+		s.httpStubbed = false
+	}
+}
+
 // Get execute a GET request on the given route.
 // Headers are optional.
 func (s *TestSuite) Get(route string, headers map[string]string) (*http.Response, error) {
@@ -221,11 +449,18 @@ func (s *TestSuite) Delete(route string, headers map[string]string, body io.Read
 
 // Request execute a request on the given route.
 // Headers and body are optional.
+//
+// The request is sent to the address the server is actually bound to, so
+// it works even if the port is assigned by the OS ("server.port" set to 0).
+// The "Host" header is the host of "BaseURL()".
 func (s *TestSuite) Request(method, route string, headers map[string]string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, BaseURL()+route, body)
+	req, err := http.NewRequest(method, serverURL()+route, body)
 	if err != nil {
 		return nil, err
 	}
+	if base, err := url.Parse(BaseURL()); err == nil {
+		req.Host = base.Host
+	}
 	req.Close = true
 	for k, v := range headers {
 		req.Header.Set(k, v)
@@ -233,6 +468,21 @@ func (s *TestSuite) Request(method, route string, headers map[string]string, bod
 	return s.getHTTPClient().Do(req)
 }
 
+// serverURL returns the base URL of the running server, built from the
+// address its listener is bound to. Unspecified hosts ("0.0.0.0" or "::")
+// are replaced with the loopback address. Returns "BaseURL()" if the
+// server is not running.
+func serverURL() string {
+	host, port, err := net.SplitHostPort(Address())
+	if err != nil {
+		return BaseURL()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return config.GetString("server.protocol") + "://" + net.JoinHostPort(host, port)
+}
+
 // GetBody read the whole body of a response.
 // If read failed, test fails and return empty byte slice.
 func (s *TestSuite) GetBody(response *http.Response) []byte {
@@ -254,6 +504,25 @@ func (s *TestSuite) GetJSONBody(response *http.Response, data interface{}) error
 	return nil
 }
 
+// AssertHeader asserts the given response has a header with the given name
+// and value. If the header has multiple values, only the first one is compared.
+// Fails the test and returns false if the header is missing or doesn't match.
+func (s *TestSuite) AssertHeader(response *http.Response, name, expected string) bool {
+	if !s.AssertHeaderExists(response, name) {
+		return false
+	}
+	return s.Equal(expected, response.Header.Get(name), fmt.Sprintf("Response header %q doesn't match", name))
+}
+
+// AssertHeaderExists asserts the given response has a header with the given name.
+// Fails the test and returns false if the header is missing.
+func (s *TestSuite) AssertHeaderExists(response *http.Response, name string) bool {
+	if _, ok := response.Header[http.CanonicalHeaderKey(name)]; !ok {
+		return s.Fail(fmt.Sprintf("Response header %q is missing", name))
+	}
+	return true
+}
+
 // CreateTestFiles create a slice of "filesystem.File" from the given paths.
 // Files are passed to a temporary http request and parsed as Multipart form,
 // to reproduce the way files are obtained in real scenarios.
@@ -370,8 +639,9 @@ func RunTest(t *testing.T, suite ITestSuite) bool {
 		}
 	}
 	defer config.Clear()
-	lang.LoadDefault()
-	lang.LoadAllAvailableLanguages()
+	if err := loadLanguages(); err != nil {
+		return assert.Fail(t, "Invalid default language", err)
+	}
 
 	if config.GetBool("database.autoMigrate") && config.GetString("database.connection") != "none" {
 		database.Migrate()
@@ -3,9 +3,12 @@ package goyave
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -34,6 +37,14 @@ type FailingTestSuite struct {
 	TestSuite
 }
 
+type TransactionalTestSuite struct {
+	TestSuite
+}
+
+type SeedingTestSuite struct {
+	TestSuite
+}
+
 type ConcurrentTestSuite struct {
 	res *int
 	TestSuite
@@ -225,6 +236,135 @@ func (suite *CustomTestSuite) TestRequests() {
 	})
 }
 
+func (suite *CustomTestSuite) TestRequestDynamicPort() {
+	config.Set("server.port", 0)
+	config.Set("server.domain", "example.org")
+	defer func() {
+		config.Set("server.port", 1235)
+		config.Set("server.domain", "")
+	}()
+
+	suite.Equal("http://example.org:0", serverURL())
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/host", func(response *Response, request *Request) {
+			response.String(http.StatusOK, request.Request().Host)
+		})
+	}, func() {
+		_, port, err := net.SplitHostPort(Address())
+		suite.Nil(err)
+		suite.Equal("http://127.0.0.1:"+port, serverURL())
+
+		resp, err := suite.Get("/host", nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusOK, resp.StatusCode)
+			suite.Equal("example.org:"+port, string(suite.GetBody(resp)))
+		}
+	})
+}
+
+func (suite *CustomTestSuite) TestAssertHeader() {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "application/json")
+	recorder.Header().Set("Location", "/users/1")
+	recorder.Header().Set("Cache-Control", "")
+	resp := recorder.Result()
+	defer resp.Body.Close()
+
+	suite.True(suite.AssertHeader(resp, "Content-Type", "application/json"))
+	suite.True(suite.AssertHeader(resp, "location", "/users/1"))
+	suite.True(suite.AssertHeaderExists(resp, "Cache-Control"))
+
+	t := suite.T()
+	mockT := new(testing.T)
+	suite.SetT(mockT)
+	mismatched := suite.AssertHeader(resp, "Location", "/users/2")
+	suite.SetT(t)
+	suite.False(mismatched)
+	suite.True(mockT.Failed())
+
+	mockT = new(testing.T)
+	suite.SetT(mockT)
+	absent := suite.AssertHeader(resp, "ETag", "abc")
+	absentExists := suite.AssertHeaderExists(resp, "ETag")
+	suite.SetT(t)
+	suite.False(absent)
+	suite.False(absentExists)
+	suite.True(mockT.Failed())
+}
+
+func (suite *CustomTestSuite) TestStubHTTPClient() {
+	stub := suite.StubHTTPClient()
+	defer suite.RestoreHTTPClient()
+	stub.Respond("GET", "https://api.example.org/users", http.StatusOK, `[{"id":1,"name":"John Doe"}]`).
+		Handle("POST", "https://api.example.org/users?notify=true", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("Location", "https://api.example.org/users/2")
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		})
+
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/users", func(response *Response, request *Request) {
+			resp, err := HTTPClient().Get("https://api.example.org/users?page=1")
+			if err != nil {
+				response.String(http.StatusBadGateway, err.Error())
+				return
+			}
+			defer resp.Body.Close()
+			users := []map[string]interface{}{}
+			if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+				panic(err)
+			}
+			response.String(http.StatusOK, users[0]["name"].(string))
+		})
+		router.Route("POST", "/users", func(response *Response, request *Request) {
+			resp, err := HTTPClient().Post("https://api.example.org/users?notify=true", "application/json", strings.NewReader(`{"name":"Jane"}`))
+			if err != nil {
+				response.String(http.StatusBadGateway, err.Error())
+				return
+			}
+			defer resp.Body.Close()
+			response.Header().Set("Location", resp.Header.Get("Location"))
+			response.Status(resp.StatusCode)
+			io.Copy(response, resp.Body)
+		})
+		router.Route("GET", "/unknown", func(response *Response, request *Request) {
+			_, err := HTTPClient().Get("https://api.example.org/unknown")
+			response.String(http.StatusBadGateway, err.Error())
+		})
+	}, func() {
+		resp, err := suite.Get("/users", nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusOK, resp.StatusCode)
+			suite.Equal("John Doe", string(suite.GetBody(resp)))
+		}
+
+		resp, err = suite.Post("/users", nil, nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusCreated, resp.StatusCode)
+			suite.AssertHeader(resp, "Location", "https://api.example.org/users/2")
+			suite.Equal(`{"name":"Jane"}`, string(suite.GetBody(resp)))
+		}
+
+		resp, err = suite.Get("/unknown", nil)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusBadGateway, resp.StatusCode)
+			suite.Equal("Get \"https://api.example.org/unknown\": No HTTP stub registered for GET https://api.example.org/unknown", string(suite.GetBody(resp)))
+		}
+	})
+
+	suite.RestoreHTTPClient()
+	suite.NotSame(stub, HTTPClient().Transport)
+}
+
 func (suite *CustomTestSuite) TestJSON() {
 	suite.RunServer(func(router *Router) {
 		router.Route("GET", "/invalid", genericHandler("get"))
@@ -355,6 +495,66 @@ func (suite *CustomTestSuite) TestMultipartForm() {
 	})
 }
 
+func (suite *CustomTestSuite) TestMultipartFileAccessors() {
+	paths := []string{"test-file-1.txt", "test-file-2.txt"}
+	for i, p := range paths {
+		if err := ioutil.WriteFile(p, []byte(fmt.Sprintf("content %d", i+1)), 0644); err != nil {
+			panic(err)
+		}
+		defer filesystem.Delete(p)
+	}
+
+	suite.RunServer(func(router *Router) {
+		router.Route("POST", "/post", func(response *Response, request *Request) {
+			file, ok := request.FirstFile("file")
+			if !ok {
+				response.Status(http.StatusUnprocessableEntity)
+				return
+			}
+			names := []string{}
+			for _, f := range request.Files("file") {
+				names = append(names, f.Header.Filename)
+			}
+			_, missingOk := request.FirstFile("missing")
+			response.JSON(http.StatusOK, map[string]interface{}{
+				"first":        file.Header.Filename,
+				"names":        names,
+				"missing":      missingOk,
+				"missingFiles": len(request.Files("missing")),
+			})
+		})
+	}, func() {
+		upload := func(paths ...string) map[string]interface{} {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			for _, p := range paths {
+				suite.WriteFile(writer, p, "file", filepath.Base(p))
+			}
+			if err := writer.Close(); err != nil {
+				panic(err)
+			}
+			resp, err := suite.Post("/post", map[string]string{"Content-Type": writer.FormDataContentType()}, body)
+			suite.Nil(err)
+			json := map[string]interface{}{}
+			if err == nil {
+				suite.Equal(http.StatusOK, resp.StatusCode)
+				suite.Nil(suite.GetJSONBody(resp, &json))
+			}
+			return json
+		}
+
+		json := upload(paths[0])
+		suite.Equal("test-file-1.txt", json["first"])
+		suite.Equal([]interface{}{"test-file-1.txt"}, json["names"])
+		suite.Equal(false, json["missing"])
+		suite.Equal(0.0, json["missingFiles"])
+
+		json = upload(paths...)
+		suite.Equal("test-file-1.txt", json["first"])
+		suite.Equal([]interface{}{"test-file-1.txt", "test-file-2.txt"}, json["names"])
+	})
+}
+
 func (suite *CustomTestSuite) TestClearDatabase() {
 	config.Set("database.connection", "mysql")
 	db := database.GetConnection()
@@ -479,6 +679,149 @@ func TestTestSuiteFail(t *testing.T) {
 	assert.True(t, mockT.Failed())
 }
 
+func (suite *TransactionalTestSuite) SetupSuite() {
+	config.Set("database.connection", "mysql")
+	database.GetConnection().AutoMigrate(&TestModel{})
+}
+
+func (suite *TransactionalTestSuite) TestACreate() {
+	suite.NotNil(suite.tx)
+	suite.Same(suite.tx, database.GetConnection())
+	for i := 0; i < 5; i++ {
+		database.GetConnection().Create(&TestModel{Name: fmt.Sprintf("Test %d", i)})
+	}
+	count := int64(0)
+	database.GetConnection().Model(&TestModel{}).Count(&count)
+	suite.Equal(int64(5), count)
+}
+
+func (suite *TransactionalTestSuite) TestBCreateWithServer() {
+	count := int64(0)
+	database.GetConnection().Model(&TestModel{}).Count(&count)
+	suite.Equal(int64(0), count) // Nothing leaked from the previous test
+
+	suite.RunServer(func(router *Router) {
+		router.Route("POST", "/model", func(response *Response, request *Request) {
+			database.GetConnection().Create(&TestModel{Name: "created"})
+			response.Status(http.StatusCreated)
+		})
+	}, func() {
+		resp, err := suite.Post("/model", nil, nil)
+		suite.Nil(err)
+		if err == nil {
+			resp.Body.Close()
+			suite.Equal(http.StatusCreated, resp.StatusCode)
+		}
+	})
+
+	database.GetConnection().Model(&TestModel{}).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *TransactionalTestSuite) TestCNoLeak() {
+	count := int64(0)
+	database.GetConnection().Model(&TestModel{}).Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+func (suite *TransactionalTestSuite) TearDownSuite() {
+	suite.Nil(suite.tx)
+	count := int64(0)
+	db := database.GetConnection()
+	db.Model(&TestModel{}).Count(&count)
+	suite.Equal(int64(0), count)
+	db.Migrator().DropTable(&TestModel{})
+	config.Set("database.connection", "none")
+}
+
+func TestTransactionalTestSuite(t *testing.T) {
+	suite := new(TransactionalTestSuite)
+	suite.SetTransactional(true)
+	RunTest(t, suite)
+}
+
+func (suite *CustomTestSuite) TestBeginRollbackTransactionNoop() {
+	suite.False(suite.Transactional())
+	suite.Nil(suite.tx)
+	suite.RollbackTransaction() // No transaction, no effect
+	suite.Nil(suite.tx)
+}
+
+func (suite *SeedingTestSuite) SetupSuite() {
+	config.Set("database.connection", "mysql")
+	database.GetConnection().AutoMigrate(&TestModel{})
+	database.RegisterModel(&TestModel{})
+	database.RegisterFactory(&TestModel{}, func() interface{} {
+		return &TestModel{Name: "generated"}
+	})
+	database.RegisterSeeder("models", func() {
+		database.GetFactory(&TestModel{}).Save(3)
+	})
+}
+
+func (suite *SeedingTestSuite) countModels() int64 {
+	count := int64(0)
+	database.GetConnection().Model(&TestModel{}).Count(&count)
+	return count
+}
+
+func (suite *SeedingTestSuite) TestASeedListEndpoint() {
+	suite.Seed("models")
+	suite.Factory(&TestModel{}).Override(&TestModel{Name: "override"}).Save(1)
+
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/models", func(response *Response, request *Request) {
+			models := []*TestModel{}
+			if err := database.GetConnection().Order("id").Find(&models).Error; err != nil {
+				panic(err)
+			}
+			response.JSON(http.StatusOK, models)
+		})
+	}, func() {
+		resp, err := suite.Get("/models", nil)
+		suite.Nil(err)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+		suite.Equal(http.StatusOK, resp.StatusCode)
+
+		models := []*TestModel{}
+		if err := suite.GetJSONBody(resp, &models); err == nil {
+			suite.Len(models, 4)
+			for _, m := range models[:3] {
+				suite.Equal("generated", m.Name)
+			}
+			suite.Equal("override", models[3].Name)
+		}
+	})
+}
+
+func (suite *SeedingTestSuite) TestBCleanedUp() {
+	suite.Equal(int64(0), suite.countModels())
+	suite.Seed("models")
+	suite.Equal(int64(3), suite.countModels())
+}
+
+func (suite *SeedingTestSuite) TearDownSuite() {
+	suite.Equal(int64(0), suite.countModels())
+	database.GetConnection().Migrator().DropTable(&TestModel{})
+	database.ClearRegisteredModels()
+	database.ClearFactories()
+	database.ClearSeeders()
+	config.Set("database.connection", "none")
+}
+
+func TestSeedingTestSuite(t *testing.T) {
+	RunTest(t, new(SeedingTestSuite))
+}
+
+func TestSeedingTransactionalTestSuite(t *testing.T) {
+	suite := new(SeedingTestSuite)
+	suite.SetTransactional(true)
+	RunTest(t, suite)
+}
+
 func (suite *MigratingTestSuite) TearDownSuite() {
 	suite.ClearDatabaseTables()
 }
@@ -0,0 +1,108 @@
+package goyave
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+// configureTLS loads the certificates defined by the "server.tls.cert"
+// and "server.tls.key" config entries, as well as the additional
+// certificates defined by the "server.tls.certificates" config entry,
+// and adds them to the TLS config of the given server.
+//
+// Additional certificates are selected using the server name sent by the
+// client (SNI). Certificates having a "host" are served for this hostname
+// only ("*.example.org" matches any subdomain of "example.org"). The other
+// certificates are selected using the names they are valid for.
+// The certificate defined by "server.tls.cert" is used as fallback.
+//
+// Client authentication (mutual TLS) is configured as well.
+func configureTLS(server *http.Server) error {
+	if server.TLSConfig == nil {
+		server.TLSConfig = &tls.Config{}
+	}
+	tlsConfig := server.TLSConfig
+	if err := configureClientAuth(tlsConfig); err != nil {
+		return err
+	}
+
+	if config.Has("server.tls.cert") || config.Has("server.tls.key") {
+		cert, err := tls.LoadX509KeyPair(config.GetString("server.tls.cert"), config.GetString("server.tls.key"))
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+
+	if !config.Has("server.tls.certificates") {
+		return nil
+	}
+
+	hosts := make(map[string]*tls.Certificate)
+	for _, e := range config.Get("server.tls.certificates").([]interface{}) {
+		entry := e.(map[string]interface{})
+		cert, err := tls.LoadX509KeyPair(entry["cert"].(string), entry["key"].(string))
+		if err != nil {
+			return err
+		}
+		if host, ok := entry["host"].(string); ok && host != "" {
+			hosts[strings.ToLower(host)] = &cert
+			continue
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+
+	if len(hosts) > 0 && tlsConfig.GetCertificate == nil {
+		tlsConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			name := strings.ToLower(hello.ServerName)
+			if cert, ok := hosts[name]; ok {
+				return cert, nil
+			}
+			if i := strings.Index(name, "."); i != -1 {
+				if cert, ok := hosts["*"+name[i:]]; ok {
+					return cert, nil
+				}
+			}
+			return nil, nil // Fall back to "tls.Config.Certificates"
+		}
+	}
+	return nil
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":             tls.NoClientCert,
+	"request":          tls.RequestClientCert,
+	"require":          tls.RequireAnyClientCert,
+	"verifyIfGiven":    tls.VerifyClientCertIfGiven,
+	"requireAndVerify": tls.RequireAndVerifyClientCert,
+}
+
+// configureClientAuth sets the client authentication policy (mutual TLS)
+// of the given TLS config, using the "server.tls.clientAuth" config entry.
+// The certificate authorities used to verify client certificates are
+// loaded from the PEM file defined by the "server.tls.clientCA" config entry.
+// If no file is given, the system's certificate pool is used.
+func configureClientAuth(tlsConfig *tls.Config) error {
+	if clientAuth := config.GetString("server.tls.clientAuth"); clientAuth != "none" {
+		tlsConfig.ClientAuth = clientAuthTypes[clientAuth]
+	}
+	if !config.Has("server.tls.clientCA") {
+		return nil
+	}
+	pem, err := ioutil.ReadFile(config.GetString("server.tls.clientCA"))
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("No valid certificate found in client CA file %q", config.GetString("server.tls.clientCA"))
+	}
+	tlsConfig.ClientCAs = pool
+	return nil
+}
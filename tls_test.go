@@ -0,0 +1,257 @@
+package goyave
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goyave.dev/goyave/v3/config"
+)
+
+type TLSTestSuite struct {
+	TestSuite
+	dir string
+}
+
+func (suite *TLSTestSuite) SetupSuite() {
+	dir, err := ioutil.TempDir("", "goyave-tls")
+	if err != nil {
+		panic(err)
+	}
+	suite.dir = dir
+}
+
+func (suite *TLSTestSuite) TearDownSuite() {
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *TLSTestSuite) SetupTest() {
+	config.Set("server.tls.key", "resources/server.key")
+	config.Set("server.tls.cert", "resources/server.crt")
+	config.Set("server.protocol", "https")
+	protocol = "https"
+}
+
+func (suite *TLSTestSuite) TearDownTest() {
+	config.Set("server.protocol", "http")
+	protocol = "http"
+}
+
+// generateCertificate creates a self-signed server certificate for the given
+// host and returns the paths to the certificate and key files.
+func (suite *TLSTestSuite) generateCertificate(host string) (string, string) {
+	return suite.generateCertificateWithUsage(host, x509.ExtKeyUsageServerAuth)
+}
+
+func (suite *TLSTestSuite) generateCertificateWithUsage(host string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+
+		// Self-signed client certificates are used as their own CA
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	certPath := filepath.Join(suite.dir, host+".crt")
+	keyPath := filepath.Join(suite.dir, host+".key")
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		panic(err)
+	}
+	return certPath, keyPath
+}
+
+// servedCertificate returns the subject common name of the certificate
+// served by the test server for the given server name.
+func (suite *TLSTestSuite) servedCertificate(serverName string) string {
+	client := &http.Client{
+		Timeout: suite.Timeout(),
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				ServerName:         serverName,
+			},
+		},
+	}
+	resp, err := client.Get(BaseURL() + "/hello")
+	if err != nil {
+		suite.Fail(err.Error())
+		return ""
+	}
+	defer resp.Body.Close()
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	return resp.TLS.PeerCertificates[0].Subject.CommonName
+}
+
+func (suite *TLSTestSuite) TestSNI() {
+	certA, keyA := suite.generateCertificate("a.example.org")
+	certB, keyB := suite.generateCertificate("b.example.org")
+	certC, keyC := suite.generateCertificate("wildcard.example.net")
+	config.Set("server.tls.certificates", []interface{}{
+		map[string]interface{}{"host": "a.example.org", "cert": certA, "key": keyA},
+		map[string]interface{}{"cert": certB, "key": keyB},
+		map[string]interface{}{"host": "*.example.net", "cert": certC, "key": keyC},
+	})
+	defer config.Set("server.tls.certificates", nil)
+
+	fallback, err := tls.LoadX509KeyPair("resources/server.crt", "resources/server.key")
+	if err != nil {
+		panic(err)
+	}
+	leaf, err := x509.ParseCertificate(fallback.Certificate[0])
+	if err != nil {
+		panic(err)
+	}
+
+	suite.RunServer(func(router *Router) {
+		router.Route("GET", "/hello", helloHandler)
+	}, func() {
+		suite.Equal("a.example.org", suite.servedCertificate("a.example.org"))
+		suite.Equal("a.example.org", suite.servedCertificate("A.EXAMPLE.ORG"))
+		suite.Equal("b.example.org", suite.servedCertificate("b.example.org"))
+		suite.Equal("wildcard.example.net", suite.servedCertificate("sub.example.net"))
+		suite.Equal(leaf.Subject.CommonName, suite.servedCertificate("unknown.example.org"))
+	})
+}
+
+func (suite *TLSTestSuite) TestClientCertificate() {
+	clientCert, clientKey := suite.generateCertificateWithUsage("client", x509.ExtKeyUsageClientAuth)
+	otherCert, otherKey := suite.generateCertificateWithUsage("other", x509.ExtKeyUsageClientAuth)
+	config.Set("server.tls.clientAuth", "requireAndVerify")
+	config.Set("server.tls.clientCA", clientCert)
+	defer func() {
+		config.Set("server.tls.clientAuth", "none")
+		config.Set("server.tls.clientCA", nil)
+	}()
+
+	request := func(cert, key string) (*http.Response, error) {
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		if cert != "" {
+			certificate, err := tls.LoadX509KeyPair(cert, key)
+			if err != nil {
+				panic(err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{certificate}
+		}
+		client := &http.Client{
+			Timeout:   suite.Timeout(),
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		}
+		return client.Get(BaseURL() + "/whoami")
+	}
+
+	suite.RunServer(func(router *Router) {
+		router.Get("/whoami", func(response *Response, request *Request) {
+			suite.NotNil(request.TLS())
+			certificates := request.ClientCertificates()
+			if suite.Len(certificates, 1) {
+				response.String(http.StatusOK, certificates[0].Subject.CommonName)
+			}
+		})
+	}, func() {
+		resp, err := request(clientCert, clientKey)
+		suite.Nil(err)
+		if err == nil {
+			defer resp.Body.Close()
+			suite.Equal(http.StatusOK, resp.StatusCode)
+			suite.Equal("client", string(suite.GetBody(resp)))
+		}
+
+		// Certificate not signed by the client CA
+		resp, err = request(otherCert, otherKey)
+		suite.NotNil(err)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		// No certificate
+		resp, err = request("", "")
+		suite.NotNil(err)
+		if err == nil {
+			resp.Body.Close()
+		}
+	})
+}
+
+func (suite *TLSTestSuite) TestClientCertificateAccessors() {
+	request := suite.CreateTestRequest(nil)
+	suite.Nil(request.TLS())
+	suite.Nil(request.ClientCertificates())
+
+	state := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+	request.httpRequest.TLS = state
+	suite.Same(state, request.TLS())
+	suite.Equal(state.PeerCertificates, request.ClientCertificates())
+}
+
+func (suite *TLSTestSuite) TestInvalidClientCA() {
+	config.Set("server.tls.clientAuth", "requireAndVerify")
+	config.Set("server.tls.clientCA", "resources/test_file.txt")
+	defer func() {
+		config.Set("server.tls.clientAuth", "none")
+		config.Set("server.tls.clientCA", nil)
+	}()
+	suite.Panics(func() {
+		config.Set("server.tls.clientAuth", "unknown")
+	})
+
+	err := Start(func(router *Router) {})
+	if suite.NotNil(err) {
+		suite.Equal(ExitHTTPError, err.(*Error).ExitCode)
+	}
+}
+
+func (suite *TLSTestSuite) TestInvalidCertificate() {
+	suite.Panics(func() {
+		config.Set("server.tls.certificates", []interface{}{map[string]interface{}{"cert": "cert.pem"}})
+	})
+	suite.Panics(func() {
+		config.Set("server.tls.certificates", []interface{}{"cert.pem"})
+	})
+
+	config.Set("server.tls.certificates", []interface{}{
+		map[string]interface{}{"host": "a.example.org", "cert": "notafile.crt", "key": "notafile.key"},
+	})
+	defer config.Set("server.tls.certificates", nil)
+
+	err := Start(func(router *Router) {})
+	if suite.NotNil(err) {
+		suite.Equal(ExitHTTPError, err.(*Error).ExitCode)
+	}
+	suite.False(IsReady())
+}
+
+func TestTLSTestSuite(t *testing.T) {
+	RunTest(t, new(TLSTestSuite))
+}
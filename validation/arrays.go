@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/url"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -39,7 +40,7 @@ func createArray(dataType string, length int) reflect.Value {
 	case "uuid":
 		newArray := make([]uuid.UUID, 0, length)
 		arr = reflect.ValueOf(&newArray).Elem()
-	case "bool":
+	case "bool", "boolean":
 		newArray := make([]bool, 0, length)
 		arr = reflect.ValueOf(&newArray).Elem()
 	case "date":
@@ -87,15 +88,43 @@ func validateArray(field string, value interface{}, parameters []string, form ma
 	return false
 }
 
+// validateDistinct checks that all the elements of the array are different.
+// The "ignore_case" parameter makes the comparison of strings case-insensitive.
+// Any other parameter is the name of the key used for the comparison when
+// the elements are objects ("distinct:email" for example). In this case,
+// elements without this key are ignored and elements that are not objects
+// make the validation fail.
 func validateDistinct(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	if GetFieldType(value) != "array" {
 		return false // Can't validate if not an array
 	}
 
+	ignoreCase := false
+	key := ""
+	for _, p := range parameters {
+		if p == "ignore_case" {
+			ignoreCase = true
+		} else {
+			key = p
+		}
+	}
+
 	found := []interface{}{}
 	list := reflect.ValueOf(value)
 	for i := 0; i < list.Len(); i++ {
 		v := list.Index(i).Interface()
+		if key != "" {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if v, ok = obj[key]; !ok {
+				continue
+			}
+		}
+		if str, ok := v.(string); ok && ignoreCase {
+			v = strings.ToLower(str)
+		}
 		if helper.Contains(found, v) {
 			return false
 		}
@@ -121,7 +150,8 @@ func validateIn(field string, value interface{}, parameters []string, form map[s
 	case "numeric":
 		return checkInNumeric(parameters, value)
 	case "string":
-		return helper.Contains(parameters, value)
+		// Named string types are accepted too
+		return helper.ContainsStr(parameters, reflect.ValueOf(value).String())
 	}
 	// Don't check arrays and files
 	return false
@@ -132,7 +162,7 @@ func validateNotIn(field string, value interface{}, parameters []string, form ma
 	case "numeric":
 		return !checkInNumeric(parameters, value)
 	case "string":
-		return !helper.ContainsStr(parameters, value.(string))
+		return !helper.ContainsStr(parameters, reflect.ValueOf(value).String())
 	}
 	// Don't check arrays and files
 	return false
@@ -141,7 +171,7 @@ func validateNotIn(field string, value interface{}, parameters []string, form ma
 func validateInArray(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	_, other, _, exists := GetFieldFromName(parameters[0], form)
 	if exists && GetFieldType(other) == "array" {
-		return helper.Contains(other, value)
+		return helper.ContainsEqual(other, value)
 	}
 	return false
 }
@@ -149,7 +179,7 @@ func validateInArray(field string, value interface{}, parameters []string, form
 func validateNotInArray(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	_, other, _, exists := GetFieldFromName(parameters[0], form)
 	if exists && GetFieldType(other) == "array" {
-		return !helper.Contains(other, value)
+		return !helper.ContainsEqual(other, value)
 	}
 	return false
 }
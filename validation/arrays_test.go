@@ -164,6 +164,32 @@ func TestValidateDistinct(t *testing.T) {
 	assert.False(t, validateDistinct("field", "string", []string{}, map[string]interface{}{}))
 }
 
+func TestValidateDistinctIgnoreCase(t *testing.T) {
+	assert.True(t, validateDistinct("field", []string{"test", "Test"}, []string{}, map[string]interface{}{}))
+	assert.False(t, validateDistinct("field", []string{"test", "Test"}, []string{"ignore_case"}, map[string]interface{}{}))
+	assert.False(t, validateDistinct("field", []interface{}{"ÉTÉ", 2, "été"}, []string{"ignore_case"}, map[string]interface{}{}))
+	assert.True(t, validateDistinct("field", []interface{}{"test", 2, "test2"}, []string{"ignore_case"}, map[string]interface{}{}))
+}
+
+func TestValidateDistinctByKey(t *testing.T) {
+	users := []interface{}{
+		map[string]interface{}{"email": "a@example.org", "name": "John"},
+		map[string]interface{}{"email": "b@example.org", "name": "John"},
+		map[string]interface{}{"name": "Jane"},
+		map[string]interface{}{"name": "Jim"},
+	}
+	assert.True(t, validateDistinct("field", users, []string{"email"}, map[string]interface{}{}))
+	assert.False(t, validateDistinct("field", users, []string{"name"}, map[string]interface{}{}))
+
+	users = append(users, map[string]interface{}{"email": "A@example.org"})
+	assert.True(t, validateDistinct("field", users, []string{"email"}, map[string]interface{}{}))
+	assert.False(t, validateDistinct("field", users, []string{"email", "ignore_case"}, map[string]interface{}{}))
+	assert.False(t, validateDistinct("field", users, []string{"ignore_case", "email"}, map[string]interface{}{}))
+
+	// Not objects
+	assert.False(t, validateDistinct("field", []string{"a", "b"}, []string{"email"}, map[string]interface{}{}))
+}
+
 func TestValidateIn(t *testing.T) {
 	assert.True(t, validateIn("field", "dolor", []string{"lorem", "ipsum", "sit", "dolor", "amet"}, map[string]interface{}{}))
 	assert.False(t, validateIn("field", "dolors", []string{"lorem", "ipsum", "sit", "dolor", "amet"}, map[string]interface{}{}))
@@ -194,6 +220,12 @@ func TestValidateNotIn(t *testing.T) {
 
 	assert.False(t, validateNotIn("field", []string{"1"}, []string{"1", "2.4", "2.65", "87", "2.5"}, map[string]interface{}{}))
 
+	type status string
+	assert.False(t, validateNotIn("field", status("dolor"), []string{"lorem", "dolor"}, map[string]interface{}{}))
+	assert.True(t, validateNotIn("field", status("amet"), []string{"lorem", "dolor"}, map[string]interface{}{}))
+	assert.True(t, validateIn("field", status("dolor"), []string{"lorem", "dolor"}, map[string]interface{}{}))
+	assert.False(t, validateIn("field", status("amet"), []string{"lorem", "dolor"}, map[string]interface{}{}))
+
 	assert.Panics(t, func() {
 		field := &Field{
 			Rules: []*Rule{
@@ -236,6 +268,12 @@ func TestValidateInArray(t *testing.T) {
 	}
 	assert.True(t, validateInArray("object.field", "dolor", []string{"object.other"}, data))
 	assert.False(t, validateInArray("object.field", "dolors", []string{"object.other"}, data))
+
+	// Non-comparable values
+	objects := []interface{}{map[string]interface{}{"id": 1.0}, map[string]interface{}{"id": 2.0}}
+	assert.True(t, validateInArray("field", map[string]interface{}{"id": 2.0}, []string{"other"}, map[string]interface{}{"other": objects}))
+	assert.False(t, validateInArray("field", map[string]interface{}{"id": 3.0}, []string{"other"}, map[string]interface{}{"other": objects}))
+	assert.True(t, validateNotInArray("field", map[string]interface{}{"id": 3.0}, []string{"other"}, map[string]interface{}{"other": objects}))
 }
 
 func TestValidateNotInArray(t *testing.T) {
@@ -17,7 +17,34 @@ func parseDate(date interface{}, format string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("Date is not a string so cannot be parsed")
 }
 
+// dateParameterLayouts the layouts accepted for dates given as rule
+// parameters, if no layout is specified.
+var dateParameterLayouts = []string{"2006-01-02T15:04:05", "2006-01-02"}
+
+// parseDateParameter parses a date given as rule parameter, using
+// the given layout or trying each of the "dateParameterLayouts" if empty.
+func parseDateParameter(param string, layout string) (time.Time, error) {
+	if layout != "" {
+		return time.Parse(layout, param)
+	}
+	var err error
+	for _, l := range dateParameterLayouts {
+		var t time.Time
+		if t, err = time.Parse(l, param); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
 func getDates(value interface{}, parameters []string, form map[string]interface{}) ([]time.Time, error) {
+	return getDatesWithLayout(value, parameters, form, "")
+}
+
+// getDatesWithLayout works like "getDates" but parses the dates given as
+// parameters and the non-converted dates of the compared fields using
+// the given layout. If the layout is empty, the default layouts are used.
+func getDatesWithLayout(value interface{}, parameters []string, form map[string]interface{}, layout string) ([]time.Time, error) {
 	dates := []time.Time{}
 	date, ok := value.(time.Time)
 	if ok {
@@ -27,7 +54,11 @@ func getDates(value interface{}, parameters []string, form map[string]interface{
 			if exists {
 				otherDate, ok := other.(time.Time)
 				if !ok {
-					t, err := parseDate(other, "2006-01-02")
+					otherLayout := layout
+					if otherLayout == "" {
+						otherLayout = "2006-01-02"
+					}
+					t, err := parseDate(other, otherLayout)
 					if err != nil {
 						return dates, fmt.Errorf("Cannot parse date in other field")
 					}
@@ -38,7 +69,7 @@ func getDates(value interface{}, parameters []string, form map[string]interface{
 			}
 
 			// TODO v4: avoid reparsing the date every single time
-			t, err := parseDate(param, "2006-01-02T15:04:05")
+			t, err := parseDateParameter(param, layout)
 			if err != nil {
 				panic(err)
 			}
@@ -91,7 +122,16 @@ func validateDateEquals(field string, value interface{}, parameters []string, fo
 	return err == nil && dates[0].Equal(dates[1])
 }
 
+// validateDateBetween checks the date is between the two given dates, inclusive.
+//  date_between:min,max[,layout]
+// The bounds can be dates or the names of other fields. The optional layout
+// is used to parse the bounds and the compared fields that are not dates yet.
 func validateDateBetween(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
-	dates, err := getDates(value, parameters, form)
+	layout := ""
+	if len(parameters) >= 3 {
+		layout = parameters[2]
+		parameters = parameters[:2]
+	}
+	dates, err := getDatesWithLayout(value, parameters, form, layout)
 	return err == nil && (dates[0].After(dates[1]) || dates[0].Equal(dates[1])) && (dates[0].Before(dates[2]) || dates[0].Equal(dates[2]))
 }
@@ -286,6 +286,36 @@ func TestValidateDateBetween(t *testing.T) {
 	assert.True(t, validateDateBetween("field", data["field"], []string{"object.min", "object.max"}, data))
 }
 
+func TestValidateDateBetweenLayout(t *testing.T) {
+	// Bounds without time
+	params := []string{"2024-01-01", "2024-12-31"}
+	assert.True(t, validateDateBetween("field", createDate("2024-06-15"), params, map[string]interface{}{}))
+	assert.True(t, validateDateBetween("field", createDate("2024-01-01"), params, map[string]interface{}{}))
+	assert.True(t, validateDateBetween("field", createDate("2024-12-31"), params, map[string]interface{}{}))
+	assert.False(t, validateDateBetween("field", createDate("2023-12-31"), params, map[string]interface{}{}))
+	assert.False(t, validateDateBetween("field", createDate("2025-01-01"), params, map[string]interface{}{}))
+
+	// Custom layout
+	params = []string{"01/01/2024", "31/12/2024", "02/01/2006"}
+	assert.True(t, validateDateBetween("field", createDate("2024-06-15"), params, map[string]interface{}{}))
+	assert.True(t, validateDateBetween("field", createDate("2024-12-31"), params, map[string]interface{}{}))
+	assert.False(t, validateDateBetween("field", createDate("2025-01-01"), params, map[string]interface{}{}))
+	assert.Panics(t, func() {
+		validateDateBetween("field", createDate("2024-06-15"), []string{"2024-01-01", "2024-12-31", "02/01/2006"}, map[string]interface{}{})
+	})
+
+	// Cross-field with custom layout
+	form := map[string]interface{}{"start": "01/06/2024", "end": "30/06/2024"}
+	params = []string{"start", "end", "02/01/2006"}
+	assert.True(t, validateDateBetween("field", createDate("2024-06-01"), params, form))
+	assert.True(t, validateDateBetween("field", createDate("2024-06-30"), params, form))
+	assert.False(t, validateDateBetween("field", createDate("2024-07-01"), params, form))
+	assert.False(t, validateDateBetween("field", createDate("2024-06-15"), []string{"start", "end"}, form)) // Wrong layout
+
+	// Other rules accept dates without time too
+	assert.True(t, validateBefore("field", createDate("2024-06-15"), []string{"2024-06-16"}, map[string]interface{}{}))
+}
+
 func TestValidateDateConvert(t *testing.T) {
 	form := map[string]interface{}{"field": "2019-11-02"}
 	assert.True(t, validateDate("field", form["field"], []string{}, form))
@@ -39,11 +39,8 @@ func validateExtension(field string, value interface{}, parameters []string, for
 	files, ok := value.([]filesystem.File)
 	if ok {
 		for _, file := range files {
-			if i := strings.LastIndex(file.Header.Filename, "."); i != -1 {
-				if !helper.ContainsStr(parameters, file.Header.Filename[i+1:]) {
-					return false
-				}
-			} else {
+			i := strings.LastIndex(file.Header.Filename, ".")
+			if i == -1 || !containsExtension(parameters, file.Header.Filename[i+1:]) {
 				return false
 			}
 		}
@@ -52,6 +49,18 @@ func validateExtension(field string, value interface{}, parameters []string, for
 	return false
 }
 
+// containsExtension returns true if the given extension is in the list of
+// allowed extensions. The comparison is case-insensitive and the allowed
+// extensions can be written with or without leading dot.
+func containsExtension(allowed []string, extension string) bool {
+	for _, e := range allowed {
+		if strings.EqualFold(strings.TrimPrefix(e, "."), extension) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateCount(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	files, ok := value.([]filesystem.File)
 	size, err := strconv.Atoi(parameters[0])
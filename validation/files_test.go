@@ -65,11 +65,15 @@ func createTestFiles(files ...string) []filesystem.File {
 }
 
 func createTestFileWithNoExtension() []filesystem.File {
-	_, filename, _, _ := runtime.Caller(1)
+	return createTestFileWithName("noextension")
+}
+
+func createTestFileWithName(name string) []filesystem.File {
+	_, filename, _, _ := runtime.Caller(0)
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	addFileToRequest(writer, path.Dir(filename)+"/../"+logoPath, "file", "noextension")
+	addFileToRequest(writer, path.Dir(filename)+"/../"+logoPath, "file", name)
 	err := writer.Close()
 	if err != nil {
 		panic(err)
@@ -129,6 +133,14 @@ func TestValidateExtension(t *testing.T) {
 	assert.False(t, validateExtension("file", createTestFileWithNoExtension(), []string{"png"}, map[string]interface{}{}))
 	assert.False(t, validateExtension("file", "test", []string{"png"}, map[string]interface{}{}))
 
+	// Case-insensitive
+	assert.True(t, validateExtension("file", createTestFileWithName("LOGO.PNG"), []string{"png", "jpg", "pdf"}, map[string]interface{}{}))
+	assert.True(t, validateExtension("file", createTestFileWithName("logo.png"), []string{"PNG"}, map[string]interface{}{}))
+	assert.True(t, validateExtension("file", createTestFileWithName("logo.png"), []string{".png"}, map[string]interface{}{}))
+	assert.False(t, validateExtension("file", createTestFileWithName("setup.exe"), []string{"png", "jpg", "pdf"}, map[string]interface{}{}))
+	assert.False(t, validateExtension("file", createTestFileWithName("logo.png.exe"), []string{"png", "jpg", "pdf"}, map[string]interface{}{}))
+	assert.False(t, validateExtension("file", createTestFileWithName("logo."), []string{"png"}, map[string]interface{}{}))
+
 	assert.Panics(t, func() {
 		field := &Field{
 			Rules: []*Rule{
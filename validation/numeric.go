@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"encoding/json"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -23,8 +25,8 @@ func validateNumeric(field string, value interface{}, parameters []string, form
 		}
 		return ok
 	case kind == "string":
-		floatVal, err := strconv.ParseFloat(value.(string), 64)
-		ok := err == nil
+		floatVal, err := strconv.ParseFloat(rv.String(), 64) // Also handles json.Number
+		ok := err == nil && !math.IsNaN(floatVal) && !math.IsInf(floatVal, 0)
 		if ok {
 			parent[fieldName] = floatVal
 		}
@@ -34,17 +36,36 @@ func validateNumeric(field string, value interface{}, parameters []string, form
 	}
 }
 
+// isInteger returns true if the given float has no fractional part.
+// NaN and infinities are not integers.
+func isInteger(val float64) bool {
+	return !math.IsNaN(val) && !math.IsInf(val, 0) && val == math.Trunc(val)
+}
+
 func validateInteger(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	rv := reflect.ValueOf(value)
 	kind := rv.Kind().String()
 	fieldName, _, parent, _ := GetFieldFromName(field, form)
+	if number, ok := value.(json.Number); ok {
+		// Converting directly to int keeps the precision of large integers.
+		if intVal, err := number.Int64(); err == nil {
+			parent[fieldName] = int(intVal)
+			return true
+		}
+		val, err := number.Float64()
+		if err != nil || !isInteger(val) {
+			return false
+		}
+		parent[fieldName] = int(val)
+		return true
+	}
 	switch {
 	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint") && kind != "uintptr":
 		return true
 	case strings.HasPrefix(kind, "float"):
 		if kind == "float64" {
 			val, _ := value.(float64)
-			if val-float64(int(val)) > 0 {
+			if !isInteger(val) {
 				return false
 			}
 			parent[fieldName] = int(val)
@@ -52,7 +73,7 @@ func validateInteger(field string, value interface{}, parameters []string, form
 		}
 
 		val, _ := value.(float32)
-		if val-float32(int(val)) > 0 {
+		if !isInteger(float64(val)) {
 			return false
 		}
 		parent[fieldName] = int(val)
@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,6 +23,8 @@ func TestValidateNumeric(t *testing.T) {
 	assert.False(t, validateNumeric("field", []string{}, []string{}, map[string]interface{}{"field": []string{}}))
 	assert.False(t, validateNumeric("field", map[string]string{}, []string{}, map[string]interface{}{"field": map[string]string{}}))
 	assert.False(t, validateNumeric("field", "test", []string{}, map[string]interface{}{"field": "test"}))
+	assert.True(t, validateNumeric("field", json.Number("1.5"), []string{}, map[string]interface{}{"field": json.Number("1.5")}))
+	assert.False(t, validateNumeric("field", json.Number("test"), []string{}, map[string]interface{}{"field": json.Number("test")}))
 }
 
 func TestValidateNumericConvertString(t *testing.T) {
@@ -35,6 +39,10 @@ func TestValidateNumericConvertString(t *testing.T) {
 	form3 := map[string]interface{}{"field": "2"}
 	validateNumeric("field", form3["field"], []string{}, form3)
 	assert.Equal(t, float64(2), form3["field"])
+
+	form4 := map[string]interface{}{"field": json.Number("1.5")}
+	validateNumeric("field", form4["field"], []string{}, form4)
+	assert.Equal(t, 1.5, form4["field"])
 }
 
 func TestValidateNumericConvertInObject(t *testing.T) {
@@ -72,6 +80,10 @@ func TestValidateInteger(t *testing.T) {
 	assert.False(t, validateInteger("field", []string{}, []string{}, map[string]interface{}{"field": []string{}}))
 	assert.False(t, validateInteger("field", map[string]string{}, []string{}, map[string]interface{}{"field": map[string]string{}}))
 	assert.False(t, validateInteger("field", "test", []string{}, map[string]interface{}{"field": "test"}))
+	assert.True(t, validateInteger("field", json.Number("2"), []string{}, map[string]interface{}{"field": json.Number("2")}))
+	assert.True(t, validateInteger("field", json.Number("2.0"), []string{}, map[string]interface{}{"field": json.Number("2.0")}))
+	assert.False(t, validateInteger("field", json.Number("2.5"), []string{}, map[string]interface{}{"field": json.Number("2.5")}))
+	assert.False(t, validateInteger("field", json.Number("test"), []string{}, map[string]interface{}{"field": json.Number("test")}))
 }
 
 func TestValidateIntegerConvert(t *testing.T) {
@@ -86,6 +98,10 @@ func TestValidateIntegerConvert(t *testing.T) {
 	form3 := map[string]interface{}{"field": float64(3)}
 	validateInteger("field", form3["field"], []string{}, form3)
 	assert.Equal(t, 3, form3["field"])
+
+	form4 := map[string]interface{}{"field": json.Number("9007199254740993")}
+	validateInteger("field", form4["field"], []string{}, form4)
+	assert.Equal(t, 9007199254740993, form4["field"])
 }
 
 func TestValidateIntegerConvertInObject(t *testing.T) {
@@ -105,3 +121,54 @@ func TestValidateIntegerConvertInObject(t *testing.T) {
 	_, ok := data["object"].(map[string]interface{})["integer"].(int)
 	assert.True(t, ok)
 }
+
+func TestValidateNumericRejectsSpecialValues(t *testing.T) {
+	assert.False(t, validateNumeric("field", "NaN", []string{}, map[string]interface{}{"field": "NaN"}))
+	assert.False(t, validateNumeric("field", "Inf", []string{}, map[string]interface{}{"field": "Inf"}))
+	assert.False(t, validateNumeric("field", "-Inf", []string{}, map[string]interface{}{"field": "-Inf"}))
+	assert.False(t, validateNumeric("field", "12abc", []string{}, map[string]interface{}{"field": "12abc"}))
+}
+
+func TestValidateIntegerRejectsFractions(t *testing.T) {
+	assert.False(t, validateInteger("field", -1.5, []string{}, map[string]interface{}{"field": -1.5}))
+	assert.False(t, validateInteger("field", float32(-2.5), []string{}, map[string]interface{}{"field": float32(-2.5)}))
+	assert.False(t, validateInteger("field", "1.5", []string{}, map[string]interface{}{"field": "1.5"}))
+	assert.False(t, validateInteger("field", "12abc", []string{}, map[string]interface{}{"field": "12abc"}))
+	assert.True(t, validateInteger("field", -3.0, []string{}, map[string]interface{}{"field": -3.0}))
+}
+
+func TestValidateIntegerRejectsNaNInf(t *testing.T) {
+	for _, value := range []interface{}{math.Inf(1), math.Inf(-1), math.NaN(), float32(math.Inf(1)), float32(math.NaN())} {
+		form := map[string]interface{}{"field": value}
+		assert.False(t, validateInteger("field", value, []string{}, form), value)
+		assert.IsType(t, value, form["field"])
+	}
+}
+
+func (suite *ValidatorTestSuite) TestValidateNumericCoercionFromForm() {
+	data := map[string]interface{}{
+		"price":    "12.5",
+		"quantity": "3",
+	}
+	set := RuleSet{
+		"price":    {"required", "numeric", "min:10"},
+		"quantity": {"required", "integer", "lower_than:price"},
+	}
+	errors := Validate(data, set, false, "en-US")
+	suite.Empty(errors)
+	suite.Equal(12.5, data["price"])
+	suite.Equal(3, data["quantity"])
+
+	data = map[string]interface{}{
+		"price":    "12abc",
+		"quantity": "1.5",
+	}
+	errors = Validate(data, RuleSet{
+		"price":    {"required", "numeric"},
+		"quantity": {"required", "integer"},
+	}, false, "en-US")
+	suite.Equal([]string{"The price must be numeric."}, errors["price"])
+	suite.Equal([]string{"The quantity must be an integer."}, errors["quantity"])
+	suite.Equal("12abc", data["price"])
+	suite.Equal("1.5", data["quantity"])
+}
@@ -3,7 +3,6 @@ package validation
 import (
 	"sort"
 	"strings"
-	"time"
 
 	"goyave.dev/goyave/v3/lang"
 )
@@ -12,8 +11,15 @@ import (
 // This function should return the value to replace the placeholder with.
 type Placeholder func(string, string, []string, string) string
 
+// ValueLabeler function converting a raw rule parameter value into a
+// human-readable label, for the given field, rule and language.
+// This is used by the ":values" placeholder, for example to display
+// localized labels for the options of the "in" rule.
+type ValueLabeler func(field string, rule string, value string, language string) string
+
 var placeholders map[string]Placeholder = map[string]Placeholder{}
 var sortedKeys []string = []string{}
+var valueLabeler ValueLabeler = defaultValueLabeler
 
 // SetPlaceholder sets the replacer function for the given placeholder.
 // If a placeholder with this name already exists, the latter will be overridden.
@@ -30,6 +36,26 @@ func SetPlaceholder(placeholderName string, replacer Placeholder) {
 	sort.Sort(sort.Reverse(sort.StringSlice(sortedKeys)))
 }
 
+// SetValueLabeler sets the function converting raw rule parameter values
+// into display labels in the ":values" placeholder. Setting it to nil
+// restores the default behavior, which is to display the raw values.
+//  validation.SetValueLabeler(func(field string, rule string, value string, language string) string {
+//  	if rule != "in" {
+//  		return value
+//  	}
+//  	return lang.Get(language, field+"."+value) // e.g. "status.draft"
+//  })
+func SetValueLabeler(labeler ValueLabeler) {
+	if labeler == nil {
+		labeler = defaultValueLabeler
+	}
+	valueLabeler = labeler
+}
+
+func defaultValueLabeler(field string, rule string, value string, language string) string {
+	return value
+}
+
 func processPlaceholders(field string, rule string, params []string, message string, language string) string {
 	if i := strings.LastIndex(field, "."); i != -1 {
 		field = field[i+1:]
@@ -56,8 +82,12 @@ func simpleParameterPlaceholder(field string, rule string, parameters []string,
 	return parameters[0]
 }
 
-func datePlaceholder(index int, parameters []string, language string) string {
-	_, err := time.Parse("2006-01-02T15:04:05", parameters[index])
+func datePlaceholder(index int, rule string, parameters []string, language string) string {
+	layout := ""
+	if rule == "date_between" && len(parameters) >= 3 {
+		layout = parameters[2]
+	}
+	_, err := parseDateParameter(parameters[index], layout)
 	if err != nil {
 		// Not a date, may be a field
 		return replaceField(parameters[index], language)
@@ -82,7 +112,11 @@ func init() {
 		return replaceField(parameters[0], language)
 	})
 	SetPlaceholder("values", func(field string, rule string, parameters []string, language string) string {
-		return strings.Join(parameters, ", ")
+		labels := make([]string, 0, len(parameters))
+		for _, p := range parameters {
+			labels = append(labels, valueLabeler(field, rule, p, language))
+		}
+		return strings.Join(labels, ", ")
 	})
 	SetPlaceholder("version", func(field string, rule string, parameters []string, language string) string {
 		if len(parameters) > 0 {
@@ -91,9 +125,9 @@ func init() {
 		return ""
 	})
 	SetPlaceholder("date", func(field string, rule string, parameters []string, language string) string {
-		return datePlaceholder(0, parameters, language)
+		return datePlaceholder(0, rule, parameters, language)
 	})
 	SetPlaceholder("max_date", func(field string, rule string, parameters []string, language string) string {
-		return datePlaceholder(1, parameters, language)
+		return datePlaceholder(1, rule, parameters, language)
 	})
 }
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -37,6 +38,9 @@ func (suite *PlaceholderTestSuite) TestPlaceholders() {
 	suite.Equal("email address", placeholders[":date"]("field", "date", []string{"email"}, "en-US"))
 	suite.Equal("2019-11-02T17:00:00", placeholders[":date"]("field", "date", []string{"2019-11-02T17:00:00"}, "en-US"))
 	suite.Equal("2019-11-03T17:00:00", placeholders[":max_date"]("field", "date", []string{"2019-11-02T17:00:00", "2019-11-03T17:00:00"}, "en-US"))
+	suite.Equal("2024-12-31", placeholders[":max_date"]("field", "date_between", []string{"2024-01-01", "2024-12-31"}, "en-US"))
+	suite.Equal("31/12/2024", placeholders[":max_date"]("field", "date_between", []string{"01/01/2024", "31/12/2024", "02/01/2006"}, "en-US"))
+	suite.Equal("email address", placeholders[":max_date"]("field", "date_between", []string{"01/01/2024", "email", "02/01/2006"}, "en-US"))
 }
 
 func (suite *PlaceholderTestSuite) TestProcessPlaceholders() {
@@ -47,6 +51,27 @@ func (suite *PlaceholderTestSuite) TestProcessPlaceholders() {
 	suite.Equal("The image must have exactly 2 file(s).", processPlaceholders("image", "count", []string{"2"}, "The :field must have exactly :value file(s).", "en-US"))
 }
 
+func (suite *PlaceholderTestSuite) TestValueLabeler() {
+	SetValueLabeler(func(field string, rule string, value string, language string) string {
+		if rule != "in" {
+			return value
+		}
+		return field + "." + strings.ToUpper(value) + "@" + language
+	})
+	defer SetValueLabeler(nil)
+
+	suite.Equal("status.DRAFT@en-US, status.PUBLISHED@en-US", placeholders[":values"]("status", "in", []string{"draft", "published"}, "en-US"))
+	suite.Equal("ppm, png", placeholders[":values"]("image", "extension", []string{"ppm", "png"}, "en-US"))
+
+	errors := Validate(map[string]interface{}{"status": "archived"}, RuleSet{
+		"status": {"required", "string", "in:draft,published"},
+	}, true, "en-US")
+	suite.Equal([]string{"The status must have one of the following values: status.DRAFT@en-US, status.PUBLISHED@en-US."}, errors["status"])
+
+	SetValueLabeler(nil)
+	suite.Equal("draft, published", placeholders[":values"]("status", "in", []string{"draft", "published"}, "en-US"))
+}
+
 func TestPlaceholderTestSuite(t *testing.T) {
 	suite.Run(t, new(PlaceholderTestSuite))
 }
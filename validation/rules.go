@@ -243,6 +243,12 @@ func validateLowerThanEqual(field string, value interface{}, parameters []string
 	return true // Pass if field type cannot be checked (bool, dates, ...)
 }
 
+// validateBool accepts actual booleans as well as their most common
+// representations, and converts the value to a "bool":
+//  - true: 1, "1", "on", "true", "yes"
+//  - false: 0, "0", "off", "false", "no"
+// Numeric representations can be integers or floats, such as the
+// numbers decoded from JSON bodies.
 func validateBool(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	rv := reflect.ValueOf(value)
 	kind := rv.Kind().String()
@@ -250,7 +256,7 @@ func validateBool(field string, value interface{}, parameters []string, form map
 	switch {
 	case kind == "bool":
 		return true
-	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint") && kind != "uintptr":
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint") && kind != "uintptr", strings.HasPrefix(kind, "float"):
 		v, _ := helper.ToFloat64(value)
 		if v == 1 {
 			parent[fieldName] = true
@@ -336,7 +342,20 @@ func validateSize(field string, value interface{}, parameters []string, form map
 	return true // Pass if field type cannot be checked (bool, dates, ...)
 }
 
+// validateObject checks the value is an object. If parameters are given,
+// the object must not contain keys other than the ones listed.
+// The values of the keys can be validated as nested fields ("object.key").
 func validateObject(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
-	_, ok := value.(map[string]interface{})
-	return ok
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if len(parameters) > 0 {
+		for key := range object {
+			if !helper.ContainsStr(parameters, key) {
+				return false
+			}
+		}
+	}
+	return true
 }
@@ -414,8 +414,13 @@ func TestValidateBool(t *testing.T) {
 	assert.True(t, validateBool("field", true, []string{}, data))
 	assert.True(t, validateBool("field", false, []string{}, data))
 
-	assert.False(t, validateBool("field", 0.0, []string{}, data))
-	assert.False(t, validateBool("field", 1.0, []string{}, data))
+	assert.True(t, validateBool("field", 0.0, []string{}, data))
+	assert.True(t, validateBool("field", 1.0, []string{}, data))
+	assert.True(t, validateBool("field", float32(1), []string{}, data))
+
+	assert.False(t, validateBool("field", 0.5, []string{}, data))
+	assert.False(t, validateBool("field", 2.0, []string{}, data))
+	assert.False(t, validateBool("field", "maybe", []string{}, data))
 	assert.False(t, validateBool("field", []string{"true"}, []string{}, data))
 	assert.False(t, validateBool("field", -1, []string{}, data))
 }
@@ -464,6 +469,34 @@ func TestValidateBoolConvertInObject(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func (suite *ValidatorTestSuite) TestValidateBoolean() {
+	accepted := map[interface{}]bool{
+		true: true, false: false,
+		1: true, 0: false,
+		1.0: true, 0.0: false,
+		"1": true, "0": false,
+		"true": true, "false": false,
+		"yes": true, "no": false,
+		"on": true, "off": false,
+	}
+	for value, expected := range accepted {
+		data := map[string]interface{}{"field": value}
+		errors := Validate(data, RuleSet{"field": {"required", "boolean"}}, false, "en-US")
+		suite.Empty(errors, value)
+		suite.Equal(expected, data["field"], value)
+	}
+
+	data := map[string]interface{}{"field": "maybe"}
+	errors := Validate(data, RuleSet{"field": {"required", "boolean"}}, false, "en-US")
+	suite.Equal([]string{"The field must be a boolean."}, errors["field"])
+	suite.Equal("maybe", data["field"])
+
+	data = map[string]interface{}{"field": []interface{}{"on", 0}}
+	errors = Validate(data, RuleSet{"field": {"required", "array:boolean"}}, true, "en-US")
+	suite.Empty(errors)
+	suite.Equal([]bool{true, false}, data["field"])
+}
+
 func TestValidateSame(t *testing.T) {
 	assert.True(t, validateSame("field", "password", []string{"other"}, map[string]interface{}{"field": "password", "other": "password"}))
 	assert.True(t, validateSame("field", 1, []string{"other"}, map[string]interface{}{"field": 1, "other": 1}))
@@ -580,4 +613,11 @@ func TestValidateSize(t *testing.T) {
 func TestValidateObject(t *testing.T) {
 	assert.False(t, validateObject("field", "123", []string{}, map[string]interface{}{}))
 	assert.True(t, validateObject("field", map[string]interface{}{"hello": "world"}, []string{}, map[string]interface{}{}))
+	assert.False(t, validateObject("field", []interface{}{"hello", "world"}, []string{}, map[string]interface{}{}))
+	assert.False(t, validateObject("field", nil, []string{}, map[string]interface{}{}))
+
+	// Allowed keys
+	assert.True(t, validateObject("field", map[string]interface{}{"hello": "world"}, []string{"hello", "foo"}, map[string]interface{}{}))
+	assert.True(t, validateObject("field", map[string]interface{}{}, []string{"hello"}, map[string]interface{}{}))
+	assert.False(t, validateObject("field", map[string]interface{}{"hello": "world", "bar": 1}, []string{"hello", "foo"}, map[string]interface{}{}))
 }
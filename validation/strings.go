@@ -122,7 +122,7 @@ func validateRegex(field string, value interface{}, parameters []string, form ma
 
 func validateTimezone(field string, value interface{}, parameters []string, form map[string]interface{}) bool {
 	tz, ok := value.(string)
-	if ok && tz != "Local" {
+	if ok && tz != "" { // time.LoadLocation returns UTC for empty strings
 		loc, err := time.LoadLocation(tz)
 		if err == nil {
 			fieldName, _, parent, _ := GetFieldFromName(field, form)
@@ -137,6 +137,9 @@ func TestValidateTimezone(t *testing.T) {
 	assert.True(t, validateTimezone("field", "Europe/Paris", []string{}, data))
 	assert.True(t, validateTimezone("field", "America/St_Thomas", []string{}, data))
 	assert.True(t, validateTimezone("field", "GMT", []string{}, data))
+	assert.True(t, validateTimezone("field", "Local", []string{}, data))
+	assert.False(t, validateTimezone("field", "", []string{}, data))
+	assert.False(t, validateTimezone("field", "Europe/Atlantis", []string{}, data))
 	assert.False(t, validateTimezone("field", "GMT+2", []string{}, data))
 	assert.False(t, validateTimezone("field", "UTC+2", []string{}, data))
 	assert.False(t, validateTimezone("field", "here", []string{}, data))
@@ -172,6 +175,20 @@ func TestValidateTimezoneConvertInObject(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func (suite *ValidatorTestSuite) TestValidateTimezoneMessage() {
+	data := map[string]interface{}{
+		"zone": "Mars/Olympus_Mons",
+	}
+	errors := Validate(data, RuleSet{"zone": {"required", "timezone"}}, true, "en-US")
+	suite.Equal([]string{"The zone must be a valid time zone."}, errors["zone"])
+
+	data = map[string]interface{}{
+		"zone": 2,
+	}
+	errors = Validate(data, RuleSet{"zone": {"required", "timezone"}}, true, "en-US")
+	suite.Equal([]string{"The zone must be a valid time zone."}, errors["zone"])
+}
+
 func TestValidateIP(t *testing.T) {
 	data := map[string]interface{}{
 		"field": "127.0.0.1",
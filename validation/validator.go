@@ -1,12 +1,14 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
-	"goyave.dev/goyave/v3/helper"
+	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/lang"
 )
 
@@ -51,8 +53,8 @@ type RuleDefinition struct {
 
 	// ComparesFields is true when the rule compares the value of the field under
 	// validation with another field. A field containing at least one rule with
-	// ComparesFields = true will be executed later in the validation process to
-	// ensure conversions are properly executed prior.
+	// ComparesFields = true will be validated after the fields it is compared with
+	// to ensure conversions are properly executed prior.
 	ComparesFields bool
 }
 
@@ -66,6 +68,37 @@ func (r RuleSet) AsRules() *Rules {
 	return r.parse()
 }
 
+// Messages custom validation error messages, by field name then by rule name.
+// Rules used in array validation are prefixed by ">", like in a RuleSet.
+type Messages map[string]map[string]string
+
+// WithMessages parses and checks this RuleSet and returns it as Rules, with
+// the given messages overriding the localized messages for specific fields and rules.
+// Custom messages support the same placeholders as the localized messages.
+// Panics if a message targets a field or a rule that is not part of this RuleSet.
+//
+//  rules := validation.RuleSet{
+//  	"work_email": {"required", "string", "email"},
+//  }.WithMessages(validation.Messages{
+//  	"work_email": {"email": "Please enter a valid work email."},
+//  })
+func (r RuleSet) WithMessages(messages Messages) *Rules {
+	rules := r.parse()
+	for fieldName, fieldMessages := range messages {
+		field, ok := rules.Fields[fieldName]
+		if !ok {
+			panic(fmt.Sprintf("Cannot set message for field \"%s\": field is not validated", fieldName))
+		}
+		for ruleName := range fieldMessages {
+			if !field.hasRule(ruleName) {
+				panic(fmt.Sprintf("Cannot set message for rule \"%s\" of field \"%s\": rule is not used", ruleName, fieldName))
+			}
+		}
+		field.Messages = fieldMessages
+	}
+	return rules
+}
+
 // Parse converts the more convenient RuleSet validation rules syntax to
 // a Rules map.
 func (r RuleSet) parse() *Rules {
@@ -97,6 +130,11 @@ type Rule struct {
 	ArrayDimension uint8
 }
 
+// key returns the name of the rule prefixed by ">" for each array dimension.
+func (r *Rule) key() string {
+	return strings.Repeat(">", int(r.ArrayDimension)) + r.Name
+}
+
 // IsType returns true if the rule definition is a type rule.
 // See RuleDefinition.IsType
 func (r *Rule) IsType() bool {
@@ -126,7 +164,13 @@ func (r *Rule) IsTypeDependent() bool {
 // Field is a component of route validation. A Field is a value in
 // a Rules map, the key being the name of the field.
 type Field struct {
-	Rules      []*Rule
+	Rules []*Rule
+
+	// Messages custom error messages overriding the localized ones, by
+	// rule name. Rules used in array validation are prefixed by ">",
+	// like in a RuleSet. Placeholders are supported.
+	Messages map[string]string
+
 	isArray    bool
 	isRequired bool
 	isNullable bool
@@ -147,6 +191,26 @@ func (f *Field) IsArray() bool {
 	return f.isArray
 }
 
+// hasRule returns true if the field has a rule with the given name.
+// The name is prefixed by ">" for each array dimension.
+func (f *Field) hasRule(name string) bool {
+	for _, rule := range f.Rules {
+		if rule.key() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getMessage returns the custom message defined for the given rule,
+// or the localized message.
+func (f *Field) getMessage(rule *Rule, value reflect.Value, language string) string {
+	if message, ok := f.Messages[rule.key()]; ok {
+		return message
+	}
+	return getMessage(f.Rules, rule, value, language)
+}
+
 // check if rules meet the minimum parameters requirement and update
 // the isRequired, isNullable and isArray fields.
 func (f *Field) check() {
@@ -182,8 +246,29 @@ type FieldMap map[string]*Field
 
 // Rules is a component of route validation and maps a
 // field name (key) with a Field struct (value).
+//
+// This is the structured equivalent of RuleSet, which is parsed into Rules.
+// Because rule parameters are not delimited by commas, it can express
+// parameters containing commas or colons:
+//
+//  rules := &validation.Rules{
+//  	Fields: validation.FieldMap{
+//  		"separator": {
+//  			Rules: []*validation.Rule{
+//  				{Name: "required"},
+//  				{Name: "in", Params: []string{",", ":"}},
+//  			},
+//  		},
+//  	},
+//  }
 type Rules struct {
-	Fields     FieldMap
+	Fields FieldMap
+
+	// StrictFields makes the validation fail if the data contains
+	// fields that are not present in this rule set.
+	// Can be enabled globally with the "validation.strictFields" config entry.
+	StrictFields bool
+
 	sortedKeys []string
 	checked    bool
 }
@@ -212,24 +297,96 @@ func (r *Rules) check() {
 	}
 }
 
+// sortKeys defines the order in which the fields are validated.
+// Fields are sorted alphabetically, so parent objects are validated before
+// their children, then each field is moved after the fields it is compared
+// with (rules with ComparesFields = true), so conversions applied to the
+// compared fields are visible when the comparison is executed.
+// Circular dependencies are ignored: the alphabetical order prevails.
 func (r *Rules) sortKeys() {
-	r.sortedKeys = make([]string, 0, len(r.Fields))
-
+	keys := make([]string, 0, len(r.Fields))
 	for k := range r.Fields {
-		r.sortedKeys = append(r.sortedKeys, k)
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	r.sortedKeys = make([]string, 0, len(keys))
+	visited := make(map[string]bool, len(keys))
+	var visit func(string)
+	visit = func(fieldName string) {
+		if visited[fieldName] {
+			return
+		}
+		visited[fieldName] = true
+		for _, dependency := range r.fieldDependencies(fieldName) {
+			visit(dependency)
+		}
+		r.sortedKeys = append(r.sortedKeys, fieldName)
 	}
+	for _, k := range keys {
+		visit(k)
+	}
+}
 
-	sort.SliceStable(r.sortedKeys, func(i, j int) bool {
-		fieldName1 := r.sortedKeys[i]
-		field2 := r.Fields[r.sortedKeys[j]]
-		for _, r := range field2.Rules {
-			def, ok := validationRules[r.Name]
-			if ok && def.ComparesFields && helper.ContainsStr(r.Params, fieldName1) {
-				return true
+// fieldDependencies returns the sorted names of the fields the given field
+// is compared with.
+func (r *Rules) fieldDependencies(fieldName string) []string {
+	dependencies := []string{}
+	for _, rule := range r.Fields[fieldName].Rules {
+		def, ok := validationRules[rule.Name]
+		if !ok || !def.ComparesFields {
+			continue
+		}
+		for _, param := range rule.Params {
+			if _, exists := r.Fields[param]; exists && param != fieldName {
+				dependencies = append(dependencies, param)
 			}
 		}
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+// findNonValidatedFields returns the path of the fields of the given data
+// that are not present in the rule set. Objects whose fields are validated
+// individually are checked recursively.
+// The "<field>_confirmation" fields are ignored if "<field>" has
+// the "confirmed" rule.
+func (r *Rules) findNonValidatedFields(data map[string]interface{}, prefix string) []string {
+	nonValidated := []string{}
+	for key, value := range data {
+		path := prefix + key
+		if r.isConfirmation(path) {
+			continue
+		}
+
+		validated := false
+		isParent := false
+		for fieldName := range r.Fields {
+			if fieldName == path {
+				validated = true
+			} else if strings.HasPrefix(fieldName, path+".") {
+				isParent = true
+			}
+		}
+
+		if obj, ok := value.(map[string]interface{}); ok && isParent {
+			nonValidated = append(nonValidated, r.findNonValidatedFields(obj, path+".")...)
+		} else if !validated {
+			nonValidated = append(nonValidated, path)
+		}
+	}
+	return nonValidated
+}
+
+// isConfirmation returns true if the given path is the confirmation
+// of a field having the "confirmed" rule.
+func (r *Rules) isConfirmation(path string) bool {
+	if !strings.HasSuffix(path, "_confirmation") {
 		return false
-	})
+	}
+	field, ok := r.Fields[strings.TrimSuffix(path, "_confirmation")]
+	return ok && field.hasRule("confirmed")
 }
 
 // Errors is a map of validation errors with the field name as a key.
@@ -238,6 +395,13 @@ type Errors map[string][]string
 var validationRules map[string]*RuleDefinition
 
 func init() {
+	config.Register("validation.strictFields", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []interface{}{},
+	})
+
 	validationRules = map[string]*RuleDefinition{
 		"required":           {validateRequired, 0, false, false, false},
 		"numeric":            {validateNumeric, 0, true, false, false},
@@ -273,6 +437,7 @@ func init() {
 		"url":                {validateURL, 0, true, false, false},
 		"uuid":               {validateUUID, 0, true, false, false},
 		"bool":               {validateBool, 0, true, false, false},
+		"boolean":            {validateBool, 0, true, false, false},
 		"same":               {validateSame, 1, false, false, true},
 		"different":          {validateDifferent, 1, false, false, true},
 		"confirmed":          {validateConfirmed, 0, false, false, false},
@@ -313,6 +478,11 @@ func AddRule(name string, rule *RuleDefinition) {
 // If all validation rules pass, returns an empty "validation.Errors".
 // Third parameter tells the function if the data comes from a JSON request.
 // Last parameter sets the language of the validation error messages.
+//
+// Fields are validated in a deterministic order: alphabetically, except that
+// a field is always validated after the fields it is compared with (using
+// rules such as "greater_than" or "same"). This way, a "numeric" conversion
+// on field "a" is visible to a "greater_than:a" rule on field "b".
 func Validate(data map[string]interface{}, rules Ruler, isJSON bool, language string) Errors {
 	if data == nil {
 		var malformedMessage string
@@ -327,9 +497,51 @@ func Validate(data map[string]interface{}, rules Ruler, isJSON bool, language st
 	return validate(data, isJSON, rules.AsRules(), language)
 }
 
+// ValidateArray validate each element of the given array with the given rule set.
+// This is useful when the whole body of the request is an array of objects,
+// for example when importing records in bulk:
+//
+//  var records []interface{}
+//  if err := json.Unmarshal(request.RawBody(), &records); err != nil {
+//  	// Malformed request
+//  }
+//  errors := validation.ValidateArray(records, rules, true, request.Lang)
+//
+// The errors are keyed by the index of the element, followed by the name
+// of the field: "0.email", "1.name". Elements which are not objects
+// fail with the "object" rule message, keyed by their index only.
+// If all validation rules pass, returns an empty "validation.Errors".
+func ValidateArray(data []interface{}, rules Ruler, isJSON bool, language string) Errors {
+	if data == nil {
+		return Validate(nil, rules, isJSON, language)
+	}
+
+	r := rules.AsRules()
+	errors := Errors{}
+	for i, element := range data {
+		index := strconv.Itoa(i)
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			errors[index] = append(errors[index], processPlaceholders(index, "object", nil, lang.Get(language, "validation.rules.object"), language))
+			continue
+		}
+		for field, messages := range validate(object, isJSON, r, language) {
+			errors[index+"."+field] = messages
+		}
+	}
+	return errors
+}
+
 func validate(data map[string]interface{}, isJSON bool, rules *Rules, language string) Errors {
 	errors := Errors{}
 
+	if rules.StrictFields || (config.IsLoaded() && config.GetBool("validation.strictFields")) {
+		message := lang.Get(language, "disallow-non-validated-fields")
+		for _, field := range rules.findNonValidatedFields(data, "") {
+			errors[field] = append(errors[field], message)
+		}
+	}
+
 	for _, fieldName := range rules.sortedKeys {
 		field := rules.Fields[fieldName]
 		name, fieldVal, parent, _ := GetFieldFromName(fieldName, data)
@@ -341,14 +553,17 @@ func validate(data map[string]interface{}, isJSON bool, rules *Rules, language s
 			continue
 		}
 
+		if field.IsNullable() && fieldVal == nil {
+			// Present but null values skip the whole chain, regardless
+			// of the position of the "nullable" rule.
+			continue
+		}
+
 		convertArray(isJSON, name, field, parent) // Convert single value arrays in url-encoded requests
 
 		for _, rule := range field.Rules {
 			fieldVal = parent[name]
 			if rule.Name == "nullable" {
-				if fieldVal == nil {
-					break
-				}
 				continue
 			}
 
@@ -356,13 +571,13 @@ func validate(data map[string]interface{}, isJSON bool, rules *Rules, language s
 				if ok, errorValue := validateRuleInArray(rule, fieldName, rule.ArrayDimension, data); !ok {
 					errors[fieldName] = append(
 						errors[fieldName],
-						processPlaceholders(fieldName, rule.Name, rule.Params, getMessage(field.Rules, rule, errorValue, language), language),
+						processPlaceholders(fieldName, rule.Name, rule.Params, field.getMessage(rule, errorValue, language), language),
 					)
 				}
 			} else if !validationRules[rule.Name].Function(fieldName, fieldVal, rule.Params, data) {
 				errors[fieldName] = append(
 					errors[fieldName],
-					processPlaceholders(fieldName, rule.Name, rule.Params, getMessage(field.Rules, rule, reflect.ValueOf(fieldVal), language), language),
+					processPlaceholders(fieldName, rule.Name, rule.Params, field.getMessage(rule, reflect.ValueOf(fieldVal), language), language),
 				)
 			}
 		}
@@ -460,7 +675,7 @@ func findTypeRule(rules []*Rule, arrayDimension uint8) string {
 // GetFieldType returns the non-technical type of the given "value" interface.
 // This is used by validation rules to know if the input data is a candidate
 // for validation or not and is especially useful for type-dependent rules.
-//  - "numeric" if the value is an int, uint or a float
+//  - "numeric" if the value is an int, uint, a float or a "json.Number"
 //  - "string" if the value is a string
 //  - "array" if the value is a slice
 //  - "file" if the value is a slice of "filesystem.File"
@@ -469,9 +684,13 @@ func GetFieldType(value interface{}) string {
 	return getFieldType(reflect.ValueOf(value))
 }
 
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
 func getFieldType(value reflect.Value) string {
 	kind := value.Kind().String()
 	switch {
+	case value.IsValid() && value.Type() == jsonNumberType:
+		return "numeric"
 	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint") && kind != "uintptr", strings.HasPrefix(kind, "float"):
 		return "numeric"
 	case kind == "string":
@@ -509,6 +728,8 @@ func GetFieldFromName(name string, data map[string]interface{}) (string, interfa
 		if obj, ok := val.(map[string]interface{}); ok {
 			return GetFieldFromName(name[len(key)+1:], obj)
 		}
+		// Parent is not an object, the nested field cannot exist
+		return "", nil, nil, false
 	}
 
 	return name, val, data, ok
@@ -1,10 +1,12 @@
 package validation
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"goyave.dev/goyave/v3/config"
 	"goyave.dev/goyave/v3/helper"
 	"goyave.dev/goyave/v3/helper/filesystem"
 	"goyave.dev/goyave/v3/lang"
@@ -195,6 +197,43 @@ func (suite *ValidatorTestSuite) TestValidate() {
 	suite.Equal("The text is required.", errors["text"][0])
 }
 
+func (suite *ValidatorTestSuite) TestValidateNullable() {
+	data := map[string]interface{}{
+		"nickname": nil,
+		"bio":      nil,
+	}
+	errors := Validate(data, RuleSet{
+		"nickname": {"nullable", "string"},
+		"bio":      {"string", "min:3", "nullable"},
+	}, true, "en-US")
+	suite.Empty(errors)
+	suite.Contains(data, "nickname")
+	suite.Contains(data, "bio")
+	suite.Nil(data["nickname"])
+	suite.Nil(data["bio"])
+
+	data = map[string]interface{}{
+		"nickname": 42,
+		"bio":      "ab",
+	}
+	errors = Validate(data, RuleSet{
+		"nickname": {"nullable", "string"},
+		"bio":      {"string", "min:3", "nullable"},
+	}, true, "en-US")
+	suite.Equal([]string{"The nickname must be a string."}, errors["nickname"])
+	suite.Equal([]string{"The bio must be at least 3 characters."}, errors["bio"])
+
+	// Without nullable, null values are removed and rules are not applied
+	// unless the field is required.
+	data = map[string]interface{}{
+		"nickname": nil,
+	}
+	errors = Validate(data, RuleSet{
+		"nickname": {"required", "string"},
+	}, true, "en-US")
+	suite.Equal([]string{"The nickname is required.", "The nickname must be a string."}, errors["nickname"])
+}
+
 func (suite *ValidatorTestSuite) TestValidateWithArray() {
 	data := map[string]interface{}{
 		"string": "hello",
@@ -707,6 +746,64 @@ func (suite *ValidatorTestSuite) TestAsRules() {
 	})
 }
 
+func (suite *ValidatorTestSuite) TestStructuredRulesEquivalence() {
+	ruleSet := RuleSet{
+		"email": {"required", "string", "email"},
+		"role":  {"required", "in:admin,user"},
+		"tags":  {"array:string", ">min:2"},
+	}
+	rules := &Rules{
+		Fields: FieldMap{
+			"email": {
+				Rules: []*Rule{
+					{Name: "required", Params: []string{}},
+					{Name: "string", Params: []string{}},
+					{Name: "email", Params: []string{}},
+				},
+			},
+			"role": {
+				Rules: []*Rule{
+					{Name: "required", Params: []string{}},
+					{Name: "in", Params: []string{"admin", "user"}},
+				},
+			},
+			"tags": {
+				Rules: []*Rule{
+					{Name: "array", Params: []string{"string"}},
+					{Name: "min", Params: []string{"2"}, ArrayDimension: 1},
+				},
+			},
+		},
+	}
+	suite.Equal(ruleSet.AsRules(), rules.AsRules())
+
+	newData := func() map[string]interface{} {
+		return map[string]interface{}{
+			"email": "not an email",
+			"role":  "guest",
+			"tags":  []interface{}{"a", "abc"},
+		}
+	}
+	errors := Validate(newData(), ruleSet, true, "en-US")
+	suite.Len(errors, 3)
+	suite.Equal(errors, Validate(newData(), rules, true, "en-US"))
+
+	// Parameters containing delimiters
+	separator := &Rules{
+		Fields: FieldMap{
+			"separator": {
+				Rules: []*Rule{
+					{Name: "required"},
+					{Name: "in", Params: []string{",", ":"}},
+				},
+			},
+		},
+	}
+	suite.Empty(Validate(map[string]interface{}{"separator": ","}, separator, true, "en-US"))
+	suite.Empty(Validate(map[string]interface{}{"separator": ":"}, separator, true, "en-US"))
+	suite.Len(Validate(map[string]interface{}{"separator": ";"}, separator, true, "en-US"), 1)
+}
+
 func (suite *ValidatorTestSuite) TestRulesCheck() {
 	rules := &Rules{
 		Fields: FieldMap{
@@ -738,6 +835,7 @@ func (suite *ValidatorTestSuite) TestGetFieldType() {
 	suite.Equal("numeric", getFieldType(reflect.ValueOf(1.1)))
 	suite.Equal("numeric", getFieldType(reflect.ValueOf(uint(1))))
 	suite.Equal("numeric", getFieldType(reflect.ValueOf(float32(1))))
+	suite.Equal("numeric", getFieldType(reflect.ValueOf(json.Number("9007199254740993"))))
 	suite.Equal("string", getFieldType(reflect.ValueOf("hello")))
 	suite.Equal("array", getFieldType(reflect.ValueOf([]string{"hello", "world"})))
 	suite.Equal("file", getFieldType(reflect.ValueOf([]filesystem.File{})))
@@ -792,6 +890,12 @@ func (suite *ValidatorTestSuite) TestGetFieldFromName() {
 	suite.Nil(val)
 	suite.Nil(parent)
 	suite.False(ok)
+
+	name, val, parent, ok = GetFieldFromName("notobject.key", data)
+	suite.Empty(name)
+	suite.Nil(val)
+	suite.Nil(parent)
+	suite.False(ok)
 }
 
 func (suite *ValidatorTestSuite) TestTypeDependentAfterConversion() {
@@ -988,6 +1092,50 @@ func (suite *ValidatorTestSuite) TestSortKeysWithNullable() {
 	rules.AsRules().sortKeys()
 }
 
+func (suite *ValidatorTestSuite) TestSortKeysDeterministic() {
+	rules := RuleSet{
+		"a":        {"numeric", "greater_than:b"},
+		"b":        {"numeric", "greater_than:c"},
+		"c":        {"numeric"},
+		"d":        {"string"},
+		"user":     {"object"},
+		"user.age": {"numeric", "lower_than:a"},
+	}
+	for i := 0; i < 20; i++ {
+		suite.Equal([]string{"c", "b", "a", "d", "user", "user.age"}, rules.AsRules().sortedKeys)
+	}
+
+	// Circular dependencies don't cause infinite loops.
+	circular := RuleSet{
+		"a": {"numeric", "greater_than:b"},
+		"b": {"numeric", "lower_than:a"},
+	}
+	suite.Equal([]string{"b", "a"}, circular.AsRules().sortedKeys)
+}
+
+func (suite *ValidatorTestSuite) TestValidateComparedFieldsConversion() {
+	// The comparison rules require both fields to have the same type.
+	// Without ordering guarantees, "b" could be compared with "c"
+	// before "c" is converted to a number.
+	rules := RuleSet{
+		"a": {"required", "numeric", "greater_than:b"},
+		"b": {"required", "numeric", "greater_than:c"},
+		"c": {"required", "numeric"},
+	}
+	for i := 0; i < 20; i++ {
+		data := map[string]interface{}{
+			"a": "3",
+			"b": "2",
+			"c": "1",
+		}
+		errors := Validate(data, rules, false, "en-US")
+		suite.Empty(errors)
+		suite.Equal(3.0, data["a"])
+		suite.Equal(2.0, data["b"])
+		suite.Equal(1.0, data["c"])
+	}
+}
+
 func (suite *ValidatorTestSuite) testSortKeysWithRule(rule string) {
 	rules := &Rules{
 		Fields: map[string]*Field{
@@ -1004,6 +1152,230 @@ func (suite *ValidatorTestSuite) testSortKeysWithRule(rule string) {
 	suite.Equal([]string{"two", "one"}, rules.sortedKeys)
 }
 
+func (suite *ValidatorTestSuite) TestValidateArrayRules() {
+	rules := RuleSet{
+		"tags":   {"required", "array:string", "max:3", ">min:2"},
+		"scores": {"array:numeric", "size:2", ">max:10"},
+	}
+
+	data := map[string]interface{}{
+		"tags":   []interface{}{"go", "web"},
+		"scores": []interface{}{"1.5", "2"},
+	}
+	suite.Empty(Validate(data, rules, false, "en-US"))
+	suite.Equal([]string{"go", "web"}, data["tags"])
+	suite.Equal([]float64{1.5, 2}, data["scores"])
+
+	// Typed array
+	data = map[string]interface{}{
+		"tags":   []interface{}{"go", 2},
+		"scores": []interface{}{1, "two"},
+	}
+	errors := Validate(data, rules, true, "en-US")
+	suite.Equal([]string{"The tags must be an array."}, errors["tags"])
+	suite.Equal([]string{"The scores must be an array."}, errors["scores"])
+
+	// Too large array
+	data = map[string]interface{}{
+		"tags":   []interface{}{"go", "web", "api", "orm"},
+		"scores": []interface{}{1.0, 2.0, 3.0},
+	}
+	errors = Validate(data, rules, true, "en-US")
+	suite.Equal([]string{"The tags may not have more than 3 items."}, errors["tags"])
+	suite.Equal([]string{"The scores must contain exactly 2 items."}, errors["scores"])
+
+	// Per-element validation
+	data = map[string]interface{}{
+		"tags":   []interface{}{"go", "a"},
+		"scores": []interface{}{1.0, 2.0},
+	}
+	errors = Validate(data, rules, true, "en-US")
+	suite.Equal([]string{"The tags values must be at least 2 characters."}, errors["tags"])
+
+	data = map[string]interface{}{
+		"tags":   []interface{}{"go"},
+		"scores": []interface{}{1.0, 20.0},
+	}
+	errors = Validate(data, rules, true, "en-US")
+	suite.Equal([]string{"The scores values may not be greater than 10."}, errors["scores"])
+}
+
+func (suite *ValidatorTestSuite) TestValidateInMessage() {
+	errors := Validate(map[string]interface{}{"status": "archived"}, RuleSet{
+		"status": {"required", "string", "in:draft,published"},
+	}, true, "en-US")
+	suite.Equal([]string{"The status must have one of the following values: draft, published."}, errors["status"])
+}
+
+func (suite *ValidatorTestSuite) TestValidateStrictFields() {
+	rules := RuleSet{
+		"name":       {"required", "string"},
+		"password":   {"required", "string", "confirmed"},
+		"user":       {"required", "object"},
+		"user.email": {"required", "email"},
+		"tags":       {"array", ">string"},
+	}
+	newData := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name":                  "John",
+			"password":              "secret",
+			"password_confirmation": "secret",
+			"user": map[string]interface{}{
+				"email": "john@example.org",
+				"admin": true,
+			},
+			"tags":  []interface{}{"a", "b"},
+			"extra": 1,
+		}
+	}
+
+	errors := Validate(newData(), rules, true, "en-US")
+	suite.Empty(errors)
+
+	strict := rules.AsRules()
+	strict.StrictFields = true
+	errors = Validate(newData(), strict, true, "en-US")
+	suite.Len(errors, 2)
+	suite.Equal([]string{"Non-validated fields are forbidden."}, errors["extra"])
+	suite.Equal([]string{"Non-validated fields are forbidden."}, errors["user.admin"])
+
+	data := newData()
+	delete(data, "extra")
+	delete(data["user"].(map[string]interface{}), "admin")
+	suite.Empty(Validate(data, strict, true, "en-US"))
+
+	// Only the confirmation of fields having the "confirmed" rule is allowed
+	data["name_confirmation"] = "John"
+	data["is_admin_confirmation"] = true
+	errors = Validate(data, strict, true, "en-US")
+	suite.Len(errors, 2)
+	suite.Equal([]string{"Non-validated fields are forbidden."}, errors["name_confirmation"])
+	suite.Equal([]string{"Non-validated fields are forbidden."}, errors["is_admin_confirmation"])
+}
+
+func (suite *ValidatorTestSuite) TestValidateStrictFieldsConfig() {
+	if err := config.LoadFrom("../config.test.json"); err != nil {
+		suite.FailNow(err.Error())
+	}
+	defer config.Clear()
+	config.Set("validation.strictFields", true)
+
+	data := map[string]interface{}{
+		"name":  "John",
+		"extra": 1,
+	}
+	errors := Validate(data, RuleSet{"name": {"string"}}, true, "en-US")
+	suite.Equal([]string{"Non-validated fields are forbidden."}, errors["extra"])
+
+	config.Set("validation.strictFields", false)
+	suite.Empty(Validate(data, RuleSet{"name": {"string"}}, true, "en-US"))
+}
+
+func (suite *ValidatorTestSuite) TestValidateArrayPayload() {
+	rules := RuleSet{
+		"name":  {"required", "string"},
+		"email": {"required", "email"},
+		"age":   {"integer"},
+	}
+	data := []interface{}{
+		map[string]interface{}{"name": "John", "email": "john@example.org", "age": 20.0},
+		map[string]interface{}{"name": 1, "email": "not an email"},
+		map[string]interface{}{"name": "Jane", "email": "jane@example.org"},
+	}
+	errors := ValidateArray(data, rules, true, "en-US")
+	suite.Equal(Errors{
+		"1.name":  {"The name must be a string."},
+		"1.email": {"The email address must be a valid email address."},
+	}, errors)
+	suite.Equal(20, data[0].(map[string]interface{})["age"]) // Converted
+
+	suite.Empty(ValidateArray([]interface{}{}, rules, true, "en-US"))
+
+	errors = ValidateArray([]interface{}{"string", map[string]interface{}{"name": "John", "email": "john@example.org"}}, rules, true, "en-US")
+	suite.Equal(Errors{"0": {"The 0 must be an object."}}, errors)
+
+	errors = ValidateArray(nil, rules, true, "en-US")
+	suite.Equal(Errors{"error": {"Malformed JSON"}}, errors)
+}
+
+func (suite *ValidatorTestSuite) TestValidateCustomMessages() {
+	rules := RuleSet{
+		"work_email":     {"required", "string", "email"},
+		"personal_email": {"required", "string", "email"},
+		"name":           {"required", "string", "min:3"},
+		"tags":           {"array", ">string", ">max:2"},
+	}.WithMessages(Messages{
+		"work_email": {"email": "Please enter a valid work email."},
+		"name":       {"min": "The :field needs :min characters or more."},
+		"tags":       {">max": "Tags are limited to :max characters."},
+	})
+
+	data := map[string]interface{}{
+		"work_email":     "not an email",
+		"personal_email": "not an email",
+		"name":           "Jo",
+		"tags":           []interface{}{"ok", "too long"},
+	}
+	errors := Validate(data, rules, true, "en-US")
+	suite.Equal(Errors{
+		"work_email":     {"Please enter a valid work email."},
+		"personal_email": {"The personal_email must be a valid email address."},
+		"name":           {"The name needs 3 characters or more."},
+		"tags":           {"Tags are limited to 2 characters."},
+	}, errors)
+
+	suite.Panics(func() {
+		RuleSet{"name": {"required"}}.WithMessages(Messages{"other": {"required": "message"}})
+	})
+	suite.Panics(func() {
+		RuleSet{"name": {"required"}}.WithMessages(Messages{"name": {"string": "message"}})
+	})
+	suite.Panics(func() {
+		RuleSet{"tags": {"array", ">string"}}.WithMessages(Messages{"tags": {"string": "message"}})
+	})
+}
+
+func (suite *ValidatorTestSuite) TestValidateObject() {
+	rules := RuleSet{
+		"options":       {"required", "object:color,size"},
+		"options.color": {"required", "string", "in:red,blue"},
+		"options.size":  {"numeric", "min:1"},
+	}
+
+	data := map[string]interface{}{
+		"options": map[string]interface{}{"color": "red", "size": 2.0},
+	}
+	suite.Empty(Validate(data, rules, true, "en-US"))
+
+	// Array mistakenly provided
+	data = map[string]interface{}{
+		"options": []interface{}{"red", 2.0},
+	}
+	errors := Validate(data, rules, true, "en-US")
+	suite.Equal([]string{"The options must be an object."}, errors["options"])
+	suite.Equal([]string{
+		"The color is required.",
+		"The color must be a string.",
+		"The color must have one of the following values: red, blue.",
+	}, errors["options.color"])
+	suite.NotContains(errors, "options.size")
+
+	// Nested keys
+	data = map[string]interface{}{
+		"options": map[string]interface{}{"color": "green", "size": 0.0},
+	}
+	suite.Equal(Errors{
+		"options.color": {"The color must have one of the following values: red, blue."},
+		"options.size":  {"The size must be at least 1."},
+	}, Validate(data, rules, true, "en-US"))
+
+	// Unknown key
+	data = map[string]interface{}{
+		"options": map[string]interface{}{"color": "red", "weight": 3.0},
+	}
+	suite.Equal(Errors{"options": {"The options must be an object."}}, Validate(data, rules, true, "en-US"))
+}
+
 func TestValidatorTestSuite(t *testing.T) {
 	suite.Run(t, new(ValidatorTestSuite))
 }
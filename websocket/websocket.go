@@ -222,9 +222,9 @@ func (u *Upgrader) serve(c *ws.Conn, request *goyave.Request, handler Handler) {
 			if u.ErrorHandler != nil {
 				u.ErrorHandler(request, err)
 			} else {
-				goyave.ErrLogger.Println(err)
+				goyave.GetLogger().Errorf("%v", err)
 				if e, ok := err.(*PanicError); ok && e.Stacktrace != "" {
-					goyave.ErrLogger.Println(e.Stacktrace)
+					goyave.GetLogger().Errorf("%s", e.Stacktrace)
 				}
 			}
 			conn.CloseWithError(err)
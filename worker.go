@@ -0,0 +1,93 @@
+package goyave
+
+import (
+	"context"
+	"sync"
+)
+
+// workerGroup the goroutines managed by the server during one run.
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var (
+	workers        *workerGroup
+	pendingWorkers []func(context.Context)
+	workersMutex   sync.Mutex
+)
+
+// Go launches a goroutine managed by the server, for background jobs
+// such as cache warmers or queue consumers.
+//
+// If the server is not running yet, the goroutine is started once the server
+// is ready, alongside the startup hooks. The context given to the function
+// is cancelled when the server stops. The server then waits for all managed
+// goroutines to return before executing the shutdown hooks and closing the
+// database connections, up to the shutdown timeout.
+//
+//  goyave.Go(func(ctx context.Context) {
+//      ticker := time.NewTicker(time.Minute)
+//      defer ticker.Stop()
+//      for {
+//          select {
+//          case <-ctx.Done():
+//              return
+//          case <-ticker.C:
+//              warmCache()
+//          }
+//      }
+//  })
+func Go(worker func(context.Context)) {
+	workersMutex.Lock()
+	defer workersMutex.Unlock()
+	if workers == nil {
+		pendingWorkers = append(pendingWorkers, worker)
+		return
+	}
+	workers.launch(worker)
+}
+
+func (g *workerGroup) launch(worker func(context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		worker(g.ctx)
+	}()
+}
+
+func startWorkers() {
+	workersMutex.Lock()
+	defer workersMutex.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	workers = &workerGroup{ctx: ctx, cancel: cancel}
+	for _, worker := range pendingWorkers {
+		workers.launch(worker)
+	}
+	pendingWorkers = nil
+}
+
+// stopWorkers cancels the context of the managed goroutines and waits
+// for them to return, or until the given context is done.
+func stopWorkers(ctx context.Context) {
+	workersMutex.Lock()
+	g := workers
+	workers = nil
+	workersMutex.Unlock()
+	if g == nil {
+		return
+	}
+
+	g.cancel()
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		GetLogger().Warnf("Shutdown timeout exceeded while waiting for managed goroutines to return")
+	}
+}